@@ -0,0 +1,135 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"shortlink/internal/base/errno"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Prefix API Key 明文前缀，鉴权中间件据此区分 API Key 和登录 token
+const Prefix = "sk_"
+
+// LocalKey API Key 鉴权时写入 Locals 的 key
+const LocalKey = "api_key"
+
+// DefaultRateLimit 未指定时每分钟允许的请求次数
+const DefaultRateLimit = 60
+
+// MaxRateLimit 每分钟允许的请求次数上限，避免创建者通过指定很大的值绕过限流
+const MaxRateLimit = 600
+
+// Scope API Key 允许执行的操作
+type Scope string
+
+const (
+	ScopeLinkCreate Scope = "link:create"
+	ScopeLinkRead   Scope = "link:read"
+	ScopeLinkUpdate Scope = "link:update"
+)
+
+var scopes = []Scope{ScopeLinkCreate, ScopeLinkRead, ScopeLinkUpdate}
+
+// Key API Key 元数据，明文只在创建时返回一次，存储时只保存摘要
+type Key struct {
+	Id       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	// 明文的前几位，用于用户辨认
+	Hint   string  `json:"hint"`
+	Scopes []Scope `json:"scopes"`
+	// 允许访问的分组，为空时不限制
+	Gids []string `json:"gids"`
+	// 每分钟允许的请求次数
+	RateLimit int `json:"rate_limit"`
+	// 过期时间 0 表示永不过期
+	ExpireAt  int64 `json:"expire_at"`
+	CreatedAt int64 `json:"created_at"`
+
+	// 使用次数和最后使用时间，单独存储，查询时填充
+	UsageCount int64 `json:"-"`
+	LastUsedAt int64 `json:"-"`
+}
+
+// ValidateScopes 校验并去重权限范围，不能为空
+func ValidateScopes(in []Scope) ([]Scope, error) {
+	res := make([]Scope, 0, len(in))
+	for _, scope := range in {
+		if !slices.Contains(scopes, scope) {
+			return nil, errno.ApiKeyInvalidScope
+		}
+		if !slices.Contains(res, scope) {
+			res = append(res, scope)
+		}
+	}
+	if len(res) == 0 {
+		return nil, errno.ApiKeyInvalidScope
+	}
+	return res, nil
+}
+
+// ValidateRateLimit 未指定时使用默认值，不能为负数或超过上限
+func ValidateRateLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultRateLimit, nil
+	}
+	if limit < 0 || limit > MaxRateLimit {
+		return 0, errno.ApiKeyInvalidRateLimit
+	}
+	return limit, nil
+}
+
+func (k Key) Allow(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// AllowGroup 限制了分组时，未指定分组同样不允许
+func (k Key) AllowGroup(gid string) bool {
+	if len(k.Gids) == 0 {
+		return true
+	}
+	return gid != "" && slices.Contains(k.Gids, gid)
+}
+
+func (k Key) Expired(now time.Time) bool {
+	return k.ExpireAt > 0 && now.Unix() >= k.ExpireAt
+}
+
+// FromContext 当前请求使用 API Key 鉴权时返回对应的 Key
+func FromContext(ctx context.Context) (*Key, bool) {
+	key, ok := ctx.Value(LocalKey).(*Key)
+	return key, ok && key != nil
+}
+
+// CheckGroup 使用 API Key 访问时校验分组，其他鉴权方式直接通过
+func CheckGroup(ctx context.Context, gid string) error {
+	if key, ok := FromContext(ctx); ok && !key.AllowGroup(gid) {
+		return errno.ApiKeyGroupDenied
+	}
+	return nil
+}
+
+// Generate 生成 API Key 明文
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash 明文的摘要，明文本身是高熵随机数，不需要加盐
+func Hash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsApiKey 是否为 API Key 格式的 token
+func IsApiKey(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
@@ -0,0 +1,98 @@
+package apikey
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidateScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []Scope
+		want    []Scope
+		wantErr bool
+	}{
+		{"dedupe", []Scope{ScopeLinkCreate, ScopeLinkRead, ScopeLinkCreate}, []Scope{ScopeLinkCreate, ScopeLinkRead}, false},
+		{"empty", nil, nil, true},
+		{"unknown", []Scope{ScopeLinkRead, "stats:write"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateScopes(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, errno.ApiKeyInvalidScope) {
+					t.Fatalf("ValidateScopes() error = %v, want ApiKeyInvalidScope", err)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateScopes() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+		err   error
+	}{
+		{"default", 0, DefaultRateLimit, nil},
+		{"custom", 120, 120, nil},
+		{"max", MaxRateLimit, MaxRateLimit, nil},
+		{"above max", 1000000000, 0, errno.ApiKeyInvalidRateLimit},
+		{"negative", -1, 0, errno.ApiKeyInvalidRateLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateRateLimit(tt.limit)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Errorf("ValidateRateLimit(%d) = %d, %v, want %d, %v", tt.limit, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		key         Key
+		gid         string
+		wantGroup   bool
+		wantExpired bool
+	}{
+		{"unrestricted", Key{}, "", true, false},
+		{"allowed group", Key{Gids: []string{"g1", "g2"}}, "g2", true, false},
+		{"other group", Key{Gids: []string{"g1"}}, "g2", false, false},
+		{"default group", Key{Gids: []string{"g1"}}, "", false, false},
+		{"expired", Key{ExpireAt: now.Unix()}, "g1", true, true},
+		{"not expired", Key{ExpireAt: now.Add(time.Hour).Unix()}, "g1", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.AllowGroup(tt.gid); got != tt.wantGroup {
+				t.Errorf("AllowGroup(%q) = %v, want %v", tt.gid, got, tt.wantGroup)
+			}
+			if got := tt.key.Expired(now); got != tt.wantExpired {
+				t.Errorf("Expired() = %v, want %v", got, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	a, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Generate()
+	if !IsApiKey(a) || a == b || Hash(a) == Hash(b) {
+		t.Errorf("Generate() = %q, %q", a, b)
+	}
+}
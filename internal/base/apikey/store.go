@@ -0,0 +1,225 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/bytedance/sonic"
+	"github.com/redis/go-redis/v9"
+	"shortlink/internal/base/errno"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// KeyPrefix API Key hash key: short-link:apikey:{id}
+	//
+	//	field: key 元数据 hash 明文摘要 count 使用次数 last_used 最后使用时间
+	KeyPrefix = "short-link:apikey:"
+	// HashKeyPrefix 明文摘要到 id 的索引 string key: short-link:apikey-hash:{sha256}
+	HashKeyPrefix = "short-link:apikey-hash:"
+	// UserKeysPrefix 用户的所有 API Key set key: short-link:apikeys:{username}
+	UserKeysPrefix = "short-link:apikeys:"
+	// RateKeyPrefix 每分钟的请求计数 string key: short-link:apikey-rate:{id}:{minute}
+	RateKeyPrefix = "short-link:apikey-rate:"
+)
+
+// hintLength 展示的明文前缀长度，包含 sk_
+const hintLength = 8
+
+// CreateParams 创建 API Key 的参数
+type CreateParams struct {
+	Username  string
+	Name      string
+	Scopes    []Scope
+	Gids      []string
+	RateLimit int
+	// 为零值时永不过期
+	ExpireAt time.Time
+}
+
+// Store API Key 存储，与登录会话相同保存在 Redis 中
+type Store struct {
+	rdb *redis.Client
+}
+
+func NewStore(rdb *redis.Client) *Store {
+	if rdb == nil {
+		panic("nil rdb")
+	}
+	return &Store{rdb: rdb}
+}
+
+// Create 创建 API Key，返回的明文只有这一次机会获取
+func (s *Store) Create(ctx context.Context, params CreateParams) (string, Key, error) {
+	scopes, err := ValidateScopes(params.Scopes)
+	if err != nil {
+		return "", Key{}, err
+	}
+	rateLimit, err := ValidateRateLimit(params.RateLimit)
+	if err != nil {
+		return "", Key{}, err
+	}
+	plain, err := Generate()
+	if err != nil {
+		return "", Key{}, err
+	}
+	b := make([]byte, 8)
+	if _, err = rand.Read(b); err != nil {
+		return "", Key{}, err
+	}
+
+	now := time.Now()
+	key := Key{
+		Id:        hex.EncodeToString(b),
+		Username:  params.Username,
+		Name:      params.Name,
+		Hint:      plain[:hintLength],
+		Scopes:    scopes,
+		Gids:      params.Gids,
+		RateLimit: rateLimit,
+		CreatedAt: now.Unix(),
+	}
+	if !params.ExpireAt.IsZero() {
+		if !params.ExpireAt.After(now) {
+			return "", Key{}, errno.ApiKeyInvalidExpire
+		}
+		key.ExpireAt = params.ExpireAt.Unix()
+	}
+	value, err := sonic.MarshalString(&key)
+	if err != nil {
+		return "", Key{}, err
+	}
+
+	if _, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, KeyPrefix+key.Id, "key", value, "hash", Hash(plain))
+		pipe.Set(ctx, HashKeyPrefix+Hash(plain), key.Id, 0)
+		pipe.SAdd(ctx, UserKeysPrefix+key.Username, key.Id)
+		return nil
+	}); err != nil {
+		return "", Key{}, err
+	}
+	return plain, key, nil
+}
+
+// List 查询用户的所有 API Key，按创建时间倒序
+func (s *Store) List(ctx context.Context, username string) ([]Key, error) {
+	ids, err := s.rdb.SMembers(ctx, UserKeysPrefix+username).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Key, 0, len(ids))
+	for _, id := range ids {
+		key, err := s.get(ctx, id)
+		if err != nil {
+			if errors.Is(err, errno.ApiKeyNotExists) {
+				continue
+			}
+			return nil, err
+		}
+		res = append(res, *key)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt > res[j].CreatedAt
+	})
+	return res, nil
+}
+
+// Revoke 删除 API Key，只能删除自己的
+func (s *Store) Revoke(ctx context.Context, username, id string) error {
+	key, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.Username != username {
+		return errno.ApiKeyNotExists
+	}
+	hash, err := s.rdb.HGet(ctx, KeyPrefix+id, "hash").Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, KeyPrefix+id)
+		pipe.SRem(ctx, UserKeysPrefix+username, id)
+		if hash != "" {
+			pipe.Del(ctx, HashKeyPrefix+hash)
+		}
+		return nil
+	})
+	return err
+}
+
+// Authenticate 校验 API Key 明文，检查有效期和请求频率并记录使用情况
+func (s *Store) Authenticate(ctx context.Context, plain string) (*Key, error) {
+	if !IsApiKey(plain) {
+		return nil, errno.ApiKeyInvalid
+	}
+	id, err := s.rdb.Get(ctx, HashKeyPrefix+Hash(plain)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errno.ApiKeyInvalid
+		}
+		return nil, err
+	}
+	key, err := s.get(ctx, id)
+	if err != nil {
+		if errors.Is(err, errno.ApiKeyNotExists) {
+			return nil, errno.ApiKeyInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.Expired(now) {
+		return nil, errno.ApiKeyExpired
+	}
+
+	// 固定窗口计数，窗口过后自动过期
+	rateKey := RateKeyPrefix + id + ":" + strconv.FormatInt(now.Unix()/60, 10)
+	var incr *redis.IntCmd
+	if _, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, rateKey)
+		pipe.Expire(ctx, rateKey, 2*time.Minute)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if incr.Val() > int64(key.RateLimit) {
+		return nil, errno.ApiKeyRateLimited
+	}
+
+	if _, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, KeyPrefix+id, "count", 1)
+		pipe.HSet(ctx, KeyPrefix+id, "last_used", now.Unix())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	key.UsageCount++
+	key.LastUsedAt = now.Unix()
+	return key, nil
+}
+
+func (s *Store) get(ctx context.Context, id string) (*Key, error) {
+	values, err := s.rdb.HMGet(ctx, KeyPrefix+id, "key", "count", "last_used").Result()
+	if err != nil {
+		return nil, err
+	}
+	value, _ := values[0].(string)
+	if value == "" {
+		return nil, errno.ApiKeyNotExists
+	}
+	key := &Key{}
+	if err = sonic.UnmarshalString(value, key); err != nil {
+		return nil, err
+	}
+	if count, ok := values[1].(string); ok {
+		key.UsageCount, _ = strconv.ParseInt(count, 10, 64)
+	}
+	if lastUsed, ok := values[2].(string); ok {
+		key.LastUsedAt, _ = strconv.ParseInt(lastUsed, 10, 64)
+	}
+	return key, nil
+}
@@ -32,6 +32,9 @@ type Cache interface {
 	// HIncrBy increments the integer value of a hash field
 	HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error)
 
+	// Incr increments the integer value of a key, the expiration is only set when the key is created
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+
 	// GetInstance retrieves the cache instance
 	GetInstance() interface{}
 }
@@ -120,6 +120,18 @@ func (r RedisDistributedCache) HIncrBy(ctx context.Context, key, field string, i
 	return r.rdb.HIncrBy(ctx, key, field, incr).Result()
 }
 
+func (r RedisDistributedCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	// 自增与设置过期时间需要保证原子性，否则可能留下永不过期的计数器
+	luaScript := `
+        local count = redis.call("INCR", KEYS[1])
+        if count == 1 and tonumber(ARGV[1]) > 0 then
+            redis.call("PEXPIRE", KEYS[1], ARGV[1])
+        end
+        return count
+    `
+	return r.rdb.Eval(ctx, luaScript, []string{key}, expiration.Milliseconds()).Int64()
+}
+
 func (r RedisDistributedCache) SafeGet(
 	ctx context.Context,
 	key string,
@@ -70,8 +70,59 @@ type Config struct {
 		Username  string `mapstructure:"username"`
 		AuthToken string `mapstructure:"auth_token"`
 	} `mapstructure:"email"`
+
+	// Pagination 分页配置
+	Pagination struct {
+		// 游标签名密钥 多实例部署时需要保持一致
+		CursorSecret string `mapstructure:"cursor_secret"`
+	} `mapstructure:"pagination"`
+
+	// Auth 鉴权配置
+	Auth struct {
+		// 鉴权方式 session: Redis 登录会话 jwt: 访问令牌 + 刷新令牌
+		Mode string `mapstructure:"mode"`
+		Jwt  struct {
+			Issuer string `mapstructure:"issuer"`
+			// 访问令牌有效期（秒）
+			AccessTTL int `mapstructure:"access_ttl"`
+			// 刷新令牌有效期（秒）
+			RefreshTTL int `mapstructure:"refresh_ttl"`
+			// 签名密钥轮换间隔（秒）
+			KeyRotateInterval int `mapstructure:"key_rotate_interval"`
+		} `mapstructure:"jwt"`
+	} `mapstructure:"auth"`
+
+	// Password 密码存储和密码策略配置
+	Password struct {
+		// 哈希算法 argon2id 或 bcrypt
+		Algorithm string `mapstructure:"algorithm"`
+		MinLength int    `mapstructure:"min_length"`
+		MaxLength int    `mapstructure:"max_length"`
+		// 至少包含的字符类型数量 小写字母、大写字母、数字、特殊字符
+		MinClasses int `mapstructure:"min_classes"`
+	} `mapstructure:"password"`
+
+	// Account 邮箱验证和重置密码配置
+	Account struct {
+		// 邮件链接中令牌的签名密钥 为空时使用随机密钥，多实例部署时需要配置
+		TokenSecret string `mapstructure:"token_secret"`
+		// 邮箱验证链接有效期（秒）
+		VerifyTTL int `mapstructure:"verify_ttl"`
+		// 重置密码链接有效期（秒）
+		ResetTTL int `mapstructure:"reset_ttl"`
+		// 邮件中的链接地址，令牌通过 token 查询参数附加
+		VerifyUrl string `mapstructure:"verify_url"`
+		ResetUrl  string `mapstructure:"reset_url"`
+		// 邮箱未验证的用户不能创建短链接
+		RequireVerifiedMail bool `mapstructure:"require_verified_mail"`
+	} `mapstructure:"account"`
 }
 
+const (
+	AuthModeSession = "session"
+	AuthModeJwt     = "jwt"
+)
+
 const DefaultConfigName = "config"
 
 func initC(c ConfigInterface) {
@@ -87,6 +138,18 @@ func initC(c ConfigInterface) {
 
 	viper.SetDefault("server.max_requests", 1000)
 	viper.SetDefault("server.port", 8080)
+	viper.SetDefault("auth.mode", AuthModeSession)
+	viper.SetDefault("auth.jwt.issuer", "short-link")
+	viper.SetDefault("auth.jwt.access_ttl", 15*60)
+	viper.SetDefault("auth.jwt.refresh_ttl", 30*24*60*60)
+	viper.SetDefault("auth.jwt.key_rotate_interval", 24*60*60)
+	viper.SetDefault("password.algorithm", "argon2id")
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("password.max_length", 64)
+	viper.SetDefault("password.min_classes", 2)
+	viper.SetDefault("account.verify_ttl", 24*60*60)
+	viper.SetDefault("account.reset_ttl", 30*60)
+	viper.SetDefault("account.require_verified_mail", true)
 
 	viper.SetConfigName(configName)
 	viper.SetConfigType("toml")
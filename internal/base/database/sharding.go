@@ -9,34 +9,37 @@ import (
 	"shortlink/internal/base/toolkit"
 )
 
+// NumberOfShards 分片数量 需要遍历全部分片的场景直接拼接表名后缀 _0 ~ _15
+const NumberOfShards = 16
+
 func setupSharding(Db *gorm.DB) {
 
 	shardingByUsername := sharding.Register(sharding.Config{
 		ShardingKey:         "username",
-		NumberOfShards:      16,
+		NumberOfShards:      NumberOfShards,
 		PrimaryKeyGenerator: sharding.PKSnowflake,
 		ShardingAlgorithm:   hashModeShardingAlgorithm(),
-		ShardingSuffixs:     shardingSuffix(16),
+		ShardingSuffixs:     shardingSuffix(NumberOfShards),
 	}, "user", "group")
 
 	Db.Use(shardingByUsername)
 
 	shardingByGid := sharding.Register(sharding.Config{
 		ShardingKey:         "gid",
-		NumberOfShards:      16,
+		NumberOfShards:      NumberOfShards,
 		PrimaryKeyGenerator: sharding.PKSnowflake,
 		ShardingAlgorithm:   hashModeShardingAlgorithm(),
-		ShardingSuffixs:     shardingSuffix(16),
+		ShardingSuffixs:     shardingSuffix(NumberOfShards),
 	}, "link")
 
 	Db.Use(shardingByGid)
 
 	shardingByFullShortUrl := sharding.Register(sharding.Config{
-		ShardingKey:         "full_short_url",
-		NumberOfShards:      16,
+		ShardingKey:         "short_uri",
+		NumberOfShards:      NumberOfShards,
 		PrimaryKeyGenerator: sharding.PKSnowflake,
 		ShardingAlgorithm:   hashModeShardingAlgorithm(),
-		ShardingSuffixs:     shardingSuffix(16),
+		ShardingSuffixs:     shardingSuffix(NumberOfShards),
 	}, "link_goto")
 
 	Db.Use(shardingByFullShortUrl)
@@ -59,7 +62,7 @@ func hashModeShardingAlgorithm() func(columnValue any) (suffix string, err error
 			// Convert the first 8 characters of the hash to an integer
 			var shard int
 			fmt.Sscanf(hashValue[:8], "%x", &shard)
-			return fmt.Sprintf("_%d", shard%16), nil
+			return fmt.Sprintf("_%d", shard%NumberOfShards), nil
 		}
 		return "", errors.New("invalid username")
 	}
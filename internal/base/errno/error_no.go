@@ -6,6 +6,7 @@ var (
 	ErrorTypeResourceNotFound ErrorType = "resource-not-found" // 404
 	ErrorTypeExternalError    ErrorType = "external-error"     // 500
 	ErrorTypeServiceError     ErrorType = "service-error"      // 500/200
+	ErrorTypeTooManyRequests  ErrorType = "too-many-requests"  // 429
 )
 
 var (
@@ -14,24 +15,90 @@ var (
 	ExternalError      SlugError = SlugError{errorType: ErrorTypeExternalError, msg: "系统异常"}
 
 	ErrUnauthorized = SlugError{errorType: ErrorTypeAuthorization, msg: "未授权"}
+	TooManyRequests = SlugError{errorType: ErrorTypeTooManyRequests, msg: "请求过于频繁"}
+	InvalidCursor   = SlugError{errorType: ErrorTypeRequestParam, msg: "无效的分页游标"}
+
+	// API Key 异常
+
+	ApiKeyInvalidScope     = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的 API Key 权限范围"}
+	ApiKeyInvalidExpire    = SlugError{errorType: ErrorTypeRequestParam, msg: "API Key 过期时间早于当前时间"}
+	ApiKeyInvalidRateLimit = SlugError{errorType: ErrorTypeRequestParam, msg: "API Key 请求频率超出允许范围"}
+	ApiKeyInvalid          = SlugError{errorType: ErrorTypeAuthorization, msg: "无效的 API Key"}
+	ApiKeyExpired          = SlugError{errorType: ErrorTypeAuthorization, msg: "API Key 已过期"}
+	ApiKeyScopeDenied      = SlugError{errorType: ErrorTypeAuthorization, msg: "API Key 无权执行该操作"}
+	ApiKeyGroupDenied      = SlugError{errorType: ErrorTypeAuthorization, msg: "API Key 无权访问该分组"}
+	ApiKeyRateLimited      = SlugError{errorType: ErrorTypeTooManyRequests, msg: "API Key 请求过于频繁"}
+	ApiKeyNotExists        = SlugError{errorType: ErrorTypeResourceNotFound, msg: "API Key 不存在"}
 
 	// 短链接异常
 
 	LinkInvalidOriginalUrl     = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的原始链接"}
 	LinkInvalidValidType       = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的有效期类型"}
 	LinkEndTimeBeforeStartTime = SlugError{errorType: ErrorTypeRequestParam, msg: "结束时间早于开始时间"}
+	LinkInvalidAlias           = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的自定义短链接"}
+	LinkReservedAlias          = SlugError{errorType: ErrorTypeRequestParam, msg: "自定义短链接为系统保留字"}
+	LinkInvalidPassword        = SlugError{errorType: ErrorTypeRequestParam, msg: "访问密码长度应为4~64位"}
+	LinkInvalidMaxVisits       = SlugError{errorType: ErrorTypeRequestParam, msg: "最大访问次数不能为负数"}
+	LinkInvalidRoutingRule     = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的路由规则"}
+	LinkInvalidVariant         = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的分流配置"}
+	LinkInvalidUrlParams       = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的查询参数配置"}
+	LinkInvalidRedirectCode    = SlugError{errorType: ErrorTypeRequestParam, msg: "跳转状态码仅支持301、302、307、308"}
+	LinkInvalidFallbackUrl     = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的备用链接"}
+	LinkInvalidDomain          = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的域名"}
+	LinkUnsafeScheme           = SlugError{errorType: ErrorTypeRequestParam, msg: "不支持的链接协议"}
+	LinkInvalidTags            = SlugError{errorType: ErrorTypeRequestParam, msg: "标签最多10个，每个不超过32个字符"}
+	LinkInvalidSearchTime      = SlugError{errorType: ErrorTypeRequestParam, msg: "查询的结束时间早于开始时间"}
+	LinkImportFormat           = SlugError{errorType: ErrorTypeRequestParam, msg: "导入文件仅支持 csv 和 ndjson 格式"}
+	LinkImportEmpty            = SlugError{errorType: ErrorTypeRequestParam, msg: "导入文件中没有数据"}
+	LinkImportTooManyRows      = SlugError{errorType: ErrorTypeRequestParam, msg: "导入文件超过行数限制"}
+	LinkImportMissingGid       = SlugError{errorType: ErrorTypeRequestParam, msg: "未指定分组"}
+	LinkExportFormat           = SlugError{errorType: ErrorTypeRequestParam, msg: "导出文件仅支持 csv、json 和 xlsx 格式"}
+	LinkBulkInvalidAction      = SlugError{errorType: ErrorTypeRequestParam, msg: "不合法的批量操作"}
+	LinkBulkEmpty              = SlugError{errorType: ErrorTypeRequestParam, msg: "没有需要操作的短链接"}
+	LinkBulkTooMany            = SlugError{errorType: ErrorTypeRequestParam, msg: "超过单次批量操作的数量限制"}
+
+	LinkPasswordRequired      = SlugError{errorType: ErrorTypeAuthorization, msg: "短链接需要密码访问"}
+	LinkPasswordIncorrect     = SlugError{errorType: ErrorTypeAuthorization, msg: "短链接访问密码错误"}
+	LinkUnlockTooManyAttempts = SlugError{errorType: ErrorTypeTooManyRequests, msg: "密码错误次数过多，请稍后再试"}
 
 	LinkGroupEmpty           = SlugError{errorType: ErrorTypeResourceNotFound, msg: "分组下没有短链接"}
+	LinkGroupNotExists       = SlugError{errorType: ErrorTypeResourceNotFound, msg: "分组不存在"}
+	LinkJobNotExists         = SlugError{errorType: ErrorTypeResourceNotFound, msg: "任务不存在"}
+	LinkJobNotFinished       = SlugError{errorType: ErrorTypeServiceError, msg: "任务尚未完成"}
 	LinkAlreadyExists        = SlugError{errorType: ErrorTypeServiceError, msg: "短链接已存在"}
 	LinkNotExists            = SlugError{errorType: ErrorTypeServiceError, msg: "短链接不存在"}
 	LinkDisabled             = SlugError{errorType: ErrorTypeServiceError, msg: "短链接已停用"}
 	LinkExpired              = SlugError{errorType: ErrorTypeServiceError, msg: "短链接已过期"}
+	LinkNotStarted           = SlugError{errorType: ErrorTypeServiceError, msg: "短链接尚未生效"}
 	LinkForbidden            = SlugError{errorType: ErrorTypeServiceError, msg: "短链接已禁用"}
 	LinkReserved             = SlugError{errorType: ErrorTypeServiceError, msg: "短链接已保留"}
 	LinkInvalidStatus        = SlugError{errorType: ErrorTypeServiceError, msg: "不合法的短链接状态"}
 	LinkTooManyAttempts      = SlugError{errorType: ErrorTypeServiceError, msg: "多次尝试生成唯一短链接失败"}
 	LinkDisallowedDomain     = SlugError{errorType: ErrorTypeServiceError, msg: "不支持跳转的域名"}
 	LinkGroupLinkCountExceed = SlugError{errorType: ErrorTypeServiceError, msg: "超过组内短链接数量限制"}
+	LinkAliasAlreadyExists   = SlugError{errorType: ErrorTypeServiceError, msg: "自定义短链接已被占用"}
+	LinkVisitsExhausted      = SlugError{errorType: ErrorTypeServiceError, msg: "短链接访问次数已用完"}
+	LinkDomainNotExists      = SlugError{errorType: ErrorTypeServiceError, msg: "域名不存在"}
+	LinkDomainAlreadyExists  = SlugError{errorType: ErrorTypeServiceError, msg: "域名已被注册"}
+	LinkDomainUnavailable    = SlugError{errorType: ErrorTypeServiceError, msg: "域名暂不可用"}
+	LinkDomainInUse          = SlugError{errorType: ErrorTypeServiceError, msg: "域名下仍有短链接"}
+	LinkDomainVerifyFailed   = SlugError{errorType: ErrorTypeServiceError, msg: "未找到域名验证记录"}
+	LinkUnsafeUrl            = SlugError{errorType: ErrorTypeServiceError, msg: "跳转目标存在安全风险"}
+	LinkRedirectLoop         = SlugError{errorType: ErrorTypeServiceError, msg: "跳转目标指向本站短链接"}
+
+	// 用户异常
+
+	UserPasswordLength         = SlugError{errorType: ErrorTypeRequestParam, msg: "密码长度不符合要求"}
+	UserPasswordTooWeak        = SlugError{errorType: ErrorTypeRequestParam, msg: "密码需要包含更多类型的字符"}
+	UserPasswordHasUsername    = SlugError{errorType: ErrorTypeRequestParam, msg: "密码不能包含用户名"}
+	UserPasswordUnchanged      = SlugError{errorType: ErrorTypeRequestParam, msg: "新密码不能与旧密码相同"}
+	UserPasswordChangeRequired = SlugError{errorType: ErrorTypeRequestParam, msg: "请通过修改密码接口修改密码"}
+	UserPasswordIncorrect      = SlugError{errorType: ErrorTypeAuthorization, msg: "用户名或密码错误"}
+	UserInvalidToken           = SlugError{errorType: ErrorTypeAuthorization, msg: "令牌无效或已过期，请重新登录"}
+	UserInvalidMailToken       = SlugError{errorType: ErrorTypeRequestParam, msg: "链接无效或已过期"}
+	UserMailMissing            = SlugError{errorType: ErrorTypeRequestParam, msg: "未设置邮箱"}
+	UserMailAlreadyVerified    = SlugError{errorType: ErrorTypeServiceError, msg: "邮箱已验证"}
+	UserMailUnverified         = SlugError{errorType: ErrorTypeServiceError, msg: "邮箱未验证，请先完成邮箱验证"}
 
 	// 自定义系统异常
 
@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Now()
+	key, err := NewKey(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewKey(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyOf := func(kid string) *rsa.PublicKey {
+		if kid == key.Id {
+			return &key.Private.PublicKey
+		}
+		return nil
+	}
+	claims := Claims{Issuer: "short-link", Subject: "alice", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix(), Family: "f1"}
+
+	sign := func(k *Key, c Claims) string {
+		token, err := Sign(k, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := sign(key, claims)
+	expired := claims
+	expired.ExpiresAt = now.Add(-time.Second).Unix()
+	// 使用未知密钥签名但冒用已知的 kid
+	forged := sign(&Key{Id: key.Id, Private: other.Private}, claims)
+	parts := strings.Split(valid, ".")
+	none := encoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT","kid":"`+key.Id+`"}`)) + "." + parts[1] + "."
+	tampered := parts[0] + "." + encoding.EncodeToString([]byte(`{"iss":"short-link","sub":"bob","exp":`+
+		"9999999999"+`}`)) + "." + parts[2]
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"valid", valid, nil},
+		{"expired", sign(key, expired), ErrExpired},
+		{"unknown kid", sign(other, claims), ErrUnknownKey},
+		{"forged signature", forged, ErrSignature},
+		{"alg none", none, ErrMalformed},
+		{"tampered claims", tampered, ErrSignature},
+		{"malformed", "abc.def", ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.token, keyOf, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != claims {
+				t.Errorf("Parse() = %+v, want %+v", got, claims)
+			}
+		})
+	}
+}
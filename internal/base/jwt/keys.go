@@ -0,0 +1,270 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// KeysKey 签名密钥 hash key: short-link:jwt:keys field: kid value: storedKey
+	KeysKey = "short-link:jwt:keys"
+	// rotateLockKey 多实例同时轮换时只有一个实例生成新密钥
+	rotateLockKey = "short-link:jwt:rotate"
+	// keyBits RSA 密钥长度
+	keyBits = 2048
+	// publicKeysTTL 公钥在本地缓存的时间，遇到未知的 kid 时立即重新加载
+	publicKeysTTL = time.Minute
+)
+
+// Key 签名密钥
+type Key struct {
+	Id        string
+	Private   *rsa.PrivateKey
+	CreatedAt time.Time
+}
+
+// KeyStore 签名密钥存储
+type KeyStore interface {
+	// SigningKey 返回当前用于签名的密钥
+	SigningKey(ctx context.Context) (*Key, error)
+	// PublicKey 根据 kid 返回公钥，不存在时返回 nil
+	PublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error)
+	// PublicKeys 返回所有仍可用于验证的公钥，用于 JWKS
+	PublicKeys(ctx context.Context) (map[string]*rsa.PublicKey, error)
+}
+
+// NewKey 生成新的签名密钥
+func NewKey(now time.Time) (*Key, error) {
+	private, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+	var b [8]byte
+	if _, err = rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	return &Key{Id: encoding.EncodeToString(b[:]), Private: private, CreatedAt: now}, nil
+}
+
+type storedKey struct {
+	CreatedAt int64  `json:"created_at"`
+	Pem       string `json:"pem"`
+}
+
+// RedisKeyStore 签名密钥存储在 Redis 中，多实例共享
+//
+// 最新的密钥超过轮换间隔后生成新密钥，旧密钥保留到其签发的访问令牌全部过期后删除
+type RedisKeyStore struct {
+	rdb            *redis.Client
+	rotateInterval time.Duration
+	// 旧密钥的保留时间，不小于访问令牌的有效期
+	retention time.Duration
+
+	mu       sync.Mutex
+	keys     map[string]*Key
+	loadedAt time.Time
+}
+
+func NewRedisKeyStore(rdb *redis.Client, rotateInterval, accessTTL time.Duration) *RedisKeyStore {
+	if rdb == nil {
+		panic("nil rdb")
+	}
+	return &RedisKeyStore{
+		rdb:            rdb,
+		rotateInterval: rotateInterval,
+		retention:      rotateInterval + accessTTL,
+	}
+}
+
+func (s *RedisKeyStore) SigningKey(ctx context.Context) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if err := s.loadLocked(ctx, now, false); err != nil {
+		return nil, err
+	}
+	if key := s.newestLocked(); key != nil && now.Sub(key.CreatedAt) < s.rotateInterval {
+		return key, nil
+	}
+	if err := s.rotateLocked(ctx, now); err != nil {
+		return nil, err
+	}
+	if key := s.newestLocked(); key != nil {
+		return key, nil
+	}
+	return nil, errors.New("jwt: no signing key")
+}
+
+func (s *RedisKeyStore) PublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if err := s.loadLocked(ctx, now, false); err != nil {
+		return nil, err
+	}
+	if key, ok := s.keys[kid]; ok {
+		return &key.Private.PublicKey, nil
+	}
+	// 其他实例可能刚刚轮换了密钥
+	if err := s.loadLocked(ctx, now, true); err != nil {
+		return nil, err
+	}
+	if key, ok := s.keys[kid]; ok {
+		return &key.Private.PublicKey, nil
+	}
+	return nil, nil
+}
+
+func (s *RedisKeyStore) PublicKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(ctx, time.Now(), false); err != nil {
+		return nil, err
+	}
+	res := make(map[string]*rsa.PublicKey, len(s.keys))
+	for kid, key := range s.keys {
+		res[kid] = &key.Private.PublicKey
+	}
+	return res, nil
+}
+
+func (s *RedisKeyStore) newestLocked() *Key {
+	var newest *Key
+	for _, key := range s.keys {
+		if newest == nil || key.CreatedAt.After(newest.CreatedAt) {
+			newest = key
+		}
+	}
+	return newest
+}
+
+// loadLocked 从 Redis 加载密钥，过期的密钥顺便删除
+func (s *RedisKeyStore) loadLocked(ctx context.Context, now time.Time, force bool) error {
+	if !force && s.keys != nil && now.Sub(s.loadedAt) < publicKeysTTL {
+		return nil
+	}
+	values, err := s.rdb.HGetAll(ctx, KeysKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*Key, len(values))
+	var expired []string
+	for kid, value := range values {
+		var stored storedKey
+		if err = json.Unmarshal([]byte(value), &stored); err != nil {
+			return err
+		}
+		createdAt := time.Unix(stored.CreatedAt, 0)
+		if now.Sub(createdAt) > s.retention {
+			expired = append(expired, kid)
+			continue
+		}
+		block, _ := pem.Decode([]byte(stored.Pem))
+		if block == nil {
+			return errors.New("jwt: invalid key pem " + kid)
+		}
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		keys[kid] = &Key{Id: kid, Private: private, CreatedAt: createdAt}
+	}
+	if len(expired) > 0 {
+		if err = s.rdb.HDel(ctx, KeysKey, expired...).Err(); err != nil {
+			return err
+		}
+	}
+
+	s.keys, s.loadedAt = keys, now
+	return nil
+}
+
+// rotateLocked 生成新密钥，其他实例正在轮换时等待其完成
+func (s *RedisKeyStore) rotateLocked(ctx context.Context, now time.Time) error {
+	ok, err := s.rdb.SetNX(ctx, rotateLockKey, strconv.FormatInt(now.Unix(), 10), 10*time.Second).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		time.Sleep(200 * time.Millisecond)
+		return s.loadLocked(ctx, now, true)
+	}
+	defer s.rdb.Del(ctx, rotateLockKey)
+
+	// 拿到锁后再确认一次，避免重复轮换
+	if err = s.loadLocked(ctx, now, true); err != nil {
+		return err
+	}
+	if key := s.newestLocked(); key != nil && now.Sub(key.CreatedAt) < s.rotateInterval {
+		return nil
+	}
+
+	key, err := NewKey(now)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(storedKey{
+		CreatedAt: now.Unix(),
+		Pem: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key.Private),
+		})),
+	})
+	if err != nil {
+		return err
+	}
+	if err = s.rdb.HSet(ctx, KeysKey, key.Id, value).Err(); err != nil {
+		return err
+	}
+	s.keys[key.Id] = key
+	return nil
+}
+
+// Jwk JSON Web Key 中的 RSA 公钥
+type Jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Jwks JSON Web Key Set
+type Jwks struct {
+	Keys []Jwk `json:"keys"`
+}
+
+// NewJwks 将公钥转换为 JWKS，按 kid 排序保证输出稳定
+func NewJwks(keys map[string]*rsa.PublicKey) Jwks {
+	res := Jwks{Keys: make([]Jwk, 0, len(keys))}
+	for kid, key := range keys {
+		res.Keys = append(res.Keys, Jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Alg: alg,
+			Use: "sig",
+			N:   encoding.EncodeToString(key.N.Bytes()),
+			E:   encoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	sort.Slice(res.Keys, func(i, j int) bool {
+		return res.Keys[i].Kid < res.Keys[j].Kid
+	})
+	return res
+}
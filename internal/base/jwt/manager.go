@@ -0,0 +1,138 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"github.com/redis/go-redis/v9"
+	"shortlink/internal/base"
+	"time"
+)
+
+// TokenPair 登录或刷新后返回给客户端的令牌
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// 访问令牌有效期（秒）
+	ExpiresIn int `json:"expires_in"`
+}
+
+// Manager 签发和校验访问令牌，管理刷新令牌
+//
+// 访问令牌是无状态的，只校验签名和有效期；登出时吊销刷新令牌族，已签发的访问令牌在过期后失效
+type Manager struct {
+	keys      KeyStore
+	refresh   *RefreshStore
+	issuer    string
+	accessTTL time.Duration
+}
+
+func NewManager(keys KeyStore, refresh *RefreshStore, issuer string, accessTTL time.Duration) *Manager {
+	if keys == nil {
+		panic("nil keys")
+	}
+	if refresh == nil {
+		panic("nil refresh")
+	}
+	return &Manager{keys: keys, refresh: refresh, issuer: issuer, accessTTL: accessTTL}
+}
+
+// NewManagerFromConfig 根据 base.Config.Auth.Jwt 创建
+func NewManagerFromConfig(rdb *redis.Client) *Manager {
+	cfg := base.GetConfig().Auth.Jwt
+	accessTTL := time.Duration(cfg.AccessTTL) * time.Second
+	return NewManager(
+		NewRedisKeyStore(rdb, time.Duration(cfg.KeyRotateInterval)*time.Second, accessTTL),
+		NewRefreshStore(rdb, time.Duration(cfg.RefreshTTL)*time.Second),
+		cfg.Issuer,
+		accessTTL,
+	)
+}
+
+// Issue 登录时签发令牌，开始新的刷新令牌族
+func (m *Manager) Issue(ctx context.Context, username string) (TokenPair, error) {
+	refreshToken, rt, err := m.refresh.Issue(ctx, username, "")
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return m.pair(ctx, refreshToken, rt)
+}
+
+// Refresh 使用刷新令牌换取新的令牌，旧的刷新令牌随即失效
+func (m *Manager) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	rt, err := m.refresh.Use(ctx, refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	next, rt, err := m.refresh.Issue(ctx, rt.Username, rt.Family)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return m.pair(ctx, next, rt)
+}
+
+// Verify 校验访问令牌
+func (m *Manager) Verify(ctx context.Context, accessToken string) (Claims, error) {
+	var keyErr error
+	claims, err := Parse(accessToken, func(kid string) *rsa.PublicKey {
+		var pub *rsa.PublicKey
+		pub, keyErr = m.keys.PublicKey(ctx, kid)
+		return pub
+	}, time.Now())
+	if keyErr != nil {
+		return Claims{}, keyErr
+	}
+	if err != nil {
+		return Claims{}, err
+	}
+	if claims.Issuer != m.issuer {
+		return Claims{}, ErrInvalidClaim
+	}
+	return claims, nil
+}
+
+// Revoke 吊销刷新令牌族，用于登出
+func (m *Manager) Revoke(ctx context.Context, family string) error {
+	return m.refresh.RevokeFamily(ctx, family)
+}
+
+// RevokeUser 吊销用户的所有刷新令牌
+func (m *Manager) RevokeUser(ctx context.Context, username string) error {
+	return m.refresh.RevokeUser(ctx, username)
+}
+
+// Jwks 返回验证访问令牌所需的公钥
+func (m *Manager) Jwks(ctx context.Context) (Jwks, error) {
+	keys, err := m.keys.PublicKeys(ctx)
+	if err != nil {
+		return Jwks{}, err
+	}
+	return NewJwks(keys), nil
+}
+
+func (m *Manager) pair(ctx context.Context, refreshToken string, rt RefreshToken) (TokenPair, error) {
+	key, err := m.keys.SigningKey(ctx)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	id, err := randomToken(16)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	now := time.Now()
+	accessToken, err := Sign(key, Claims{
+		Issuer:    m.issuer,
+		Subject:   rt.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(m.accessTTL).Unix(),
+		Id:        id,
+		Family:    rt.Family,
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(m.accessTTL / time.Second),
+	}, nil
+}
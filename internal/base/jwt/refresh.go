@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const (
+	// RefreshKeyPrefix 刷新令牌 hash key: short-link:jwt:refresh:{sha256(token)}
+	//
+	//	field: username, family, used
+	RefreshKeyPrefix = "short-link:jwt:refresh:"
+	// RefreshFamilyKeyPrefix 同一次登录签发的所有刷新令牌 set key: short-link:jwt:refresh-family:{family}
+	RefreshFamilyKeyPrefix = "short-link:jwt:refresh-family:"
+	// RefreshUserKeyPrefix 用户的所有令牌族 set key: short-link:jwt:refresh-user:{username}
+	RefreshUserKeyPrefix = "short-link:jwt:refresh-user:"
+)
+
+var (
+	ErrRefreshInvalid = errors.New("jwt: invalid refresh token")
+	// ErrRefreshReused 刷新令牌被重复使用，可能已经泄露，整个令牌族都会被吊销
+	ErrRefreshReused = errors.New("jwt: refresh token reused")
+)
+
+// RefreshToken 刷新令牌对应的登录信息
+type RefreshToken struct {
+	Username string
+	Family   string
+}
+
+// RefreshStore 刷新令牌存储，Redis 中只保存令牌的摘要
+//
+// 每次刷新都会签发新的刷新令牌并将旧令牌标记为已使用，已使用的令牌再次使用时吊销整个令牌族
+type RefreshStore struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewRefreshStore(rdb *redis.Client, ttl time.Duration) *RefreshStore {
+	if rdb == nil {
+		panic("nil rdb")
+	}
+	return &RefreshStore{rdb: rdb, ttl: ttl}
+}
+
+// Issue 签发刷新令牌，family 为空时开始新的令牌族
+func (s *RefreshStore) Issue(ctx context.Context, username, family string) (string, RefreshToken, error) {
+	if family == "" {
+		var err error
+		if family, err = randomToken(16); err != nil {
+			return "", RefreshToken{}, err
+		}
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+
+	key := RefreshKeyPrefix + digest(token)
+	familyKey := RefreshFamilyKeyPrefix + family
+	if _, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "username", username, "family", family)
+		pipe.Expire(ctx, key, s.ttl)
+		pipe.SAdd(ctx, familyKey, key)
+		pipe.Expire(ctx, familyKey, s.ttl)
+		pipe.SAdd(ctx, RefreshUserKeyPrefix+username, family)
+		pipe.Expire(ctx, RefreshUserKeyPrefix+username, s.ttl)
+		return nil
+	}); err != nil {
+		return "", RefreshToken{}, err
+	}
+	return token, RefreshToken{Username: username, Family: family}, nil
+}
+
+// Use 使用刷新令牌，每个令牌只能使用一次
+func (s *RefreshStore) Use(ctx context.Context, token string) (RefreshToken, error) {
+	if token == "" {
+		return RefreshToken{}, ErrRefreshInvalid
+	}
+	key := RefreshKeyPrefix + digest(token)
+	values, err := s.rdb.HMGet(ctx, key, "username", "family").Result()
+	if err != nil {
+		return RefreshToken{}, err
+	}
+	username, _ := values[0].(string)
+	family, _ := values[1].(string)
+	if username == "" || family == "" {
+		return RefreshToken{}, ErrRefreshInvalid
+	}
+
+	first, err := s.rdb.HSetNX(ctx, key, "used", strconv.FormatInt(time.Now().Unix(), 10)).Result()
+	if err != nil {
+		return RefreshToken{}, err
+	}
+	if !first {
+		if err = s.RevokeFamily(ctx, family); err != nil {
+			return RefreshToken{}, err
+		}
+		return RefreshToken{}, ErrRefreshReused
+	}
+	return RefreshToken{Username: username, Family: family}, nil
+}
+
+// RevokeFamily 吊销同一次登录签发的所有刷新令牌
+func (s *RefreshStore) RevokeFamily(ctx context.Context, family string) error {
+	if family == "" {
+		return nil
+	}
+	familyKey := RefreshFamilyKeyPrefix + family
+	keys, err := s.rdb.SMembers(ctx, familyKey).Result()
+	if err != nil {
+		return err
+	}
+	return s.rdb.Del(ctx, append(keys, familyKey)...).Err()
+}
+
+// RevokeUser 吊销用户的所有刷新令牌，用于修改密码
+func (s *RefreshStore) RevokeUser(ctx context.Context, username string) error {
+	userKey := RefreshUserKeyPrefix + username
+	families, err := s.rdb.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+	for _, family := range families {
+		if err = s.RevokeFamily(ctx, family); err != nil {
+			return err
+		}
+	}
+	return s.rdb.Del(ctx, userKey).Err()
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+func digest(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
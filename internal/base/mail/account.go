@@ -0,0 +1,189 @@
+package mail
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"shortlink/internal/base"
+	"shortlink/internal/base/errno"
+	"strings"
+	"time"
+)
+
+const (
+	purposeVerify = "verify"
+	purposeReset  = "reset"
+)
+
+// AccountToken 邮件链接中的令牌内容
+type AccountToken struct {
+	Purpose  string `json:"p"`
+	Username string `json:"u"`
+	// 邮箱验证令牌为邮箱地址，重置密码令牌为当前密码哈希的指纹
+	Stamp     string `json:"s"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// AccountMailer 发送邮箱验证和重置密码邮件，并校验邮件中的令牌
+//
+// 令牌不在服务端保存，通过签名防篡改。重置密码令牌绑定当前密码，密码修改后令牌随即失效，因此只能使用一次
+type AccountMailer struct {
+	sender    EmailSender
+	secret    []byte
+	from      string
+	verifyUrl string
+	resetUrl  string
+	verifyTTL time.Duration
+	resetTTL  time.Duration
+	now       func() time.Time
+}
+
+// NewAccountMailer secret 为空时使用随机密钥，服务重启或多实例部署时已发送的链接会失效
+func NewAccountMailer(
+	sender EmailSender,
+	secret, from, verifyUrl, resetUrl string,
+	verifyTTL, resetTTL time.Duration,
+) *AccountMailer {
+	if sender == nil {
+		panic("nil sender")
+	}
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &AccountMailer{
+		sender:    sender,
+		secret:    key,
+		from:      from,
+		verifyUrl: verifyUrl,
+		resetUrl:  resetUrl,
+		verifyTTL: verifyTTL,
+		resetTTL:  resetTTL,
+		now:       time.Now,
+	}
+}
+
+// NewAccountMailerFromConfig 根据 base.Config.Account 创建，发件人为 base.Config.Email.Username
+func NewAccountMailerFromConfig(sender EmailSender) *AccountMailer {
+	cfg := base.GetConfig()
+	return NewAccountMailer(
+		sender,
+		cfg.Account.TokenSecret,
+		cfg.Email.Username,
+		cfg.Account.VerifyUrl,
+		cfg.Account.ResetUrl,
+		time.Duration(cfg.Account.VerifyTTL)*time.Second,
+		time.Duration(cfg.Account.ResetTTL)*time.Second,
+	)
+}
+
+// SendVerifyEmail 发送邮箱验证邮件
+func (m *AccountMailer) SendVerifyEmail(username, mail string) error {
+	return m.send(VerifyEmailTemplate, m.verifyUrl, m.verifyTTL, username, mail, AccountToken{
+		Purpose:  purposeVerify,
+		Username: username,
+		Stamp:    mail,
+	})
+}
+
+// SendResetPassword 发送重置密码邮件，passwordHash 为用户当前保存的密码
+func (m *AccountMailer) SendResetPassword(username, mail, passwordHash string) error {
+	return m.send(ResetPasswordTemplate, m.resetUrl, m.resetTTL, username, mail, AccountToken{
+		Purpose:  purposeReset,
+		Username: username,
+		Stamp:    m.fingerprint(passwordHash),
+	})
+}
+
+// ParseVerifyToken 校验邮箱验证令牌，调用方需要确认 Stamp 与用户当前的邮箱一致
+func (m *AccountMailer) ParseVerifyToken(token string) (AccountToken, error) {
+	return m.parse(purposeVerify, token)
+}
+
+// ParseResetToken 校验重置密码令牌，调用方需要通过 ResetMatches 确认密码没有被修改过
+func (m *AccountMailer) ParseResetToken(token string) (AccountToken, error) {
+	return m.parse(purposeReset, token)
+}
+
+// ResetMatches 重置密码令牌签发后密码是否没有被修改
+func (m *AccountMailer) ResetMatches(t AccountToken, passwordHash string) bool {
+	return hmac.Equal([]byte(t.Stamp), []byte(m.fingerprint(passwordHash)))
+}
+
+func (m *AccountMailer) send(
+	tpl Template,
+	link string,
+	ttl time.Duration,
+	username, mail string,
+	t AccountToken,
+) error {
+	t.ExpiresAt = m.now().Add(ttl).Unix()
+	token, err := m.encode(t)
+	if err != nil {
+		return err
+	}
+	msg, err := tpl.Message(m.from, mail, TemplateData{
+		Username:      username,
+		Link:          withToken(link, token),
+		ExpireMinutes: int(ttl / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+	return m.sender.SendEmail(msg)
+}
+
+func (m *AccountMailer) encode(t AccountToken) (string, error) {
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(m.sign(payload)), nil
+}
+
+func (m *AccountMailer) parse(purpose, token string) (AccountToken, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return AccountToken{}, errno.UserInvalidMailToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return AccountToken{}, errno.UserInvalidMailToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, m.sign(payload)) {
+		return AccountToken{}, errno.UserInvalidMailToken
+	}
+	var t AccountToken
+	if err = json.Unmarshal(payload, &t); err != nil {
+		return AccountToken{}, errno.UserInvalidMailToken
+	}
+	if t.Purpose != purpose || t.Username == "" || m.now().Unix() >= t.ExpiresAt {
+		return AccountToken{}, errno.UserInvalidMailToken
+	}
+	return t, nil
+}
+
+func (m *AccountMailer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// fingerprint 令牌内容只做签名不加密，不直接放入密码哈希
+func (m *AccountMailer) fingerprint(passwordHash string) string {
+	return base64.RawURLEncoding.EncodeToString(m.sign([]byte("password:" + passwordHash))[:12])
+}
+
+func withToken(link, token string) string {
+	sep := "?"
+	if strings.Contains(link, "?") {
+		sep = "&"
+	}
+	return link + sep + "token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,101 @@
+package mail
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"shortlink/internal/base/errno"
+	"strings"
+	"testing"
+	"time"
+)
+
+var tokenPattern = regexp.MustCompile(`token=([^"&]+)`)
+
+// lastToken 从最后一封邮件的链接中取出令牌
+func lastToken(t *testing.T, sender *MemorySender) string {
+	t.Helper()
+	msg, ok := sender.Last()
+	if !ok {
+		t.Fatal("no email sent")
+	}
+	m := tokenPattern.FindStringSubmatch(msg.Body)
+	if m == nil {
+		t.Fatalf("no token in body: %s", msg.Body)
+	}
+	token, err := url.QueryUnescape(m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAccountMailer(t *testing.T) {
+	sender := NewMemorySender()
+	m := NewAccountMailer(sender, "secret", "noreply@example.com",
+		"https://example.com/verify", "https://example.com/reset?from=mail", time.Hour, 30*time.Minute)
+	now := time.Unix(1700000000, 0)
+	m.now = func() time.Time { return now }
+
+	if err := m.SendVerifyEmail("alice", "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	msg, _ := sender.Last()
+	if msg.Subject != VerifyEmailTemplate.Subject || msg.To[0] != "alice@example.com" || msg.From != "noreply@example.com" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if !strings.Contains(msg.Body, "https://example.com/verify?token=") || !strings.Contains(msg.Body, "60 分钟") {
+		t.Fatalf("unexpected body: %s", msg.Body)
+	}
+	verifyToken := lastToken(t, sender)
+
+	if err := m.SendResetPassword("alice", "alice@example.com", "hash-1"); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ = sender.Last(); !strings.Contains(msg.Body, "https://example.com/reset?from=mail&amp;token=") {
+		t.Fatalf("unexpected body: %s", msg.Body)
+	}
+	resetToken := lastToken(t, sender)
+	if len(sender.Messages()) != 2 {
+		t.Fatalf("got %d messages, want 2", len(sender.Messages()))
+	}
+
+	got, err := m.ParseVerifyToken(verifyToken)
+	if err != nil || got.Username != "alice" || got.Stamp != "alice@example.com" {
+		t.Fatalf("ParseVerifyToken() = %+v, %v", got, err)
+	}
+	got, err = m.ParseResetToken(resetToken)
+	if err != nil || got.Username != "alice" {
+		t.Fatalf("ParseResetToken() = %+v, %v", got, err)
+	}
+	if !m.ResetMatches(got, "hash-1") {
+		t.Fatal("reset token should match current password")
+	}
+	if m.ResetMatches(got, "hash-2") {
+		t.Fatal("reset token should not match changed password")
+	}
+
+	other := NewAccountMailer(sender, "other", "", "", "", time.Hour, time.Hour)
+	tests := []struct {
+		name  string
+		parse func(string) (AccountToken, error)
+		token string
+		at    time.Time
+	}{
+		{"wrong purpose", m.ParseResetToken, verifyToken, now},
+		{"expired", m.ParseVerifyToken, verifyToken, now.Add(time.Hour)},
+		{"reset expired", m.ParseResetToken, resetToken, now.Add(30 * time.Minute)},
+		{"tampered", m.ParseVerifyToken, "x" + verifyToken, now},
+		{"malformed", m.ParseVerifyToken, "not-a-token", now},
+		{"other secret", other.ParseVerifyToken, verifyToken, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.now = func() time.Time { return tt.at }
+			other.now = m.now
+			if _, err := tt.parse(tt.token); !errors.Is(err, errno.UserInvalidMailToken) {
+				t.Fatalf("err = %v, want %v", err, errno.UserInvalidMailToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package mail
+
+import "sync"
+
+// MemorySender 将邮件保存在内存中，用于测试和未配置 SMTP 的本地开发
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []EmailMessage
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) SendEmail(msg EmailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Messages 返回已发送的所有邮件
+func (s *MemorySender) Messages() []EmailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]EmailMessage(nil), s.messages...)
+}
+
+// Last 返回最后发送的邮件，没有邮件时 ok 为 false
+func (s *MemorySender) Last() (msg EmailMessage, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.messages) == 0 {
+		return EmailMessage{}, false
+	}
+	return s.messages[len(s.messages)-1], true
+}
@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// Template 邮件模板
+type Template struct {
+	Subject string
+	name    string
+}
+
+var (
+	VerifyEmailTemplate   = Template{Subject: "验证您的邮箱", name: "verify_email.html"}
+	ResetPasswordTemplate = Template{Subject: "重置您的密码", name: "reset_password.html"}
+)
+
+// TemplateData 模板参数
+type TemplateData struct {
+	Username      string
+	Link          string
+	ExpireMinutes int
+}
+
+// Message 渲染模板并生成邮件
+func (t Template) Message(from, to string, data TemplateData) (EmailMessage, error) {
+	var body bytes.Buffer
+	if err := templates.ExecuteTemplate(&body, t.name, data); err != nil {
+		return EmailMessage{}, err
+	}
+	return EmailMessage{
+		From:    from,
+		To:      []string{to},
+		Subject: t.Subject,
+		Body:    body.String(),
+	}, nil
+}
@@ -50,15 +50,11 @@ func ConnectToRocketMQForProducer() (rmqclient.Producer, func()) {
 	return producer, stopFn
 }
 
-func ConnectToRocketMQForConsumer() (rmqclient.SimpleConsumer, func()) {
+// ConnectToRocketMQForConsumer subs 为订阅的 topic 及 tag 过滤表达式，未订阅的消息不会投递到当前消费者组
+func ConnectToRocketMQForConsumer(subs map[string]*rmqclient.FilterExpression) (rmqclient.SimpleConsumer, func()) {
 
 	config := base.GetConfig().RocketMQ
 
-	subs := make(map[string]*rmqclient.FilterExpression)
-	for _, topic := range config.Topics {
-		subs[topic] = rmqclient.SUB_ALL
-	}
-
 	// In most case, you don't need to create many consumers, singleton pattern is more recommended.
 	simpleConsumer, err := rmqclient.NewSimpleConsumer(&rmqclient.Config{
 		Endpoint:      config.NameServer,
@@ -8,6 +8,9 @@ import (
 	"log/slog"
 	"reflect"
 	"shortlink/internal/base/base_event"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,6 +22,9 @@ type RocketMqBasedEventBus struct {
 	consumer     rmqclient.SimpleConsumer
 	stopFns      []func()
 	mode         RunMode
+	// 保护 listenerMap，Start 之后不再允许订阅，接收消息时无需加锁
+	mu      sync.Mutex
+	started bool
 }
 
 type RunMode int
@@ -33,32 +39,73 @@ func NewRocketMqBasedEventBus(ctx context.Context, mode RunMode) *RocketMqBasedE
 
 	var producer rmqclient.Producer
 	var producerStopFn func()
-	var consumer rmqclient.SimpleConsumer
-	var consumerStopFn func()
 
 	var stopFns []func()
 	if mode == ProducerMode || mode == MixMode {
 		producer, producerStopFn = ConnectToRocketMQForProducer()
 		stopFns = append(stopFns, producerStopFn)
 	}
-	if mode == ConsumerMode || mode == MixMode {
-		consumer, consumerStopFn = ConnectToRocketMQForConsumer()
-		stopFns = append(stopFns, consumerStopFn)
-	}
 
-	bus := &RocketMqBasedEventBus{
+	// 消费者在 Start 时按订阅的 tag 创建
+	return &RocketMqBasedEventBus{
 		listenerMap:  make(map[string][]base_event.EventListener),
 		typeRegistry: make(map[string]reflect.Type),
 		producer:     producer,
-		consumer:     consumer,
 		stopFns:      stopFns,
+		mode:         mode,
+	}
+}
+
+// Start 连接消费者并开始接收消息，需要在所有 Subscribe 之后调用
+//
+// 消费者只订阅已注册监听器的 tag，其他 tag 的消息留给订阅它们的消费者组，不会在这里积压重试
+func (bus *RocketMqBasedEventBus) Start(ctx context.Context) error {
+	if bus.mode == ProducerMode {
+		return errors.New("can't start consumer in ProducerMode")
 	}
 
-	if mode == ConsumerMode || mode == MixMode {
-		go bus.startReceivingMessages(ctx)
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if bus.started {
+		return errors.New("event bus already started")
+	}
+	bus.started = true
+	if len(bus.listenerMap) == 0 {
+		return nil
+	}
+
+	consumer, stopFn := ConnectToRocketMQForConsumer(subscriptionExpressions(bus.listenerMap))
+	bus.consumer = consumer
+	bus.stopFns = append(bus.stopFns, stopFn)
+	go bus.startReceivingMessages(ctx)
+	return nil
+}
+
+// subscriptionExpressions 按订阅生成每个 topic 的 tag 过滤表达式，订阅时未指定 tag 的 topic 接收所有消息
+func subscriptionExpressions(listenerMap map[string][]base_event.EventListener) map[string]*rmqclient.FilterExpression {
+	tags := make(map[string][]string)
+	all := make(map[string]bool)
+	for idx := range listenerMap {
+		topic, tag, ok := strings.Cut(idx, ":")
+		if !ok {
+			all[topic] = true
+			continue
+		}
+		tags[topic] = append(tags[topic], tag)
 	}
 
-	return bus
+	subs := make(map[string]*rmqclient.FilterExpression)
+	for topic := range all {
+		subs[topic] = rmqclient.SUB_ALL
+	}
+	for topic, t := range tags {
+		if all[topic] {
+			continue
+		}
+		sort.Strings(t)
+		subs[topic] = rmqclient.NewFilterExpression(strings.Join(t, "||"))
+	}
+	return subs
 }
 
 func (bus *RocketMqBasedEventBus) Close() {
@@ -88,7 +135,8 @@ func (bus *RocketMqBasedEventBus) startReceivingMessages(ctx context.Context) {
 			var ok bool
 			var listeners []base_event.EventListener
 			if listeners, ok = bus.listenerMap[idx]; !ok {
-				// 当前消息无人订阅
+				// 订阅时已按 tag 过滤，只有 topic 未指定 tag 订阅时才会收到没有监听器的消息
+				slog.Warn("Received message without listener", "subscription", idx)
 				continue
 			}
 
@@ -154,17 +202,18 @@ func (bus *RocketMqBasedEventBus) Subscribe(topic string, tag *string, listener
 		return errors.New("can't subscribe event in ProducerMode")
 	}
 
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if bus.started {
+		return errors.New("can't subscribe after the event bus started")
+	}
+
 	idx := ""
 	if tag != nil {
 		idx = topic + ":" + *tag
 	} else {
 		idx = topic
 	}
-	listeners, exists := bus.listenerMap[idx]
-	if !exists {
-		listeners = make([]base_event.EventListener, 0)
-		bus.listenerMap[idx] = listeners
-	}
-	listeners = append(listeners, listener)
+	bus.listenerMap[idx] = append(bus.listenerMap[idx], listener)
 	return nil
 }
@@ -0,0 +1,46 @@
+package mq
+
+import (
+	rmqclient "github.com/apache/rocketmq-clients/golang/v5"
+	"reflect"
+	"shortlink/internal/base/base_event"
+	"testing"
+)
+
+func TestSubscriptionExpressions(t *testing.T) {
+	tests := []struct {
+		name string
+		idx  []string
+		want map[string]*rmqclient.FilterExpression
+	}{
+		{
+			name: "single tag",
+			idx:  []string{"link:link_created"},
+			want: map[string]*rmqclient.FilterExpression{"link": rmqclient.NewFilterExpression("link_created")},
+		},
+		{
+			name: "multiple tags",
+			idx:  []string{"link:b", "link:a", "stats:visit"},
+			want: map[string]*rmqclient.FilterExpression{
+				"link":  rmqclient.NewFilterExpression("a||b"),
+				"stats": rmqclient.NewFilterExpression("visit"),
+			},
+		},
+		{
+			name: "topic without tag receives all",
+			idx:  []string{"link:a", "link"},
+			want: map[string]*rmqclient.FilterExpression{"link": rmqclient.SUB_ALL},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listenerMap := make(map[string][]base_event.EventListener)
+			for _, idx := range tt.idx {
+				listenerMap[idx] = nil
+			}
+			if got := subscriptionExpressions(listenerMap); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subscriptionExpressions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"shortlink/internal/base"
+	"strings"
+)
+
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+// argon2id 参数，参考 RFC 9106 中内存受限场景的推荐值
+const (
+	argonMemory  uint32 = 64 * 1024
+	argonTime    uint32 = 3
+	argonThreads uint8  = 2
+	argonSaltLen        = 16
+	argonKeyLen  uint32 = 32
+)
+
+var errMalformedHash = errors.New("password: malformed hash")
+
+// Hasher 按配置的算法生成密码哈希，校验时兼容 argon2id、bcrypt 和历史遗留的明文密码
+type Hasher struct {
+	algorithm  string
+	bcryptCost int
+}
+
+func NewHasher(algorithm string) (*Hasher, error) {
+	switch algorithm {
+	case Argon2id, Bcrypt:
+		return &Hasher{algorithm: algorithm, bcryptCost: bcrypt.DefaultCost}, nil
+	default:
+		return nil, fmt.Errorf("password: unsupported algorithm %q", algorithm)
+	}
+}
+
+// NewHasherFromConfig 根据 base.Config.Password 创建
+func NewHasherFromConfig() *Hasher {
+	h, err := NewHasher(base.GetConfig().Password.Algorithm)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func (h *Hasher) Hash(plain string) (string, error) {
+	if h.algorithm == Bcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), h.bcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 校验密码，rehash 表示密码正确但存储格式已过时（明文、其他算法或参数变化），需要重新哈希后保存
+func (h *Hasher) Verify(encoded, plain string) (ok bool, rehash bool, err error) {
+	switch {
+	case encoded == "":
+		return false, false, nil
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		ok, current, err := verifyArgon2id(encoded, plain)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, h.algorithm != Argon2id || !current, nil
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		if err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, false, nil
+			}
+			return false, false, err
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		if err != nil {
+			return false, false, err
+		}
+		return true, h.algorithm != Bcrypt || cost != h.bcryptCost, nil
+	default:
+		// 历史数据中的明文密码
+		ok = subtle.ConstantTimeCompare([]byte(encoded), []byte(plain)) == 1
+		return ok, ok, nil
+	}
+}
+
+// verifyArgon2id current 表示哈希使用的参数与当前参数一致
+func verifyArgon2id(encoded, plain string) (ok bool, current bool, err error) {
+	// $argon2id$v=19$m=65536,t=3,p=2$salt$key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false, errMalformedHash
+	}
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, false, errMalformedHash
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false, errMalformedHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, errMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, false, errMalformedHash
+	}
+
+	actual := argon2.IDKey([]byte(plain), salt, time, memory, threads, uint32(len(key)))
+	ok = subtle.ConstantTimeCompare(actual, key) == 1
+	current = version == argon2.Version && memory == argonMemory && time == argonTime && threads == argonThreads &&
+		uint32(len(key)) == argonKeyLen
+	return ok, current, nil
+}
@@ -0,0 +1,83 @@
+package password
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestHasherVerify(t *testing.T) {
+	argon, err := NewHasher(Argon2id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := NewHasher(Bcrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	argonHash, err := argon.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bc.Hash("s3cret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		hasher     *Hasher
+		encoded    string
+		plain      string
+		wantOk     bool
+		wantRehash bool
+	}{
+		{"argon2id", argon, argonHash, "s3cret-pass", true, false},
+		{"argon2id wrong password", argon, argonHash, "s3cret-Pass", false, false},
+		{"bcrypt", bc, bcryptHash, "s3cret-pass", true, false},
+		{"bcrypt to argon2id", argon, bcryptHash, "s3cret-pass", true, true},
+		{"argon2id to bcrypt", bc, argonHash, "s3cret-pass", true, true},
+		{"legacy plaintext", argon, "s3cret-pass", "s3cret-pass", true, true},
+		{"legacy plaintext wrong password", argon, "s3cret-pass", "other", false, false},
+		{"empty", argon, "", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := tt.hasher.Verify(tt.encoded, tt.plain)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if ok != tt.wantOk || rehash != tt.wantRehash {
+				t.Errorf("Verify() = %v, %v, want %v, %v", ok, rehash, tt.wantOk, tt.wantRehash)
+			}
+		})
+	}
+
+	if _, _, err = argon.Verify("$argon2id$v=19$m=65536$abc", "x"); err == nil {
+		t.Error("Verify() malformed hash error = nil")
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	policy := Policy{MinLength: 8, MaxLength: 16, MinClasses: 3}
+	tests := []struct {
+		name     string
+		plain    string
+		username string
+		wantErr  error
+	}{
+		{"ok", "Abcdef12", "alice", nil},
+		{"too short", "Ab1", "alice", errno.UserPasswordLength},
+		{"too long", "Abcdefgh12345678x", "alice", errno.UserPasswordLength},
+		{"too weak", "abcdefgh12", "alice", errno.UserPasswordTooWeak},
+		{"symbol counts", "abcdefg!12", "alice", nil},
+		{"contains username", "xxALICE12", "alice", errno.UserPasswordHasUsername},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := policy.Validate(tt.plain, tt.username); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
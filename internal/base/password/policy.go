@@ -0,0 +1,53 @@
+package password
+
+import (
+	"shortlink/internal/base"
+	"shortlink/internal/base/errno"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy 密码策略，在注册、修改用户信息和修改密码时校验
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// 至少包含的字符类型数量 小写字母、大写字母、数字、特殊字符
+	MinClasses int
+}
+
+// PolicyFromConfig 根据 base.Config.Password 创建
+func PolicyFromConfig() Policy {
+	cfg := base.GetConfig().Password
+	return Policy{MinLength: cfg.MinLength, MaxLength: cfg.MaxLength, MinClasses: cfg.MinClasses}
+}
+
+// Validate 校验密码，密码中不能包含用户名
+func (p Policy) Validate(plain, username string) error {
+	length := utf8.RuneCountInString(plain)
+	if length < p.MinLength || (p.MaxLength > 0 && length > p.MaxLength) {
+		return errno.UserPasswordLength
+	}
+
+	var lower, upper, digit, symbol int
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	if lower+upper+digit+symbol < p.MinClasses {
+		return errno.UserPasswordTooWeak
+	}
+
+	if username != "" && strings.Contains(strings.ToLower(plain), strings.ToLower(username)) {
+		return errno.UserPasswordHasUsername
+	}
+	return nil
+}
@@ -65,6 +65,13 @@ func RespondWithError(c *fiber.Ctx, err error) error {
 			Msg:    slugError.Error(),
 		}
 		return c.Status(fiber.StatusBadRequest).JSON(r)
+	case errno.ErrorTypeTooManyRequests:
+		// 请求频率过高
+		r := Response{
+			Status: "Too many requests",
+			Msg:    slugError.Error(),
+		}
+		return c.Status(fiber.StatusTooManyRequests).JSON(r)
 	case errno.ErrorTypeResourceNotFound:
 		// 资源未找到，返回 notfound 页面
 		return c.Status(fiber.StatusNotFound).SendFile("../resources/notfound.html")
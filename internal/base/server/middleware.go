@@ -12,7 +12,6 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"shortlink/internal/base"
 	"shortlink/internal/base/server/httperr"
-	"shortlink/internal/base/server/middleware/auth"
 )
 
 func setupMiddlewares(app *fiber.App) {
@@ -29,5 +28,4 @@ func setupMiddlewares(app *fiber.App) {
 	app.Use(logger.New())
 	app.Use(recover.New())
 	app.Use(requestid.New())
-	app.Use(auth.New(nil, nil))
 }
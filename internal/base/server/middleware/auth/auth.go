@@ -1,37 +1,222 @@
 package auth
 
 import (
+	"errors"
+	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
+	"shortlink/internal/base"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/jwt"
+	"shortlink/internal/base/server/httperr"
+	"strings"
 )
 
-func New(redisClient *redis.Client, excludes []string) fiber.Handler {
+// LoginKeyPrefix 登录会话的 key 前缀，与 UserRepository.Login 写入的 key 保持一致
+//
+// hash key: short-link:login:{username}
+//
+//	field: token
+//	value: 用户信息
+const LoginKeyPrefix = "short-link:login:"
+
+const (
+	// HeaderUsername 登录用户名
+	HeaderUsername = "username"
+	// HeaderToken 登录返回的 token，也可以通过 Authorization: Bearer {token} 传递
+	HeaderToken = "token"
+)
+
+// LocalTokenFamily JWT 模式下访问令牌所属的刷新令牌族，登出时吊销
+const LocalTokenFamily = "token_family"
+
+// ApiKeyRoute 允许使用 API Key 访问的路由及所需的权限范围，Path 与 excludes 的规则相同
+type ApiKeyRoute struct {
+	Method string
+	Path   string
+	Scope  apikey.Scope
+}
+
+// New 鉴权中间件，校验登录 token 并将用户信息写入 Locals
+//
+// excludes 为不需要鉴权的路由，相对于 base_path，支持 :param 匹配单段路径和 * 匹配剩余路径
+//
+// base.Config.Auth.Mode 为 jwt 时校验访问令牌，否则校验 Redis 中的登录会话；
+// 以 sk_ 开头的 Bearer token 视为 API Key，只能访问 apiKeyRoutes 中的路由
+func New(redisClient *redis.Client, excludes []string, apiKeyRoutes ...ApiKeyRoute) fiber.Handler {
+	if redisClient == nil {
+		panic("nil redisClient")
+	}
+	basePath := strings.TrimSuffix(base.GetConfig().Server.BasePath, "/")
+	patterns := make([][]string, 0, len(excludes))
+	for _, exclude := range excludes {
+		patterns = append(patterns, splitPath(basePath+exclude))
+	}
+
+	routes := make([]apiKeyRoute, 0, len(apiKeyRoutes))
+	for _, route := range apiKeyRoutes {
+		routes = append(routes, apiKeyRoute{
+			method:  route.Method,
+			pattern: splitPath(basePath + route.Path),
+			scope:   route.Scope,
+		})
+	}
+
+	verify := sessionVerifier(redisClient)
+	if base.GetConfig().Auth.Mode == base.AuthModeJwt {
+		verify = jwtVerifier(jwt.NewManagerFromConfig(redisClient))
+	}
+	verifyApiKey := apiKeyVerifier(apikey.NewStore(redisClient), routes)
+
+	return func(c *fiber.Ctx) error {
+		// 排除不需要鉴权的接口
+		path := splitPath(c.Path())
+		for _, pattern := range patterns {
+			if matchPath(pattern, path) {
+				return c.Next()
+			}
+		}
+		if apikey.IsApiKey(bearerToken(c)) {
+			return verifyApiKey(c)
+		}
+		return verify(c)
+	}
+}
+
+// sessionVerifier 校验 UserRepository.Login 写入 Redis 的登录会话
+func sessionVerifier(redisClient *redis.Client) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		//// 排除不需要鉴权的接口
-		//baseUrl := base.GetConfig().Server.BasePath
-		//for _, exclude := range excludes {
-		//	if baseUrl+exclude == c.Path() {
-		//		return c.Next()
-		//	}
-		//}
-		//// 获取请求头中的 Authorization 字段
-		//token := c.Get("Authorization")
-		//if token == "" {
-		//	return errno.ErrUnauthorized
-		//}
-		//// 查询 token 对应的用户信息 基于redis
-		//user, err := redisClient.Get(c.Context(), token).Result()
-		//if err != nil {
-		//	return errno.ErrUnauthorized
-		//}
-		//userinfo := make(map[string]interface{})
-		//if err = sonic.Unmarshal([]byte(user), &userinfo); err != nil {
-		//	return err
-		//}
-		//// 将用户信息存入上下文
-		//c.Locals("user", userinfo)
-		//c.Locals("username", userinfo["username"])
-		c.Locals("username", "default")
+		username, token := c.Get(HeaderUsername), bearerToken(c)
+		if username == "" || token == "" {
+			return httperr.RespondWithError(c, errno.ErrUnauthorized)
+		}
+
+		// 查询 token 对应的用户信息
+		user, err := redisClient.HGet(c.Context(), LoginKeyPrefix+username, token).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return httperr.RespondWithError(c, errno.ErrUnauthorized)
+			}
+			return httperr.RespondWithError(c, err)
+		}
+		userinfo := make(map[string]interface{})
+		if err = sonic.UnmarshalString(user, &userinfo); err != nil {
+			return httperr.RespondWithError(c, err)
+		}
+
+		// 将用户信息存入上下文
+		c.Locals("user", userinfo)
+		c.Locals("username", username)
+		// 查询按 tenant_id = username 过滤，写入时 BaseModel 从 tenant_id 中取值
+		c.Locals("tenant_id", username)
 		return c.Next()
 	}
 }
+
+// jwtVerifier 校验访问令牌的签名和有效期，不访问 Redis 中的登录会话
+func jwtVerifier(manager *jwt.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := bearerToken(c)
+		if token == "" {
+			return httperr.RespondWithError(c, errno.ErrUnauthorized)
+		}
+		claims, err := manager.Verify(c.Context(), token)
+		if err != nil {
+			if jwt.IsInvalid(err) {
+				return httperr.RespondWithError(c, errno.ErrUnauthorized)
+			}
+			return httperr.RespondWithError(c, err)
+		}
+
+		c.Locals("user", map[string]interface{}{"username": claims.Subject})
+		c.Locals("username", claims.Subject)
+		c.Locals("tenant_id", claims.Subject)
+		c.Locals(LocalTokenFamily, claims.Family)
+		return c.Next()
+	}
+}
+
+type apiKeyRoute struct {
+	method  string
+	pattern []string
+	scope   apikey.Scope
+}
+
+// apiKeyVerifier 校验 API Key 的有效期、请求频率和权限范围，分组在应用层通过 apikey.CheckGroup 校验
+func apiKeyVerifier(store *apikey.Store, routes []apiKeyRoute) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key, err := store.Authenticate(c.Context(), bearerToken(c))
+		if err != nil {
+			return httperr.RespondWithError(c, err)
+		}
+
+		path := splitPath(c.Path())
+		allowed := false
+		for _, route := range routes {
+			if route.method == c.Method() && matchPath(route.pattern, path) {
+				allowed = key.Allow(route.scope)
+				break
+			}
+		}
+		if !allowed {
+			return httperr.RespondWithError(c, errno.ApiKeyScopeDenied)
+		}
+
+		c.Locals("user", map[string]interface{}{"username": key.Username})
+		c.Locals("username", key.Username)
+		c.Locals("tenant_id", key.Username)
+		c.Locals(apikey.LocalKey, key)
+		return c.Next()
+	}
+}
+
+// Jwks 公开访问令牌的验证公钥，供网关等服务无状态地校验访问令牌
+func Jwks(manager *jwt.Manager) fiber.Handler {
+	if manager == nil {
+		panic("nil manager")
+	}
+	return func(c *fiber.Ctx) error {
+		jwks, err := manager.Jwks(c.Context())
+		if err != nil {
+			return httperr.RespondWithError(c, err)
+		}
+		c.Set(fiber.HeaderCacheControl, "public, max-age=60")
+		return c.JSON(jwks)
+	}
+}
+
+// bearerToken 优先读取 Authorization 请求头
+func bearerToken(c *fiber.Ctx) string {
+	if authorization := c.Get(fiber.HeaderAuthorization); authorization != "" {
+		token, _ := strings.CutPrefix(authorization, "Bearer ")
+		return strings.TrimSpace(token)
+	}
+	return c.Get(HeaderToken)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchPath 逐段匹配路由，:param 匹配任意非空的一段，* 匹配剩余的所有段
+func matchPath(pattern, path []string) bool {
+	for i, seg := range pattern {
+		if seg == "*" {
+			return true
+		}
+		if i >= len(path) {
+			return false
+		}
+		if strings.HasPrefix(seg, ":") {
+			if path[i] == "" {
+				return false
+			}
+			continue
+		}
+		if seg != path[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(path)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import "testing"
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/:shortUri", "/abc123", true},
+		{"/:shortUri", "/", false},
+		{"/:shortUri", "/api/short-link/v1/page", false},
+		{"/users/login", "/users/login", true},
+		{"/users/login", "/users/login/", true},
+		{"/users/login", "/users/logout", false},
+		{"/.well-known/*", "/.well-known/shortlink-verify", true},
+		{"/app/v1/:shortUri", "/app/v1/abc", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := matchPath(splitPath(tt.pattern), splitPath(tt.path)); got != tt.want {
+				t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
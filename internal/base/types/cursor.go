@@ -0,0 +1,123 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/bytedance/sonic"
+	"shortlink/internal/base/errno"
+	"strings"
+	"time"
+)
+
+// CursorReq 游标分页请求 Cursor 为空时从第一页开始
+type CursorReq struct {
+	Cursor string `json:"cursor,omitempty" query:"cursor"`
+	Size   int    `json:"size,omitempty" query:"size"`
+}
+
+func (p CursorReq) Limit() int {
+	if p.Size <= 0 {
+		return defaultPageSize
+	}
+	return p.Size
+}
+
+// CursorResp 游标分页结果 NextCursor 为空表示没有下一页
+type CursorResp[T any] struct {
+	Records    []T    `json:"records"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Cursor 游标位置，即上一页最后一条记录的排序键
+//
+// 排序键由时间和 ID 组成，时间相同时按 ID 区分，保证翻页过程中新插入的记录不会导致重复或遗漏
+type Cursor struct {
+	// 游标所属的列表 避免在不同接口之间混用
+	Kind string    `json:"k"`
+	Time time.Time `json:"t"`
+	Id   int64     `json:"i"`
+}
+
+// CursorCodec 将游标编码为带签名的字符串，客户端只能原样传回
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec secret 为空时使用随机密钥，服务重启或多实例部署时游标会失效
+func NewCursorCodec(secret string) CursorCodec {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return CursorCodec{secret: key}
+}
+
+func (c CursorCodec) Encode(cursor Cursor) (string, error) {
+	payload, err := sonic.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode 校验签名并解析游标，token 为空时返回 nil 表示第一页
+func (c CursorCodec) Decode(kind, token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errno.InvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errno.InvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+		return nil, errno.InvalidCursor
+	}
+	var cursor Cursor
+	if err = sonic.Unmarshal(payload, &cursor); err != nil || cursor.Kind != kind {
+		return nil, errno.InvalidCursor
+	}
+	return &cursor, nil
+}
+
+func (c CursorCodec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// NewCursorResp 根据多查询的一条记录判断是否还有下一页，并用本页最后一条记录生成下一页的游标
+func NewCursorResp[T any](
+	codec CursorCodec,
+	kind string,
+	records []T,
+	limit int,
+	key func(T) (time.Time, int64),
+) (*CursorResp[T], error) {
+	if records == nil {
+		records = make([]T, 0)
+	}
+	res := &CursorResp[T]{Records: records}
+	if len(records) <= limit {
+		return res, nil
+	}
+
+	res.Records = records[:limit]
+	res.HasMore = true
+	t, id := key(res.Records[limit-1])
+	next, err := codec.Encode(Cursor{Kind: kind, Time: t, Id: id})
+	if err != nil {
+		return nil, err
+	}
+	res.NextCursor = next
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+	"time"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	want := Cursor{Kind: "link", Time: time.Date(2024, 5, 1, 8, 0, 0, 123, time.UTC), Id: 42}
+
+	token, err := codec.Encode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := codec.Decode("link", token)
+	if err != nil || got == nil || !got.Time.Equal(want.Time) || got.Id != want.Id {
+		t.Fatalf("Decode() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if got, err = codec.Decode("link", ""); got != nil || err != nil {
+		t.Errorf("Decode(\"\") = %+v, %v, want nil", got, err)
+	}
+
+	invalid := []struct {
+		name  string
+		codec CursorCodec
+		kind  string
+		token string
+	}{
+		{"other kind", codec, "recycle_bin", token},
+		{"other secret", NewCursorCodec("another"), "link", token},
+		{"tampered", codec, "link", "x" + token},
+		{"malformed", codec, "link", "abc"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.codec.Decode(tt.kind, tt.token); !errors.Is(err, errno.InvalidCursor) {
+				t.Errorf("Decode() error = %v, want InvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestNewCursorResp(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	key := func(i int) (time.Time, int64) { return base.Add(-time.Duration(i) * time.Minute), int64(i) }
+
+	res, err := NewCursorResp(codec, "link", []int{1, 2, 3}, 2, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.HasMore || len(res.Records) != 2 || res.NextCursor == "" {
+		t.Fatalf("NewCursorResp() = %+v", res)
+	}
+	next, _ := codec.Decode("link", res.NextCursor)
+	if next.Id != 2 || !next.Time.Equal(base.Add(-2*time.Minute)) {
+		t.Errorf("next cursor = %+v, want id 2", next)
+	}
+
+	if res, _ = NewCursorResp(codec, "link", []int{1, 2}, 2, key); res.HasMore || res.NextCursor != "" {
+		t.Errorf("NewCursorResp() = %+v, want last page", res)
+	}
+}
@@ -0,0 +1,33 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// DBAccountChecker 查询用户的邮箱验证状态
+//
+// 用户归属于用户服务，两个服务共用数据库，这里直接查询用户表
+type DBAccountChecker struct {
+	db *gorm.DB
+}
+
+func NewDBAccountChecker(db *gorm.DB) *DBAccountChecker {
+	if db == nil {
+		panic("nil db")
+	}
+	return &DBAccountChecker{db: db}
+}
+
+// MailVerified 用户不存在时返回 false
+func (c DBAccountChecker) MailVerified(ctx context.Context, username string) (bool, error) {
+	var verified bool
+	if err := c.db.WithContext(ctx).
+		Raw(`SELECT mail_verified FROM t_user WHERE username = ? AND delete_time IS NULL LIMIT 1`, username).
+		Row().Scan(&verified); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	return verified, nil
+}
@@ -39,16 +39,26 @@ func (a *LinkAssembler) LinkEntityToLinkPo(lk *link.Link) po.Link {
 		BaseModel: database.BaseModel{
 			ID: lk.ID(),
 		},
-		Gid:         lk.Gid(),
-		ShortUri:    lk.ShortUri(),
-		OriginalUrl: lk.OriginalUrl(),
-		Favicon:     lk.Favicon(),
-		Status:      lk.Status(),
-		CreateType:  lk.CreateType(),
-		ValidType:   lk.ValidDate().ValidType(),
-		StartDate:   startDate,
-		EndDate:     endDate,
-		Desc:        lk.Desc(),
+		Gid:          lk.Gid(),
+		Domain:       lk.Domain(),
+		ShortUri:     lk.ShortUri(),
+		FullShortUrl: lk.FullShortUrl(),
+		OriginalUrl:  lk.OriginalUrl(),
+		Favicon:      lk.Favicon(),
+		Status:       lk.Status(),
+		CreateType:   lk.CreateType(),
+		ValidType:    lk.ValidDate().ValidType(),
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Desc:         lk.Desc(),
+		Password:     lk.Password(),
+		MaxVisits:    lk.MaxVisits(),
+		RoutingRules: lk.RoutingRules(),
+		Variants:     lk.Variants(),
+		UrlParams:    lk.UrlParams(),
+		RedirectCode: lk.RedirectCode(),
+		FallbackUrl:  lk.FallbackUrl(),
+		Tags:         lk.Tags(),
 	}
 }
 
@@ -71,13 +81,23 @@ func (a *LinkAssembler) LinkPoToLinkEntity(po po.Link) *link.Link {
 	if lk, err = a.linkFactory.NewLinkFromDB(
 		po.ID,
 		po.Gid,
+		po.Domain,
 		po.ShortUri,
+		po.FullShortUrl,
 		po.OriginalUrl,
 		po.Status,
 		po.CreateType,
 		po.Favicon,
 		po.Desc,
 		validDate,
+		po.Password,
+		po.MaxVisits,
+		po.RoutingRules,
+		po.Variants,
+		po.UrlParams,
+		po.RedirectCode,
+		po.FallbackUrl,
+		po.Tags,
 	); err != nil {
 		return nil
 	}
@@ -87,6 +107,7 @@ func (a *LinkAssembler) LinkPoToLinkEntity(po po.Link) *link.Link {
 func (a *LinkAssembler) LinkEntityToLinkGotoPo(lk *link.Link) *po.LinkGoto {
 	return &po.LinkGoto{
 		Gid:      lk.Gid(),
+		Domain:   lk.Domain(),
 		ShortUri: lk.ShortUri(),
 	}
 }
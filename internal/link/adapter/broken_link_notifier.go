@@ -0,0 +1,75 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"html"
+	"shortlink/internal/base/mail"
+	"shortlink/internal/link/domain/link"
+	"strings"
+)
+
+// MailBrokenLinkNotifier 通过邮件通知分组所有者跳转目标失效
+//
+// 分组和用户归属于用户服务，两个服务共用数据库，这里直接查询分组所有者的邮箱
+type MailBrokenLinkNotifier struct {
+	db     *gorm.DB
+	sender mail.EmailSender
+	from   string
+}
+
+func NewMailBrokenLinkNotifier(db *gorm.DB, sender mail.EmailSender, from string) *MailBrokenLinkNotifier {
+	if db == nil {
+		panic("nil db")
+	}
+	if sender == nil {
+		panic("nil sender")
+	}
+	return &MailBrokenLinkNotifier{db: db, sender: sender, from: from}
+}
+
+// NotifyBrokenLinks 分组所有者没有设置邮箱时不发送
+func (n MailBrokenLinkNotifier) NotifyBrokenLinks(ctx context.Context, gid string, reports []link.HealthReport) error {
+	var to string
+	if err := n.db.WithContext(ctx).
+		Raw(`
+SELECT u.mail
+FROM t_group g
+JOIN t_user u ON u.username = g.username AND u.delete_time IS NULL
+WHERE g.gid = ? AND g.delete_time IS NULL
+LIMIT 1
+`, gid).
+		Row().Scan(&to); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if to == "" {
+		return nil
+	}
+
+	return n.sender.SendEmail(mail.EmailMessage{
+		From:    n.from,
+		To:      []string{to},
+		Subject: fmt.Sprintf("%d 个短链接的跳转目标已失效", len(reports)),
+		Body:    brokenLinksMailBody(reports),
+	})
+}
+
+func brokenLinksMailBody(reports []link.HealthReport) string {
+	var b strings.Builder
+	b.WriteString("<p>以下短链接的跳转目标无法正常访问，请及时修改：</p>")
+	b.WriteString("<table border=\"1\" cellpadding=\"4\" cellspacing=\"0\">")
+	b.WriteString("<tr><th>短链接</th><th>原始链接</th><th>状态码</th></tr>")
+	for _, r := range reports {
+		status := "无法访问"
+		if r.Check.StatusCode != 0 {
+			status = fmt.Sprint(r.Check.StatusCode)
+		}
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%s</td></tr>",
+			html.EscapeString(r.FullShortUrl), html.EscapeString(r.OriginalUrl), status)
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
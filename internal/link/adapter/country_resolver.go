@@ -0,0 +1,129 @@
+package adapter
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"net/http"
+	"shortlink/internal/base/toolkit"
+	"sync"
+	"time"
+)
+
+const (
+	ipApiEndpoint = "http://ip-api.com/json/"
+	// 查询成功的结果缓存较长时间，IP 的归属国家很少变化
+	countryCacheTTL = 24 * time.Hour
+	// 查询失败时短暂缓存空结果，避免 ip-api 不可用时每次跳转都等待超时
+	countryFailureTTL = time.Minute
+)
+
+// IpApiCountryResolver 通过 ip-api.com 查询 IP 所属国家
+//
+// 查询在跳转的请求路径上，使用较短的超时时间和 LRU 缓存；查询失败时返回空字符串，按未知国家匹配路由规则，不影响跳转
+type IpApiCountryResolver struct {
+	lookup func(ip string) (string, error)
+	now    func() time.Time
+
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List
+}
+
+type countryEntry struct {
+	ip       string
+	country  string
+	expireAt time.Time
+}
+
+func NewIpApiCountryResolver(timeout time.Duration, cacheSize int) *IpApiCountryResolver {
+	client := &http.Client{Timeout: timeout}
+	return newCountryResolver(func(ip string) (string, error) {
+		return lookupIpApiCountry(client, ip)
+	}, cacheSize)
+}
+
+func newCountryResolver(lookup func(ip string) (string, error), cacheSize int) *IpApiCountryResolver {
+	if cacheSize <= 0 {
+		cacheSize = 1
+	}
+	return &IpApiCountryResolver{
+		lookup:   lookup,
+		now:      time.Now,
+		capacity: cacheSize,
+		entries:  make(map[string]*list.Element, cacheSize),
+		lru:      list.New(),
+	}
+}
+
+func (r *IpApiCountryResolver) Country(ip string) string {
+	if ip == "" || toolkit.IsReservedIP(ip) {
+		return ""
+	}
+	if country, ok := r.cached(ip); ok {
+		return country
+	}
+	country, err := r.lookup(ip)
+	if err != nil {
+		r.store(ip, "", countryFailureTTL)
+		return ""
+	}
+	r.store(ip, country, countryCacheTTL)
+	return country
+}
+
+func lookupIpApiCountry(client *http.Client, ip string) (string, error) {
+	resp, err := client.Get(ipApiEndpoint + ip)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	// 超出频率限制时返回 429
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ip-api lookup failed: %s", resp.Status)
+	}
+
+	var location toolkit.Location
+	if err = sonic.ConfigDefault.NewDecoder(resp.Body).Decode(&location); err != nil {
+		return "", err
+	}
+	return location.CountryCode, nil
+}
+
+func (r *IpApiCountryResolver) cached(ip string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elem, ok := r.entries[ip]
+	if !ok {
+		return "", false
+	}
+	entry := elem.Value.(*countryEntry)
+	if !r.now().Before(entry.expireAt) {
+		r.lru.Remove(elem)
+		delete(r.entries, ip)
+		return "", false
+	}
+	r.lru.MoveToFront(elem)
+	return entry.country, true
+}
+
+func (r *IpApiCountryResolver) store(ip, country string, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expireAt := r.now().Add(ttl)
+	if elem, ok := r.entries[ip]; ok {
+		entry := elem.Value.(*countryEntry)
+		entry.country, entry.expireAt = country, expireAt
+		r.lru.MoveToFront(elem)
+		return
+	}
+	r.entries[ip] = r.lru.PushFront(&countryEntry{ip: ip, country: country, expireAt: expireAt})
+	if r.lru.Len() > r.capacity {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*countryEntry).ip)
+	}
+}
@@ -0,0 +1,53 @@
+package adapter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIpApiCountryResolver_Country(t *testing.T) {
+	var calls int
+	r := newCountryResolver(func(ip string) (string, error) {
+		calls++
+		switch ip {
+		case "8.8.8.8":
+			return "US", nil
+		case "1.1.1.1":
+			return "AU", nil
+		default:
+			return "", errors.New("timeout")
+		}
+	}, 2)
+	now := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		ip        string
+		after     time.Duration
+		want      string
+		wantCalls int
+	}{
+		{"lookup", "8.8.8.8", 0, "US", 1},
+		{"cached", "8.8.8.8", 0, "US", 1},
+		{"reserved ip is not looked up", "10.0.0.1", 0, "", 1},
+		{"lookup failure fails open", "4.4.4.4", 0, "", 2},
+		{"failure is cached briefly", "4.4.4.4", 0, "", 2},
+		{"failure is retried after ttl", "4.4.4.4", countryFailureTTL, "", 3},
+		{"lookup evicts least recently used", "1.1.1.1", 0, "AU", 4},
+		{"evicted entry is looked up again", "8.8.8.8", 0, "US", 5},
+		{"expired entry is looked up again", "1.1.1.1", countryCacheTTL, "AU", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.after)
+			if got := r.Country(tt.ip); got != tt.want {
+				t.Errorf("Country(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("lookups = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
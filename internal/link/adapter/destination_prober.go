@@ -0,0 +1,51 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+// HttpDestinationProber 请求跳转目标检查是否可以访问
+//
+// 先发送 HEAD 请求，部分网站不支持 HEAD 或返回错误，失败时再用 GET 重试，跟随跳转后以最终的状态码为准；
+// 检查结果会展示给用户，只连接公网地址，避免通过健康检查探测内网服务
+type HttpDestinationProber struct {
+	client *http.Client
+}
+
+func NewHttpDestinationProber(timeout time.Duration) *HttpDestinationProber {
+	return &HttpDestinationProber{client: newGuardedClient(isPublicIP, timeout)}
+}
+
+func (p HttpDestinationProber) Probe(ctx context.Context, rawUrl string) link.HealthCheck {
+	checkedAt := time.Now()
+	start := checkedAt
+	code, err := p.request(ctx, http.MethodHead, rawUrl)
+	if code == 0 || code >= http.StatusBadRequest {
+		// 跳转目标或跳转后的地址不是公网地址时不再重试，按无法访问处理，也不记录耗时
+		if errors.Is(err, errDialRefused) {
+			return link.HealthCheck{CheckedAt: checkedAt}
+		}
+		start = time.Now()
+		code, _ = p.request(ctx, http.MethodGet, rawUrl)
+	}
+	return link.HealthCheck{StatusCode: code, Latency: time.Since(start), CheckedAt: checkedAt}
+}
+
+// request 返回响应状态码，无法访问时返回 0，只需要状态码，不读取响应内容
+func (p HttpDestinationProber) request(ctx context.Context, method string, rawUrl string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "shortlink-health-checker")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
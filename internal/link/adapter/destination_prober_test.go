@@ -0,0 +1,82 @@
+package adapter
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpDestinationProber_Probe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/internal":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// 测试服务监听在本机地址，默认不允许连接
+	p := NewHttpDestinationProber(time.Second)
+	p.client = newGuardedClient(func(ip net.IP) bool { return ip.IsLoopback() }, time.Second)
+	tests := []struct {
+		name string
+		url  string
+		want int
+	}{
+		{"ok", srv.URL + "/ok", http.StatusOK},
+		{"head not allowed", srv.URL + "/no-head", http.StatusOK},
+		{"not found", srv.URL + "/missing", http.StatusNotFound},
+		{"unreachable", "http://unreachable.invalid", 0},
+		{"redirect to internal", srv.URL + "/internal", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Probe(context.Background(), tt.url)
+			if got.StatusCode != tt.want {
+				t.Errorf("Probe() status = %d, want %d", got.StatusCode, tt.want)
+			}
+			if got.CheckedAt.IsZero() {
+				t.Errorf("Probe() CheckedAt should be set")
+			}
+		})
+	}
+}
+
+func TestHttpDestinationProber_RefuseNonPublic(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	p := NewHttpDestinationProber(time.Second)
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"loopback", srv.URL + "/admin"},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data/"},
+		{"private", "http://10.0.0.1:8080/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Probe(context.Background(), tt.url)
+			if !got.Broken() || got.StatusCode != 0 || got.Latency != 0 {
+				t.Errorf("Probe() = %+v, want broken without latency", got)
+			}
+		})
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d, want 0", requests)
+	}
+}
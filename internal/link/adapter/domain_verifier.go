@@ -0,0 +1,55 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"shortlink/internal/link/domain/link"
+	"strings"
+	"time"
+)
+
+// TxtResolver 查询 DNS TXT 记录，*net.Resolver 满足该接口，测试时可以替换为桩实现
+type TxtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DomainOwnershipVerifier 通过 DNS TXT 记录确认域名所有权
+//
+// 只有能修改域名解析的人才能添加 TXT 记录，域名解析到本服务并不能证明所有权
+type DomainOwnershipVerifier struct {
+	resolver TxtResolver
+	timeout  time.Duration
+}
+
+func NewDomainOwnershipVerifier(resolver TxtResolver, timeout time.Duration) *DomainOwnershipVerifier {
+	if resolver == nil {
+		panic("nil resolver")
+	}
+	return &DomainOwnershipVerifier{resolver: resolver, timeout: timeout}
+}
+
+// Verify 返回验证是否通过，err 不为空表示暂时无法完成验证，调用方不应据此判定验证失败
+func (v DomainOwnershipVerifier) Verify(ctx context.Context, domain *link.ShortDomain) (bool, error) {
+	if v.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.timeout)
+		defer cancel()
+	}
+
+	records, err := v.resolver.LookupTXT(ctx, domain.VerifyRecord())
+	if err != nil {
+		// 记录不存在说明确实没有配置，其他 DNS 错误可能只是暂时的
+		var e *net.DNSError
+		if errors.As(err, &e) && e.IsNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, r := range records {
+		if strings.TrimSpace(r) == domain.VerifyToken() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,55 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"shortlink/internal/link/domain/link"
+	"testing"
+)
+
+// stubTxtResolver 测试用的 DNS 记录
+type stubTxtResolver map[string][]string
+
+func (r stubTxtResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if records, ok := r[name]; ok {
+		return records, nil
+	}
+	if name == "_shortlink-verify.flaky.invalid" {
+		return nil, &net.DNSError{Err: "timeout", Name: name, IsTimeout: true}
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func TestDomainOwnershipVerifier_Verify(t *testing.T) {
+	v := NewDomainOwnershipVerifier(stubTxtResolver{
+		"_shortlink-verify.a.brand1.invalid": {"other", "dns-token"},
+	}, 0)
+
+	tests := []struct {
+		name    string
+		host    string
+		token   string
+		want    bool
+		wantErr bool
+	}{
+		{"dns record", "a.brand1.invalid", "dns-token", true, false},
+		{"dns record with port", "a.brand1.invalid:8443", "dns-token", true, false},
+		{"dns record mismatch", "a.brand1.invalid", "wrong", false, false},
+		{"dns record missing", "b.brand2.invalid", "token", false, false},
+		{"dns temporary error", "flaky.invalid", "token", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := link.NewShortDomainFromDB(tt.host, "alice", link.DomainUnverified, false, tt.token, nil, 0)
+			got, err := v.Verify(context.Background(), d)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("Verify() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+			var dnsErr *net.DNSError
+			if err != nil && !errors.As(err, &dnsErr) {
+				t.Errorf("Verify() error should be a DNS error, got %T", err)
+			}
+		})
+	}
+}
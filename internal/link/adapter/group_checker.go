@@ -0,0 +1,31 @@
+package adapter
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// DBGroupChecker 查询分组的所有者
+//
+// 分组归属于用户服务，两个服务共用数据库，这里直接查询分组表
+type DBGroupChecker struct {
+	db *gorm.DB
+}
+
+func NewDBGroupChecker(db *gorm.DB) *DBGroupChecker {
+	if db == nil {
+		panic("nil db")
+	}
+	return &DBGroupChecker{db: db}
+}
+
+// OwnedBy 分组不存在时返回 false
+func (c DBGroupChecker) OwnedBy(ctx context.Context, gid, username string) (bool, error) {
+	var count int64
+	if err := c.db.WithContext(ctx).
+		Raw(`SELECT COUNT(1) FROM t_group WHERE gid = ? AND username = ? AND delete_time IS NULL`, gid, username).
+		Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/domain/link"
+)
+
+// FileJobResultStore 将任务结果文件保存在本地目录
+//
+// 多实例部署时需要挂载共享目录，否则只能从执行任务的实例下载
+type FileJobResultStore struct {
+	dir string
+}
+
+func NewFileJobResultStore(dir string) (*FileJobResultStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileJobResultStore{dir: dir}, nil
+}
+
+// Create 先写入临时文件，Close 时再重命名，Abort 时删除，避免下载到写了一半的文件
+func (s FileJobResultStore) Create(_ context.Context, name string) (link.JobResultWriter, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &resultFile{File: f, path: path}, nil
+}
+
+func (s FileJobResultStore) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errno.LinkJobNotExists
+	}
+	return f, err
+}
+
+// path 文件名由任务标识生成，这里仍然拒绝包含路径的文件名
+func (s FileJobResultStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", errors.New("invalid result file name")
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+type resultFile struct {
+	*os.File
+	path string
+}
+
+// Close 写入完成，重命名为正式的结果文件
+func (f *resultFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		_ = os.Remove(f.File.Name())
+		return err
+	}
+	return os.Rename(f.File.Name(), f.path)
+}
+
+// Abort 写入失败，删除临时文件
+func (f *resultFile) Abort() error {
+	_ = f.File.Close()
+	return os.Remove(f.File.Name())
+}
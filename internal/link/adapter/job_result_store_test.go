@@ -0,0 +1,76 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestFileJobResultStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileJobResultStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		abort bool
+	}{
+		{"finished", false},
+		{"aborted", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := s.Create(ctx, tt.name+".csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = w.Write([]byte("line,status\n")); err != nil {
+				t.Fatal(err)
+			}
+			// 未完成的结果文件不能下载
+			if _, err = s.Open(ctx, tt.name+".csv"); !errors.Is(err, errno.LinkJobNotExists) {
+				t.Errorf("Open() before Close = %v, want %v", err, errno.LinkJobNotExists)
+			}
+
+			if tt.abort {
+				err = w.Abort()
+			} else {
+				err = w.Close()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := s.Open(ctx, tt.name+".csv")
+			if tt.abort {
+				if !errors.Is(err, errno.LinkJobNotExists) {
+					t.Errorf("Open() after Abort = %v, want %v", err, errno.LinkJobNotExists)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, _ := io.ReadAll(r)
+				_ = r.Close()
+				if string(body) != "line,status\n" {
+					t.Errorf("result = %q", body)
+				}
+			}
+		})
+	}
+
+	// 临时文件都已经重命名或删除
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "finished.csv" {
+		t.Errorf("result dir = %v, want only finished.csv", entries)
+	}
+}
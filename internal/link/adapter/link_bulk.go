@@ -0,0 +1,250 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gorm.io/gorm"
+	"shortlink/internal/base/cache"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/adapter/assembler"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/common/constant"
+	"shortlink/internal/link/domain/link"
+	"sort"
+	"time"
+)
+
+func (r LinkRepository) BulkUpdateLinks(
+	ctx context.Context,
+	ids []link.Identifier,
+	action link.BulkAction,
+) ([]link.BulkItemResult, error) {
+	return linkBulkUpdater{db: r.db, distributedCache: r.distributedCache, assembler: r.assembler}.
+		update(ctx, ids, action)
+}
+
+func (r LinkShardingRepository) BulkUpdateLinks(
+	ctx context.Context,
+	ids []link.Identifier,
+	action link.BulkAction,
+) ([]link.BulkItemResult, error) {
+	return linkBulkUpdater{db: r.db, distributedCache: r.distributedCache, assembler: r.assembler, sharding: true}.
+		update(ctx, ids, action)
+}
+
+// linkBulkUpdater 批量修改短链接
+//
+// 短链接按分组（分片键）划分，每个分组在一个事务中修改，某个分组失败时只回滚该分组
+type linkBulkUpdater struct {
+	db               *gorm.DB
+	distributedCache cache.DistributedCache
+	assembler        assembler.LinkAssembler
+	// 分片版需要通过 link_goto 定位分组，移动分组时需要在分片之间迁移数据
+	sharding bool
+}
+
+func (u linkBulkUpdater) update(
+	ctx context.Context,
+	ids []link.Identifier,
+	action link.BulkAction,
+) ([]link.BulkItemResult, error) {
+	res := make([]link.BulkItemResult, len(ids))
+	for i, id := range ids {
+		res[i] = link.BulkItemResult{Domain: id.Domain, ShortUri: id.ShortUri, Err: errno.LinkNotExists}
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	groups, err := u.locate(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	gids := make([]string, 0, len(groups))
+	for gid := range groups {
+		gids = append(gids, gid)
+	}
+	sort.Strings(gids)
+
+	// 缓存更新失败时数据库已经提交，不影响单个短链接的结果，处理完所有分组后返回
+	var cacheErr error
+	for _, gid := range gids {
+		idxs := groups[gid]
+		for _, idx := range idxs {
+			res[idx].Gid = gid
+		}
+		changed, err := u.updateGroup(ctx, gid, ids, idxs, action, res)
+		if err != nil {
+			// 事务已回滚，该分组下的短链接都视为失败
+			for _, idx := range idxs {
+				if res[idx].Err == nil {
+					res[idx].Err = err
+				}
+			}
+			continue
+		}
+		if err = u.refreshCache(ctx, gid, action, changed); err != nil {
+			cacheErr = err
+		}
+	}
+	return res, cacheErr
+}
+
+// locate 查询短链接所在的分组，返回分组到 ids 下标的映射，不存在的短链接不会出现在结果中
+func (u linkBulkUpdater) locate(ctx context.Context, ids []link.Identifier) (map[string][]int, error) {
+	keys := make([][]any, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, []any{id.Domain, id.ShortUri})
+	}
+
+	table := po.TableNameLink
+	if u.sharding {
+		table = po.TableNameLinkGoto
+	}
+	var rows []po.LinkGoto
+	if err := u.db.WithContext(ctx).
+		Table(table).
+		Select("gid", "domain", "short_uri").
+		Where("(domain, short_uri) IN ? AND delete_time IS NULL", keys).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	gidOf := make(map[string]string, len(rows))
+	for _, row := range rows {
+		gidOf[link.Key(row.Domain, row.ShortUri)] = row.Gid
+	}
+	groups := make(map[string][]int)
+	for i, id := range ids {
+		if gid, ok := gidOf[link.Key(id.Domain, id.ShortUri)]; ok {
+			groups[gid] = append(groups[gid], i)
+		}
+	}
+	return groups, nil
+}
+
+func (u linkBulkUpdater) updateGroup(
+	ctx context.Context,
+	gid string,
+	ids []link.Identifier,
+	idxs []int,
+	action link.BulkAction,
+	res []link.BulkItemResult,
+) ([]*link.Link, error) {
+	keys := make([][]any, 0, len(idxs))
+	for _, idx := range idxs {
+		keys = append(keys, []any{ids[idx].Domain, ids[idx].ShortUri})
+	}
+
+	var changed []*link.Link
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []po.Link
+		if err := tx.
+			Where("gid = ? AND (domain, short_uri) IN ? AND tenant_id = ?", gid, keys, ctx.Value("username")).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		rowOf := make(map[string]po.Link, len(rows))
+		for _, row := range rows {
+			rowOf[link.Key(row.Domain, row.ShortUri)] = row
+		}
+
+		for _, idx := range idxs {
+			row, ok := rowOf[link.Key(ids[idx].Domain, ids[idx].ShortUri)]
+			if !ok {
+				// 其他用户的短链接同样视为不存在
+				continue
+			}
+			if row.RecycleTime.Valid {
+				res[idx].Err = errno.LinkInvalidStatus
+				continue
+			}
+			lk := u.assembler.LinkPoToLinkEntity(row)
+			if lk == nil {
+				res[idx].Err = errno.LinkInvalidStatus
+				continue
+			}
+			if err := action.Apply(lk); err != nil {
+				res[idx].Err = err
+				continue
+			}
+			if err := u.save(tx, action, row, lk); err != nil {
+				return err
+			}
+			res[idx].Err = nil
+			changed = append(changed, lk)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errno.LinkAlreadyExists
+		}
+		return nil, err
+	}
+	return changed, nil
+}
+
+// save 保存一个短链接，分片版移动分组时与 UpdateLink 相同，从原分片迁移到目标分片
+func (u linkBulkUpdater) save(tx *gorm.DB, action link.BulkAction, row po.Link, lk *link.Link) error {
+	now := time.Now()
+	if action.Op == link.BulkRecycle {
+		return tx.Model(&po.Link{}).
+			Where("gid = ? AND id = ?", row.Gid, row.ID).
+			Updates(map[string]any{"recycle_time": sql.NullTime{Time: now, Valid: true}, "update_time": now}).Error
+	}
+
+	updated := u.assembler.LinkEntityToLinkPo(lk)
+	if u.sharding && updated.Gid != row.Gid {
+		return moveLinkToGroup(tx, row, updated)
+	}
+
+	// 与 UpdateLink 相同，只更新实体中包含的字段
+	updated.UpdateTime = now
+	return tx.Model(&po.Link{}).
+		Where("gid = ? AND id = ?", row.Gid, row.ID).
+		Select("*").
+		Omit("id", "create_time", "delete_time", "tenant_id", "recycle_time",
+			"og_image", "og_description", "health_status", "health_latency", "health_check_time").
+		Updates(&updated).Error
+}
+
+// refreshCache 事务提交后更新跳转缓存和分组数量
+func (u linkBulkUpdater) refreshCache(ctx context.Context, gid string, action link.BulkAction, changed []*link.Link) error {
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if action.Op == link.BulkRecycle {
+		// 回源查询不过滤回收站，需要在缓存中标记为已删除
+		for _, lk := range changed {
+			cacheValue := link.NewCacheValue(lk)
+			cacheValue.Status = link.StatusDeleted
+			if err := u.distributedCache.Put(ctx, constant.GotoLinkKey+lk.Key(), cacheValue, cacheValue.Expiration()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(changed))
+	for _, lk := range changed {
+		keys = append(keys, constant.GotoLinkKey+lk.Key())
+	}
+	if _, err := u.distributedCache.DeleteMultiple(ctx, keys); err != nil {
+		return err
+	}
+
+	if action.Op == link.BulkMove && action.Gid != gid {
+		username, _ := ctx.Value("username").(string)
+		n := int64(len(changed))
+		if _, err := u.distributedCache.HIncrBy(ctx, constant.LinkGroupCountKey+username, gid, -n); err != nil {
+			return err
+		}
+		if _, err := u.distributedCache.HIncrBy(ctx, constant.LinkGroupCountKey+username, action.Gid, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
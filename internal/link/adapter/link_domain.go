@@ -0,0 +1,179 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gorm.io/gorm"
+	"shortlink/internal/base/cache"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+// 域名表不参与分片，分库分表版只是在检查域名是否被使用和停用域名下的短链接时需要遍历所有分片
+
+func (r LinkRepository) GetShortDomain(ctx context.Context, host string) (*link.ShortDomain, error) {
+	return getShortDomain(ctx, r.db, host)
+}
+
+func (r LinkRepository) CreateShortDomain(ctx context.Context, domain *link.ShortDomain) error {
+	return createShortDomain(ctx, r.db, domain)
+}
+
+func (r LinkRepository) RemoveShortDomain(ctx context.Context, host string) error {
+	return removeShortDomain(ctx, r.db, []string{po.TableNameLink}, host)
+}
+
+func (r LinkRepository) ListAllShortDomains(ctx context.Context) ([]*link.ShortDomain, error) {
+	return listAllShortDomains(ctx, r.db)
+}
+
+func (r LinkRepository) SaveShortDomainVerification(ctx context.Context, domain *link.ShortDomain) error {
+	return saveShortDomainVerification(ctx, r.db, domain)
+}
+
+func (r LinkRepository) DisableDomainLinks(ctx context.Context, host string) ([]link.StatusChange, error) {
+	return disableDomainLinks(ctx, r.db, r.distributedCache, []string{po.TableNameLink}, host)
+}
+
+func (r LinkShardingRepository) GetShortDomain(ctx context.Context, host string) (*link.ShortDomain, error) {
+	return getShortDomain(ctx, r.db, host)
+}
+
+func (r LinkShardingRepository) CreateShortDomain(ctx context.Context, domain *link.ShortDomain) error {
+	return createShortDomain(ctx, r.db, domain)
+}
+
+func (r LinkShardingRepository) RemoveShortDomain(ctx context.Context, host string) error {
+	return removeShortDomain(ctx, r.db, linkShardTables(), host)
+}
+
+func (r LinkShardingRepository) ListAllShortDomains(ctx context.Context) ([]*link.ShortDomain, error) {
+	return listAllShortDomains(ctx, r.db)
+}
+
+func (r LinkShardingRepository) SaveShortDomainVerification(ctx context.Context, domain *link.ShortDomain) error {
+	return saveShortDomainVerification(ctx, r.db, domain)
+}
+
+func (r LinkShardingRepository) DisableDomainLinks(ctx context.Context, host string) ([]link.StatusChange, error) {
+	return disableDomainLinks(ctx, r.db, r.distributedCache, linkShardTables(), host)
+}
+
+func getShortDomain(ctx context.Context, db *gorm.DB, host string) (*link.ShortDomain, error) {
+	var domainPo po.LinkDomain
+	if err := db.WithContext(ctx).Where("host = ?", host).First(&domainPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errno.LinkDomainNotExists
+		}
+		return nil, err
+	}
+	return toShortDomain(domainPo), nil
+}
+
+func toShortDomain(domainPo po.LinkDomain) *link.ShortDomain {
+	var checkedAt *time.Time
+	if domainPo.CheckedTime.Valid {
+		checkedAt = &domainPo.CheckedTime.Time
+	}
+	return link.NewShortDomainFromDB(
+		domainPo.Host,
+		domainPo.Owner,
+		domainPo.Status,
+		domainPo.UseTLS,
+		domainPo.VerifyToken,
+		checkedAt,
+		domainPo.VerifyFailures,
+	)
+}
+
+func createShortDomain(ctx context.Context, db *gorm.DB, domain *link.ShortDomain) error {
+	domainPo := po.LinkDomain{
+		Host:        domain.Host(),
+		Owner:       domain.Owner(),
+		Status:      domain.Status(),
+		UseTLS:      domain.UseTLS(),
+		VerifyToken: domain.VerifyToken(),
+	}
+	if err := db.WithContext(ctx).Create(&domainPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errno.LinkDomainAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// removeShortDomain 删除域名，回收站中的短链接同样占用域名
+func removeShortDomain(ctx context.Context, db *gorm.DB, tables []string, host string) error {
+	for _, table := range tables {
+		var count int64
+		if err := db.WithContext(ctx).
+			Table(table).
+			Where("domain = ? AND delete_time IS NULL", host).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errno.LinkDomainInUse
+		}
+	}
+	res := db.WithContext(ctx).Where("host = ?", host).Delete(&po.LinkDomain{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errno.LinkDomainNotExists
+	}
+	return nil
+}
+
+func listAllShortDomains(ctx context.Context, db *gorm.DB) ([]*link.ShortDomain, error) {
+	var domainPos []po.LinkDomain
+	if err := db.WithContext(ctx).Find(&domainPos).Error; err != nil {
+		return nil, err
+	}
+	res := make([]*link.ShortDomain, 0, len(domainPos))
+	for _, domainPo := range domainPos {
+		res = append(res, toShortDomain(domainPo))
+	}
+	return res, nil
+}
+
+func saveShortDomainVerification(ctx context.Context, db *gorm.DB, domain *link.ShortDomain) error {
+	checkedTime := sql.NullTime{}
+	if domain.CheckedAt() != nil {
+		checkedTime = sql.NullTime{Time: *domain.CheckedAt(), Valid: true}
+	}
+	return db.WithContext(ctx).
+		Model(&po.LinkDomain{}).
+		Where("host = ?", domain.Host()).
+		Updates(map[string]any{
+			"status":          domain.Status(),
+			"checked_time":    checkedTime,
+			"verify_failures": domain.Failures(),
+			"update_time":     time.Now(),
+		}).Error
+}
+
+// disableDomainLinks 停用域名下所有可以访问的短链接，并清除跳转缓存
+func disableDomainLinks(
+	ctx context.Context,
+	db *gorm.DB,
+	distributedCache cache.DistributedCache,
+	tables []string,
+	host string,
+) ([]link.StatusChange, error) {
+	var res []link.StatusChange
+	for _, from := range []link.Status{link.StatusActive, link.StatusPending} {
+		changes, err := transitLinkStatus(ctx, db, distributedCache, tables, from, link.StatusDisabled,
+			"domain = ?", host)
+		res = append(res, changes...)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
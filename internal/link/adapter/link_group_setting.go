@@ -0,0 +1,36 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+)
+
+// 分组默认配置表不参与分片，分库分表版和单表版的读写逻辑相同
+
+func getGroupSetting(ctx context.Context, db *gorm.DB, gid string) (*link.GroupSetting, error) {
+	var settingPo po.LinkGroupSetting
+	err := db.WithContext(ctx).Where("gid = ?", gid).First(&settingPo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return link.NewGroupSetting(gid, nil, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return link.NewGroupSetting(settingPo.Gid, settingPo.UrlParams, settingPo.FallbackUrl)
+}
+
+func saveGroupSetting(ctx context.Context, db *gorm.DB, setting *link.GroupSetting) error {
+	settingPo := po.LinkGroupSetting{
+		Gid:         setting.Gid(),
+		UrlParams:   setting.UrlParams(),
+		FallbackUrl: setting.FallbackUrl(),
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "gid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"url_params", "fallback_url", "update_time"}),
+	}).Create(&settingPo).Error
+}
@@ -0,0 +1,80 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gorm.io/gorm"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+func (r LinkRepository) SampleActiveLinks(ctx context.Context, perGroup int) ([]link.HealthTarget, error) {
+	return sampleActiveLinks(ctx, r.db, []string{po.TableNameLink}, perGroup)
+}
+
+func (r LinkRepository) SaveHealthCheck(ctx context.Context, id link.Identifier, check link.HealthCheck) error {
+	return saveHealthCheck(ctx, r.db, id, check)
+}
+
+func (r LinkShardingRepository) SampleActiveLinks(ctx context.Context, perGroup int) ([]link.HealthTarget, error) {
+	return sampleActiveLinks(ctx, r.db, linkShardTables(), perGroup)
+}
+
+func (r LinkShardingRepository) SaveHealthCheck(ctx context.Context, id link.Identifier, check link.HealthCheck) error {
+	return saveHealthCheck(ctx, r.db, id, check)
+}
+
+// sampleActiveLinks 每个分组按检查时间选取，从未检查的优先，多次执行后轮流覆盖分组内的所有短链接
+func sampleActiveLinks(
+	ctx context.Context,
+	db *gorm.DB,
+	tables []string,
+	perGroup int,
+) ([]link.HealthTarget, error) {
+	var res []link.HealthTarget
+	for _, table := range tables {
+		rawSql := fmt.Sprintf(`
+SELECT gid, domain, short_uri, full_short_url, original_url, health_status, health_latency, health_check_time
+FROM (
+    SELECT *, ROW_NUMBER() OVER (PARTITION BY gid ORDER BY health_check_time NULLS FIRST, id) AS rn
+    FROM %s
+    WHERE status = ? AND delete_time IS NULL AND recycle_time IS NULL
+) t
+WHERE rn <= ?
+`, table)
+		var rows []po.Link
+		if err := db.WithContext(ctx).Raw(rawSql, link.StatusActive, perGroup).Scan(&rows).Error; err != nil {
+			return res, err
+		}
+		for _, row := range rows {
+			target := link.HealthTarget{
+				Identifier:   link.Identifier{Gid: row.Gid, Domain: row.Domain, ShortUri: row.ShortUri},
+				FullShortUrl: row.FullShortUrl,
+				OriginalUrl:  row.OriginalUrl,
+			}
+			if row.HealthCheckTime.Valid {
+				target.Last = &link.HealthCheck{
+					StatusCode: row.HealthStatus,
+					Latency:    time.Duration(row.HealthLatency) * time.Millisecond,
+					CheckedAt:  row.HealthCheckTime.Time,
+				}
+			}
+			res = append(res, target)
+		}
+	}
+	return res, nil
+}
+
+// saveHealthCheck 检查结果不属于用户修改，不更新 update_time
+func saveHealthCheck(ctx context.Context, db *gorm.DB, id link.Identifier, check link.HealthCheck) error {
+	return db.WithContext(ctx).
+		Table(po.TableNameLink).
+		Where("gid = ? AND domain = ? AND short_uri = ?", id.Gid, id.Domain, id.ShortUri).
+		Updates(map[string]any{
+			"health_status":     check.StatusCode,
+			"health_latency":    check.Latency.Milliseconds(),
+			"health_check_time": sql.NullTime{Time: check.CheckedAt, Valid: true},
+		}).Error
+}
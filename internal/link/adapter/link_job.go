@@ -0,0 +1,87 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+// 任务表不参与分片，分库分表版和单表版的读写逻辑相同
+
+func (r LinkRepository) GetJob(ctx context.Context, id string) (*link.Job, error) {
+	return getJob(ctx, r.db, id)
+}
+
+func (r LinkRepository) SaveJob(ctx context.Context, job *link.Job) error {
+	return saveJob(ctx, r.db, job)
+}
+
+func (r LinkShardingRepository) GetJob(ctx context.Context, id string) (*link.Job, error) {
+	return getJob(ctx, r.db, id)
+}
+
+func (r LinkShardingRepository) SaveJob(ctx context.Context, job *link.Job) error {
+	return saveJob(ctx, r.db, job)
+}
+
+func getJob(ctx context.Context, db *gorm.DB, id string) (*link.Job, error) {
+	var jobPo po.LinkJob
+	if err := db.WithContext(ctx).Where("job_id = ?", id).First(&jobPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errno.LinkJobNotExists
+		}
+		return nil, err
+	}
+	var finishTime *time.Time
+	if jobPo.FinishTime.Valid {
+		finishTime = &jobPo.FinishTime.Time
+	}
+	return link.NewJobFromDB(
+		jobPo.JobId,
+		jobPo.Type,
+		jobPo.Owner,
+		jobPo.Status,
+		jobPo.DryRun,
+		jobPo.Total,
+		jobPo.Processed,
+		jobPo.Succeeded,
+		jobPo.Failed,
+		jobPo.RowErrors,
+		jobPo.ResultFile,
+		jobPo.Message,
+		finishTime,
+	), nil
+}
+
+// saveJob 按任务标识新建或更新任务进度
+func saveJob(ctx context.Context, db *gorm.DB, job *link.Job) error {
+	jobPo := po.LinkJob{
+		JobId:      job.Id(),
+		Type:       job.Type(),
+		Owner:      job.Owner(),
+		Status:     job.Status(),
+		DryRun:     job.DryRun(),
+		Total:      job.Total(),
+		Processed:  job.Processed(),
+		Succeeded:  job.Succeeded(),
+		Failed:     job.Failed(),
+		RowErrors:  job.RowErrors(),
+		ResultFile: job.ResultFile(),
+		Message:    job.Message(),
+	}
+	if t := job.FinishTime(); t != nil {
+		jobPo.FinishTime = sql.NullTime{Time: *t, Valid: true}
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "job_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"status", "processed", "succeeded", "failed", "row_errors", "result_file", "message", "finish_time", "update_time",
+		}),
+	}).Create(&jobPo).Error
+}
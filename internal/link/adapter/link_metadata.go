@@ -0,0 +1,34 @@
+package adapter
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+)
+
+func (r LinkRepository) SaveLinkMetadata(ctx context.Context, id link.Identifier, metadata link.Metadata) error {
+	return saveLinkMetadata(ctx, r.db, id, metadata)
+}
+
+func (r LinkShardingRepository) SaveLinkMetadata(ctx context.Context, id link.Identifier, metadata link.Metadata) error {
+	return saveLinkMetadata(ctx, r.db, id, metadata)
+}
+
+// saveLinkMetadata 跳转缓存中不包含描述和图标，不需要清除缓存
+func saveLinkMetadata(ctx context.Context, db *gorm.DB, id link.Identifier, metadata link.Metadata) error {
+	updates := map[string]any{
+		"og_image":       metadata.Image,
+		"og_description": metadata.Description,
+	}
+	if metadata.Favicon != "" {
+		updates["favicon"] = metadata.Favicon
+	}
+	if summary := metadata.Summary(); summary != "" {
+		updates["desc"] = gorm.Expr("CASE WHEN \"desc\" = '' OR \"desc\" IS NULL THEN ? ELSE \"desc\" END", summary)
+	}
+	return db.WithContext(ctx).
+		Table(po.TableNameLink).
+		Where("gid = ? AND domain = ? AND short_uri = ?", id.Gid, id.Domain, id.ShortUri).
+		Updates(updates).Error
+}
@@ -35,12 +35,12 @@ func NewLinkRepository(
 	}
 }
 
-func (r LinkRepository) ShortUriExists(ctx context.Context, shortUri string) (bool, error) {
+func (r LinkRepository) ShortUriExists(ctx context.Context, key string) (bool, error) {
 	return r.distributedCache.ExistsInBloomFilter(
 		ctx,
 		cache.ShortUriCreateBloomFilter,
-		shortUri,
-		constant.GotoIsNullLinkKey+shortUri,
+		key,
+		constant.GotoIsNullLinkKey+key,
 	)
 }
 
@@ -67,6 +67,14 @@ func (r LinkRepository) CountLinksByGid(ctx context.Context, gid string) (int, e
 	}
 }
 
+func (r LinkRepository) GetGroupSetting(ctx context.Context, gid string) (*link.GroupSetting, error) {
+	return getGroupSetting(ctx, r.db, gid)
+}
+
+func (r LinkRepository) SaveGroupSetting(ctx context.Context, setting *link.GroupSetting) error {
+	return saveGroupSetting(ctx, r.db, setting)
+}
+
 func (r LinkRepository) CreateLink(ctx context.Context, lk *link.Link) error {
 	// 大概消耗时间：50ms
 	//if exists, err := r.distributedCache.ExistsInBloomFilter(
@@ -92,11 +100,11 @@ func (r LinkRepository) CreateLink(ctx context.Context, lk *link.Link) error {
 	cacheValue := link.NewCacheValue(lk)
 	if err := r.distributedCache.SafePut(
 		ctx,
-		constant.GotoLinkKey+lk.ShortUri(),
+		constant.GotoLinkKey+lk.Key(),
 		cacheValue,
 		cacheValue.Expiration(),
 		cache.ShortUriCreateBloomFilter,
-		lk.ShortUri(),
+		lk.Key(),
 	); err != nil {
 		return err
 	}
@@ -126,17 +134,20 @@ func (r LinkRepository) CreateLinkBatch(ctx context.Context, links []*link.Link)
 		}
 		return nil
 	}); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errno.LinkAlreadyExists
+		}
 		return err
 	}
 
 	for _, lk := range links {
 		err := r.distributedCache.SafePut(
 			ctx,
-			constant.GotoLinkKey+lk.ShortUri(),
+			constant.GotoLinkKey+lk.Key(),
 			link.NewCacheValue(lk),
 			lk.ValidDate().Expiration(),
 			cache.ShortUriCreateBloomFilter,
-			lk.ShortUri(),
+			lk.Key(),
 		)
 		if err != nil {
 			return err
@@ -148,6 +159,7 @@ func (r LinkRepository) CreateLinkBatch(ctx context.Context, links []*link.Link)
 
 func (r LinkRepository) UpdateLink(
 	ctx context.Context,
+	domain string,
 	shortUri string,
 	updateFn func(ctx context.Context, link *link.Link) (*link.Link, error),
 ) error {
@@ -155,7 +167,7 @@ func (r LinkRepository) UpdateLink(
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := r.db.WithContext(ctx).
 			Model(&linkPo).
-			Where("short_uri = ?", shortUri).
+			Where("domain = ? AND short_uri = ?", domain, shortUri).
 			First(&linkPo).Error; err != nil {
 			return err
 		}
@@ -168,14 +180,23 @@ func (r LinkRepository) UpdateLink(
 		}
 
 		linkPo = r.assembler.LinkEntityToLinkPo(lk)
-		return r.db.WithContext(ctx).Updates(&linkPo).Error
+		// 密码等字段允许被清空，因此需要更新零值，但实体中不包含的字段要排除
+		return r.db.WithContext(ctx).
+			Select("*").
+			Omit("create_time", "update_time", "delete_time", "tenant_id", "recycle_time",
+				"og_image", "og_description", "health_status", "health_latency", "health_check_time").
+			Updates(&linkPo).Error
 
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errno.LinkNotExists
 		}
+		return err
 	}
+
+	// 删除缓存 下次访问时重新加载
+	_, err = r.distributedCache.Delete(ctx, constant.GotoLinkKey+link.Key(domain, shortUri))
 	return err
 }
 
@@ -186,7 +207,7 @@ func (r LinkRepository) SaveToRecycleBin(ctx context.Context, id link.Identifier
 		// 查询
 		if err := r.db.WithContext(ctx).
 			Model(&linkPo).
-			Where("short_uri = ?", id.ShortUri).
+			Where("domain = ? AND short_uri = ?", id.Domain, id.ShortUri).
 			First(&linkPo).Error; err != nil {
 			return err
 		}
@@ -211,7 +232,7 @@ func (r LinkRepository) SaveToRecycleBin(ctx context.Context, id link.Identifier
 	}
 
 	// 修改缓存中的状态为已删除
-	cacheKey := constant.GotoLinkKey + id.ShortUri
+	cacheKey := constant.GotoLinkKey + link.Key(id.Domain, id.ShortUri)
 	if err = r.modifyCacheValueStatus(ctx, cacheKey, link.StatusDeleted); err != nil {
 		return err
 	}
@@ -223,7 +244,7 @@ func (r LinkRepository) RemoveFromRecycleBin(ctx context.Context, id link.Identi
 		var linkPo po.Link
 		if err = r.db.WithContext(ctx).
 			Model(&linkPo).
-			Where("short_uri = ?", id.ShortUri).
+			Where("domain = ? AND short_uri = ?", id.Domain, id.ShortUri).
 			First(&linkPo).Error; err != nil {
 			return
 		}
@@ -245,8 +266,8 @@ func (r LinkRepository) RemoveFromRecycleBin(ctx context.Context, id link.Identi
 
 	// 删除缓存
 	if err = r.distributedCache.SafeDelete(
-		ctx, constant.GotoLinkKey+id.ShortUri,
-		constant.GotoIsNullLinkKey+id.ShortUri,
+		ctx, constant.GotoLinkKey+link.Key(id.Domain, id.ShortUri),
+		constant.GotoIsNullLinkKey+link.Key(id.Domain, id.ShortUri),
 	); err != nil {
 		return err
 	}
@@ -259,7 +280,7 @@ func (r LinkRepository) RecoverFromRecycleBin(ctx context.Context, id link.Ident
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		if err := r.db.WithContext(ctx).Model(&linkPo).
-			Where("short_uri = ?", id.ShortUri).
+			Where("domain = ? AND short_uri = ?", id.Domain, id.ShortUri).
 			First(&linkPo).Error; err != nil {
 			return err
 		}
@@ -283,7 +304,7 @@ func (r LinkRepository) RecoverFromRecycleBin(ctx context.Context, id link.Ident
 		return err
 	}
 
-	cacheKey := constant.GotoLinkKey + id.ShortUri
+	cacheKey := constant.GotoLinkKey + link.Key(id.Domain, id.ShortUri)
 	if err = r.modifyCacheValueStatus(ctx, cacheKey, link.Status(linkPo.Status)); err != nil {
 		return err
 	}
@@ -303,3 +324,31 @@ func (r LinkRepository) modifyCacheValueStatus(ctx context.Context, cacheKey str
 	}
 	return
 }
+
+// ConsumeVisit 消耗一次访问次数
+//
+// 通过 Redis 计数器保证并发下的原子性，恰好用完次数的请求负责将短链接置为过期，
+// 之后即便缓存尚未失效，计数器也会拒绝多余的访问
+func (r LinkRepository) ConsumeVisit(ctx context.Context, domain, shortUri string, cacheValue *link.CacheValue) (bool, error) {
+	key := link.Key(domain, shortUri)
+	count, err := r.distributedCache.Incr(ctx, constant.LinkVisitCountKey+key, cacheValue.Expiration())
+	if err != nil {
+		return false, err
+	}
+	maxVisits := int64(cacheValue.MaxVisits)
+	if count > maxVisits {
+		return false, nil
+	}
+	if count == maxVisits {
+		if err = r.db.WithContext(ctx).
+			Model(&po.Link{}).
+			Where("domain = ? AND short_uri = ? AND status = ?", domain, shortUri, link.StatusActive).
+			Update("status", link.StatusExpired).Error; err != nil {
+			return false, err
+		}
+		if _, err = r.distributedCache.Delete(ctx, constant.GotoLinkKey+key); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,124 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"shortlink/internal/base/cache"
+	"shortlink/internal/link/common/constant"
+	"shortlink/internal/link/domain/link"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingCache 测试用的访问计数，只实现 ConsumeVisit 用到的方法
+type countingCache struct {
+	cache.DistributedCache
+	counts  map[string]int64
+	deleted []string
+}
+
+func (c *countingCache) Incr(_ context.Context, key string, _ time.Duration) (int64, error) {
+	c.counts[key]++
+	return c.counts[key], nil
+}
+
+func (c *countingCache) Delete(_ context.Context, key string) (bool, error) {
+	c.deleted = append(c.deleted, key)
+	return true, nil
+}
+
+// newDryRunDB 只生成 SQL 不连接数据库，通过回调检查生成的语句
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunConnPool{}}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// dryRunConnPool 视为已经处于事务中，Transaction 只会生成 SAVEPOINT 语句而不会建立连接
+type dryRunConnPool struct{}
+
+var errDryRun = errors.New("dry run connection")
+
+func (dryRunConnPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errDryRun
+}
+
+func (dryRunConnPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errDryRun
+}
+
+func (dryRunConnPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+
+func (dryRunConnPool) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (dryRunConnPool) Commit() error   { return nil }
+func (dryRunConnPool) Rollback() error { return nil }
+
+func TestLinkRepository_ConsumeVisit(t *testing.T) {
+	tests := []struct {
+		name      string
+		domain    string
+		maxVisits int
+		visits    int
+		// 每次访问是否被允许
+		want []bool
+		// 置为过期的次数
+		wantExpire int
+	}{
+		{"single visit", "", 1, 2, []bool{true, false}, 1},
+		{"quota exhausted", "", 3, 5, []bool{true, true, true, false, false}, 1},
+		{"quota not reached", "", 3, 2, []bool{true, true}, 0},
+		{"custom domain", "go.brand.invalid", 2, 3, []bool{true, true, false}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+			var updates []string
+			if err := db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+				updates = append(updates, tx.Statement.SQL.String())
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			distributedCache := &countingCache{counts: map[string]int64{}}
+			repo := LinkRepository{db: db, distributedCache: distributedCache}
+			cacheValue := &link.CacheValue{NeverExpire: true, Status: link.StatusActive, MaxVisits: tt.maxVisits}
+
+			for i := 0; i < tt.visits; i++ {
+				ok, err := repo.ConsumeVisit(context.Background(), tt.domain, "abc123", cacheValue)
+				if err != nil {
+					t.Fatalf("visit %d: unexpected error: %v", i+1, err)
+				}
+				if ok != tt.want[i] {
+					t.Errorf("visit %d: ConsumeVisit() = %v, want %v", i+1, ok, tt.want[i])
+				}
+			}
+
+			if len(updates) != tt.wantExpire {
+				t.Fatalf("expire updates = %d, want %d", len(updates), tt.wantExpire)
+			}
+			if tt.wantExpire == 0 {
+				return
+			}
+			if !strings.Contains(updates[0], `"status"`) || !strings.Contains(updates[0], "domain = ") {
+				t.Errorf("unexpected expire update: %s", updates[0])
+			}
+			wantDeleted := constant.GotoLinkKey + link.Key(tt.domain, "abc123")
+			if len(distributedCache.deleted) != 1 || distributedCache.deleted[0] != wantDeleted {
+				t.Errorf("deleted cache keys = %v, want [%s]", distributedCache.deleted, wantDeleted)
+			}
+		})
+	}
+}
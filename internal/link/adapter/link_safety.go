@@ -0,0 +1,87 @@
+package adapter
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"shortlink/internal/base/cache"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+)
+
+// scanBatchSize 每次从单个分片读取的短链接数量
+const scanBatchSize = 500
+
+// forbiddableStatuses 可以访问或即将可以访问的短链接，跳转目标不安全时需要禁用
+var forbiddableStatuses = []link.Status{link.StatusActive, link.StatusPending}
+
+func (r LinkRepository) ScanAvailableLinks(ctx context.Context, fn func([]link.LinkTargets) error) error {
+	return scanAvailableLinks(ctx, r.db, []string{po.TableNameLink}, fn)
+}
+
+func (r LinkRepository) ForbidLinks(ctx context.Context, ids []link.Identifier) ([]link.StatusChange, error) {
+	return forbidLinks(ctx, r.db, r.distributedCache, []string{po.TableNameLink}, ids)
+}
+
+func (r LinkShardingRepository) ScanAvailableLinks(ctx context.Context, fn func([]link.LinkTargets) error) error {
+	return scanAvailableLinks(ctx, r.db, linkShardTables(), fn)
+}
+
+func (r LinkShardingRepository) ForbidLinks(ctx context.Context, ids []link.Identifier) ([]link.StatusChange, error) {
+	return forbidLinks(ctx, r.db, r.distributedCache, linkShardTables(), ids)
+}
+
+// scanAvailableLinks 按主键分批读取，fn 中修改短链接状态不影响后续批次
+func scanAvailableLinks(
+	ctx context.Context,
+	db *gorm.DB,
+	tables []string,
+	fn func([]link.LinkTargets) error,
+) error {
+	for _, table := range tables {
+		var rows []po.Link
+		if err := db.WithContext(ctx).
+			Table(table).
+			Select("id", "gid", "domain", "short_uri", "original_url", "routing_rules", "variants", "fallback_url").
+			Where("status IN ?", forbiddableStatuses).
+			FindInBatches(&rows, scanBatchSize, func(tx *gorm.DB, batch int) error {
+				targets := make([]link.LinkTargets, 0, len(rows))
+				for _, row := range rows {
+					targets = append(targets, link.LinkTargets{
+						Identifier: link.Identifier{Gid: row.Gid, Domain: row.Domain, ShortUri: row.ShortUri},
+						Urls:       link.Destinations(row.OriginalUrl, row.RoutingRules, row.Variants, row.FallbackUrl),
+					})
+				}
+				return fn(targets)
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forbidLinks(
+	ctx context.Context,
+	db *gorm.DB,
+	distributedCache cache.DistributedCache,
+	tables []string,
+	ids []link.Identifier,
+) ([]link.StatusChange, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	keys := make([][]any, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, []any{id.Domain, id.ShortUri})
+	}
+
+	var res []link.StatusChange
+	for _, from := range forbiddableStatuses {
+		changes, err := transitLinkStatus(ctx, db, distributedCache, tables, from, link.StatusForbidden,
+			"(domain, short_uri) IN ?", keys)
+		res = append(res, changes...)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
@@ -42,12 +42,12 @@ func NewLinkShardingRepository(
 	}
 }
 
-func (r LinkShardingRepository) ShortUriExists(ctx context.Context, shortUri string) (bool, error) {
+func (r LinkShardingRepository) ShortUriExists(ctx context.Context, key string) (bool, error) {
 	return r.distributedCache.ExistsInBloomFilter(
 		ctx,
 		cache.ShortUriCreateBloomFilter,
-		constant.LockGotoLinkKey+shortUri,
-		constant.GotoIsNullLinkKey+shortUri,
+		key,
+		constant.GotoIsNullLinkKey+key,
 	)
 }
 
@@ -182,19 +182,28 @@ func (r LinkShardingRepository) ShortUriExists(ctx context.Context, shortUri str
 //	return nil
 //}
 
-func (r LinkShardingRepository) getLinkPo(ctx context.Context, shortUri string) (res po.Link, err error) {
+func (r LinkShardingRepository) getLinkPo(ctx context.Context, domain, shortUri string) (res po.Link, err error) {
 	var linkGotoPo po.LinkGoto
-	if err = r.db.WithContext(ctx).Where("short_uri = ?", shortUri).First(&linkGotoPo).Error; err != nil {
+	if err = r.db.WithContext(ctx).Where("short_uri = ? AND domain = ?", shortUri, domain).First(&linkGotoPo).Error; err != nil {
 		return
 	}
 	var linkPo po.Link
-	if err = r.db.WithContext(ctx).Where("gid = ? AND short_uri = ?", linkGotoPo.Gid, shortUri).
+	if err = r.db.WithContext(ctx).Where("gid = ? AND domain = ? AND short_uri = ?", linkGotoPo.Gid, domain, shortUri).
 		First(&linkPo).Error; err != nil {
 		return
 	}
 	return linkPo, nil
 }
 
+// GetGroupSetting 分组配置不参与分片，与单表版相同
+func (r LinkShardingRepository) GetGroupSetting(ctx context.Context, gid string) (*link.GroupSetting, error) {
+	return getGroupSetting(ctx, r.db, gid)
+}
+
+func (r LinkShardingRepository) SaveGroupSetting(ctx context.Context, setting *link.GroupSetting) error {
+	return saveGroupSetting(ctx, r.db, setting)
+}
+
 // CreateLink 保存短链接并进行预热
 func (r LinkShardingRepository) CreateLink(ctx context.Context, lk *link.Link) (err error) {
 	linkPo := r.assembler.LinkEntityToLinkPo(lk)
@@ -202,13 +211,6 @@ func (r LinkShardingRepository) CreateLink(ctx context.Context, lk *link.Link) (
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
 		if err = tx.Create(&linkPo).Error; err != nil {
-			// 在高并发场景下可能出现重复插入的情况
-			//if errors.Is(err, gorm.ErrDuplicatedKey) {
-			//	// 添加到布隆过滤器
-			//	if err = r.rdb.BFAdd(ctx, cache.ShortUriCreateBloomFilter, shortUri).Err(); err != nil {
-			//		return error_no.NewServiceErrorWithMsg(error_no.LinkDuplicateInsert, err.Error())
-			//	}
-			//}
 			return err
 		}
 		if err = tx.Create(&linkGotoPo).Error; err != nil {
@@ -217,17 +219,21 @@ func (r LinkShardingRepository) CreateLink(ctx context.Context, lk *link.Link) (
 		return nil
 	})
 	if err != nil {
+		// 布隆过滤器存在误判，在高并发场景下可能出现重复插入的情况
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errno.LinkAlreadyExists
+		}
 		return
 	}
 
 	cacheValue := link.NewCacheValue(lk)
 	err = r.distributedCache.SafePut(
 		ctx,
-		constant.GotoLinkKey+lk.ShortUri(),
+		constant.GotoLinkKey+lk.Key(),
 		cacheValue,
 		cacheValue.Expiration(),
 		cache.ShortUriCreateBloomFilter,
-		lk.ShortUri(),
+		lk.Key(),
 	)
 	return
 }
@@ -247,17 +253,20 @@ func (r LinkShardingRepository) CreateLinkBatch(ctx context.Context, links []*li
 		}
 		return nil
 	}); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errno.LinkAlreadyExists
+		}
 		return err
 	}
 
 	for _, lk := range links {
 		err := r.distributedCache.SafePut(
 			ctx,
-			constant.GotoLinkKey+lk.ShortUri(),
+			constant.GotoLinkKey+lk.Key(),
 			link.NewCacheValue(lk),
 			lk.ValidDate().Expiration(),
 			cache.ShortUriCreateBloomFilter,
-			lk.ShortUri(),
+			lk.Key(),
 		)
 		if err != nil {
 			return err
@@ -271,13 +280,14 @@ func (r LinkShardingRepository) CreateLinkBatch(ctx context.Context, links []*li
 // 2. 更新缓存
 func (r LinkShardingRepository) UpdateLink(
 	ctx context.Context,
+	domain string,
 	shortUri string,
 	updateFn func(ctx context.Context, link *link.Link) (*link.Link, error),
 ) (err error) {
 
 	// 查询
 	linkPo := po.Link{}
-	if linkPo, err = r.getLinkPo(ctx, shortUri); err != nil {
+	if linkPo, err = r.getLinkPo(ctx, domain, shortUri); err != nil {
 		return
 	}
 
@@ -289,7 +299,7 @@ func (r LinkShardingRepository) UpdateLink(
 
 	if updatedLinkPo.Gid != linkPo.Gid {
 		// 获取分布式锁
-		lockKey := constant.LockGidUpdateKey + linkPo.ShortUri
+		lockKey := constant.LockGidUpdateKey + lk.Key()
 		acquired := false
 		if acquired, err = r.locker.Acquire(ctx, lockKey, constant.DefaultTimeOut); err != nil {
 			return err
@@ -300,38 +310,29 @@ func (r LinkShardingRepository) UpdateLink(
 
 		// 事务更新
 		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-			// 从原来的分组中删除
-			if err = tx.WithContext(ctx).Delete(&linkPo).Error; err != nil {
-				return err
-			}
-			oldLinkGotoPo := po.LinkGoto{
-				Gid:      linkPo.Gid,
-				ShortUri: linkPo.ShortUri,
-			}
-			if err = tx.WithContext(ctx).Delete(oldLinkGotoPo).Error; err != nil {
-				return err
-			}
-
-			// 插入新的分组
-			if err = tx.WithContext(ctx).Create(&updatedLinkPo).Error; err != nil {
-				return err
-			}
-			shortLinkGotoPo := po.LinkGoto{
-				Gid:      updatedLinkPo.Gid,
-				ShortUri: updatedLinkPo.ShortUri,
-			}
-			if err = tx.WithContext(ctx).Create(&shortLinkGotoPo).Error; err != nil {
-				return err
-			}
-			return nil
+			return moveLinkToGroup(tx, linkPo, updatedLinkPo)
 		})
 
-		if err = r.locker.Release(ctx, lockKey); err != nil {
-			log.Errorf("释放锁失败: %v", err)
+		// 释放锁失败不能覆盖事务的错误
+		if releaseErr := r.locker.Release(ctx, lockKey); releaseErr != nil {
+			log.Errorf("释放锁失败: %v", releaseErr)
+			if err == nil {
+				err = releaseErr
+			}
+		}
+		if err != nil {
 			return err
 		}
 	} else {
-		if err = r.db.Save(&updatedLinkPo).Error; err != nil {
+		// 与单表版相同，需要更新零值，但实体中不包含的字段要排除
+		updatedLinkPo.UpdateTime = time.Now()
+		if err = r.db.WithContext(ctx).
+			Model(&po.Link{}).
+			Where("gid = ? AND id = ?", linkPo.Gid, linkPo.ID).
+			Select("*").
+			Omit("id", "create_time", "delete_time", "tenant_id", "recycle_time",
+				"og_image", "og_description", "health_status", "health_latency", "health_check_time").
+			Updates(&updatedLinkPo).Error; err != nil {
 			return err
 		}
 	}
@@ -339,13 +340,42 @@ func (r LinkShardingRepository) UpdateLink(
 	// 更新缓存
 	err = r.distributedCache.Put(
 		ctx,
-		constant.GotoLinkKey+lk.ShortUri(),
+		constant.GotoLinkKey+lk.Key(),
 		link.NewCacheValue(lk),
 		lk.ValidDate().Expiration(),
 	)
 	return err
 }
 
+// moveLinkToGroup 分片版移动分组，短链接按分组分片，需要从原分片删除后插入新分片
+//
+// 实体中不包含的字段沿用原记录，跳过钩子避免 tenant_id 等字段被覆盖；link_goto 按短链接分片，只需要修改分组
+func moveLinkToGroup(tx *gorm.DB, from po.Link, to po.Link) error {
+	if err := tx.Where("gid = ?", from.Gid).Delete(&from).Error; err != nil {
+		return err
+	}
+	carryOverLinkColumns(from, &to)
+	to.UpdateTime = time.Now()
+	if err := tx.Session(&gorm.Session{SkipHooks: true}).Create(&to).Error; err != nil {
+		return err
+	}
+	return tx.Model(&po.LinkGoto{}).
+		Where("domain = ? AND short_uri = ?", from.Domain, from.ShortUri).
+		Update("gid", to.Gid).Error
+}
+
+// carryOverLinkColumns 将实体中不包含的字段从原记录复制到新记录
+func carryOverLinkColumns(from po.Link, to *po.Link) {
+	to.CreateTime = from.CreateTime
+	to.TenantID = from.TenantID
+	to.RecycleTime = from.RecycleTime
+	to.OgImage = from.OgImage
+	to.OgDescription = from.OgDescription
+	to.HealthStatus = from.HealthStatus
+	to.HealthLatency = from.HealthLatency
+	to.HealthCheckTime = from.HealthCheckTime
+}
+
 // SaveToRecycleBin 保存到回收站
 func (r LinkShardingRepository) SaveToRecycleBin(
 	ctx context.Context,
@@ -357,7 +387,7 @@ func (r LinkShardingRepository) SaveToRecycleBin(
 	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 查询
 		if err = r.db.WithContext(ctx).
-			Model(&linkPo).Where("gid = ? and shortUri = ?", id.Gid, id.ShortUri).
+			Model(&linkPo).Where("gid = ? and domain = ? and short_uri = ?", id.Gid, id.Domain, id.ShortUri).
 			First(&linkPo).Error; err != nil {
 			return err
 		}
@@ -381,7 +411,7 @@ func (r LinkShardingRepository) SaveToRecycleBin(
 	}
 
 	// 修改缓存中的状态为已删除
-	cacheKey := constant.GotoLinkKey + id.ShortUri
+	cacheKey := constant.GotoLinkKey + link.Key(id.Domain, id.ShortUri)
 	if err = r.modifyCacheValueStatus(ctx, cacheKey, link.StatusDeleted); err != nil {
 		return
 	}
@@ -397,7 +427,7 @@ func (r LinkShardingRepository) RemoveFromRecycleBin(
 	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
 		var linkPo po.Link
 		err = r.db.WithContext(ctx).Model(&linkPo).
-			Where("gid = ? and fullShortUrl = ?", id.Gid, id.ShortUri).
+			Where("gid = ? and domain = ? and short_uri = ?", id.Gid, id.Domain, id.ShortUri).
 			Find(&linkPo).Error
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -417,8 +447,8 @@ func (r LinkShardingRepository) RemoveFromRecycleBin(
 
 	// 删除缓存
 	err = r.distributedCache.SafeDelete(
-		ctx, constant.GotoLinkKey+id.ShortUri,
-		constant.GotoIsNullLinkKey+id.ShortUri,
+		ctx, constant.GotoLinkKey+link.Key(id.Domain, id.ShortUri),
+		constant.GotoIsNullLinkKey+link.Key(id.Domain, id.ShortUri),
 	)
 	if err != nil {
 		return err
@@ -436,7 +466,7 @@ func (r LinkShardingRepository) RecoverFromRecycleBin(
 	var linkPo po.Link
 	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
 		err = r.db.WithContext(ctx).Model(&linkPo).
-			Where("gid = ? and fullShortUrl = ?", id.Gid, id.ShortUri).
+			Where("gid = ? and domain = ? and short_uri = ?", id.Gid, id.Domain, id.ShortUri).
 			Find(&linkPo).Error
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -460,7 +490,7 @@ func (r LinkShardingRepository) RecoverFromRecycleBin(
 		return
 	}
 
-	cacheKey := constant.GotoLinkKey + id.ShortUri
+	cacheKey := constant.GotoLinkKey + link.Key(id.Domain, id.ShortUri)
 	if err = r.modifyCacheValueStatus(ctx, cacheKey, link.Status(linkPo.Status)); err != nil {
 		return
 	}
@@ -480,3 +510,32 @@ func (r LinkShardingRepository) modifyCacheValueStatus(ctx context.Context, cach
 	}
 	return
 }
+
+// ConsumeVisit 消耗一次访问次数，逻辑与 LinkRepository.ConsumeVisit 一致
+func (r LinkShardingRepository) ConsumeVisit(ctx context.Context, domain, shortUri string, cacheValue *link.CacheValue) (bool, error) {
+	key := link.Key(domain, shortUri)
+	count, err := r.distributedCache.Incr(ctx, constant.LinkVisitCountKey+key, cacheValue.Expiration())
+	if err != nil {
+		return false, err
+	}
+	maxVisits := int64(cacheValue.MaxVisits)
+	if count > maxVisits {
+		return false, nil
+	}
+	if count == maxVisits {
+		var linkGotoPo po.LinkGoto
+		if err = r.db.WithContext(ctx).Where("short_uri = ? AND domain = ?", shortUri, domain).First(&linkGotoPo).Error; err != nil {
+			return false, err
+		}
+		if err = r.db.WithContext(ctx).
+			Model(&po.Link{}).
+			Where("gid = ? AND domain = ? AND short_uri = ? AND status = ?", linkGotoPo.Gid, domain, shortUri, link.StatusActive).
+			Update("status", link.StatusExpired).Error; err != nil {
+			return false, err
+		}
+		if _, err = r.distributedCache.Delete(ctx, constant.GotoLinkKey+key); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"shortlink/internal/base/database"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/adapter/assembler"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMoveLinkToGroup(t *testing.T) {
+	db := newDryRunDB(t)
+	var statements []string
+	var created *po.Link
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+		if lk, ok := tx.Statement.Dest.(*po.Link); ok && strings.HasPrefix(tx.Statement.SQL.String(), "INSERT") {
+			created = lk
+		}
+	}
+	_ = db.Callback().Delete().After("gorm:delete").Register("test:capture", capture)
+	_ = db.Callback().Create().After("gorm:create").Register("test:capture", capture)
+	_ = db.Callback().Update().After("gorm:update").Register("test:capture", capture)
+
+	createTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	from := po.Link{
+		BaseModel:     database.BaseModel{ID: 42, CreateTime: createTime, TenantID: "alice"},
+		Gid:           "old",
+		ShortUri:      "abc123",
+		OgImage:       "https://example.com/cover.png",
+		HealthStatus:  200,
+		OriginalUrl:   "https://example.com",
+		OgDescription: "desc",
+	}
+	// 由实体转换而来，不包含实体之外的字段
+	to := po.Link{BaseModel: database.BaseModel{ID: 42}, Gid: "new", ShortUri: "abc123", OriginalUrl: "https://example.com"}
+
+	if err := moveLinkToGroup(db, from, to); err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 3 {
+		t.Fatalf("statements = %q, want delete, insert and goto update", statements)
+	}
+	// 分片键必须出现在条件中
+	if !strings.Contains(statements[0], `"delete_time"=`) || !strings.Contains(statements[0], "gid = ") {
+		t.Errorf("unexpected delete: %s", statements[0])
+	}
+	if !strings.Contains(statements[2], "t_link_goto") || !strings.Contains(statements[2], "short_uri = ") {
+		t.Errorf("unexpected goto update: %s", statements[2])
+	}
+	if created == nil {
+		t.Fatal("moved link was not inserted")
+	}
+	if !created.CreateTime.Equal(createTime) || created.TenantID != "alice" || created.Gid != "new" ||
+		created.OgImage != from.OgImage || created.OgDescription != from.OgDescription || created.HealthStatus != 200 {
+		t.Errorf("moved link = %+v, want columns carried over from %+v", created, from)
+	}
+}
+
+func TestLinkShardingRepository_CreateLinkDuplicated(t *testing.T) {
+	linkFactory, err := link.NewFactory(link.FactoryConfig{
+		Domain:            "nurl.ink",
+		MaxAttempts:       1,
+		MaxLinksPerGroup:  100,
+		DefaultFavicon:    "https://nurl.ink/favicon.ico",
+		DefaultGid:        "default",
+		DefaultExpiration: 15,
+		DefaultCreateType: link.CreateByApi,
+		DefaultValidType:  link.ValidTypePermanent,
+		AliasMinLength:    4,
+		AliasMaxLength:    8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lk, err := linkFactory.NewAvailableLink("https://example.com", "gid", nil, nil, nil, nil, "",
+		link.CreateOptions{Alias: "taken"}, func(string) (bool, error) { return false, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟唯一索引冲突
+	db := newDryRunDB(t)
+	_ = db.Callback().Create().After("gorm:create").Register("test:duplicated", func(tx *gorm.DB) {
+		_ = tx.AddError(gorm.ErrDuplicatedKey)
+	})
+	repo := LinkShardingRepository{db: db, assembler: assembler.NewLinkAssembler(linkFactory)}
+
+	tests := []struct {
+		name   string
+		create func() error
+	}{
+		{"create link", func() error { return repo.CreateLink(context.Background(), lk) }},
+		{"create link batch", func() error { return repo.CreateLinkBatch(context.Background(), []*link.Link{lk}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.create(); !errors.Is(err, errno.LinkAlreadyExists) {
+				t.Errorf("error = %v, want %v", err, errno.LinkAlreadyExists)
+			}
+		})
+	}
+}
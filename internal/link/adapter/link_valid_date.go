@@ -0,0 +1,114 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"shortlink/internal/base/cache"
+	"shortlink/internal/base/database"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/common/constant"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+func (r LinkRepository) ActivatePendingLinks(ctx context.Context, now time.Time) ([]link.StatusChange, error) {
+	return activatePendingLinks(ctx, r.db, r.distributedCache, []string{po.TableNameLink}, now)
+}
+
+func (r LinkRepository) ExpireOverdueLinks(ctx context.Context, now time.Time) ([]link.StatusChange, error) {
+	return expireOverdueLinks(ctx, r.db, r.distributedCache, []string{po.TableNameLink}, now)
+}
+
+func (r LinkShardingRepository) ActivatePendingLinks(ctx context.Context, now time.Time) ([]link.StatusChange, error) {
+	return activatePendingLinks(ctx, r.db, r.distributedCache, linkShardTables(), now)
+}
+
+func (r LinkShardingRepository) ExpireOverdueLinks(ctx context.Context, now time.Time) ([]link.StatusChange, error) {
+	return expireOverdueLinks(ctx, r.db, r.distributedCache, linkShardTables(), now)
+}
+
+// linkShardTables 按有效期筛选时没有分片键，需要逐个分片处理
+func linkShardTables() []string {
+	tables := make([]string, 0, database.NumberOfShards)
+	for i := 0; i < database.NumberOfShards; i++ {
+		tables = append(tables, fmt.Sprintf("%s_%d", po.TableNameLink, i))
+	}
+	return tables
+}
+
+func activatePendingLinks(
+	ctx context.Context,
+	db *gorm.DB,
+	distributedCache cache.DistributedCache,
+	tables []string,
+	now time.Time,
+) ([]link.StatusChange, error) {
+	return transitLinkStatus(ctx, db, distributedCache, tables, link.StatusPending, link.StatusActive,
+		"start_date <= ?", now)
+}
+
+func expireOverdueLinks(
+	ctx context.Context,
+	db *gorm.DB,
+	distributedCache cache.DistributedCache,
+	tables []string,
+	now time.Time,
+) ([]link.StatusChange, error) {
+	// 未到开始时间就已经过期的短链接同样需要处理
+	var res []link.StatusChange
+	for _, from := range []link.Status{link.StatusActive, link.StatusPending} {
+		ids, err := transitLinkStatus(ctx, db, distributedCache, tables, from, link.StatusExpired,
+			"valid_type = ? AND end_date <= ?", link.ValidTypeTemporary, now)
+		res = append(res, ids...)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// transitLinkStatus 将满足条件的短链接从 from 状态改为 to 状态，并清除跳转缓存
+//
+// 每个分片单独更新，某个分片失败时已处理的分片不会回滚，返回已经发生变化的短链接
+func transitLinkStatus(
+	ctx context.Context,
+	db *gorm.DB,
+	distributedCache cache.DistributedCache,
+	tables []string,
+	from link.Status,
+	to link.Status,
+	query string,
+	args ...any,
+) (res []link.StatusChange, err error) {
+	for _, table := range tables {
+		var rows []po.Link
+		if err = db.WithContext(ctx).
+			Table(table).
+			Model(&rows).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "gid"}, {Name: "domain"}, {Name: "short_uri"}}}).
+			Where("status = ?", from).
+			Where(query, args...).
+			Updates(map[string]any{"status": to, "update_time": time.Now()}).Error; err != nil {
+			return res, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(rows))
+		for _, row := range rows {
+			res = append(res, link.StatusChange{
+				Identifier: link.Identifier{Gid: row.Gid, Domain: row.Domain, ShortUri: row.ShortUri},
+				From:       from,
+				To:         to,
+			})
+			keys = append(keys, constant.GotoLinkKey+link.Key(row.Domain, row.ShortUri))
+		}
+		if _, err = distributedCache.DeleteMultiple(ctx, keys); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,179 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"shortlink/internal/base/constant"
+	"shortlink/internal/link/domain/link"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxCachedHosts 缓存的域名数量上限，超过时清理过期的缓存
+	maxCachedHosts = 4096
+	// maxTitleLength 标题和描述的最大长度 单位: 字符
+	maxTitleLength = 256
+)
+
+// HtmlMetadataFetcher 获取跳转目标页面的标题、描述、分享图片和图标
+//
+// 请求有超时时间，只读取页面开头的 maxBytes 字节，同一域名的图标和访问失败的结果会缓存 cacheTTL；
+// 页面内容会保存到短链接的描述中，只连接公网地址，避免读取内网页面
+type HtmlMetadataFetcher struct {
+	client   *http.Client
+	maxBytes int64
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]hostMetadata
+}
+
+// hostMetadata 同一域名下的页面通常使用相同的图标，访问失败的域名在缓存期内不再请求
+type hostMetadata struct {
+	favicon   string
+	err       error
+	expiresAt time.Time
+}
+
+func NewHtmlMetadataFetcher(timeout time.Duration, maxBytes int64, cacheTTL time.Duration) *HtmlMetadataFetcher {
+	return &HtmlMetadataFetcher{
+		client:   newGuardedClient(isPublicIP, timeout),
+		maxBytes: maxBytes,
+		cacheTTL: cacheTTL,
+		hosts:    make(map[string]hostMetadata),
+	}
+}
+
+func (f *HtmlMetadataFetcher) Fetch(ctx context.Context, rawUrl string) (link.Metadata, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return link.Metadata{}, err
+	}
+	host := strings.ToLower(u.Host)
+
+	cached, ok := f.cached(host)
+	if ok && cached.err != nil {
+		return link.Metadata{}, cached.err
+	}
+
+	m, err := f.fetch(ctx, rawUrl)
+	if err != nil {
+		f.store(host, hostMetadata{err: err})
+		return m, err
+	}
+	if m.Favicon != "" {
+		f.store(host, hostMetadata{favicon: m.Favicon})
+	} else if ok {
+		m.Favicon = cached.favicon
+	}
+	return m, nil
+}
+
+func (f *HtmlMetadataFetcher) fetch(ctx context.Context, rawUrl string) (link.Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return link.Metadata{}, err
+	}
+	req.Header.Set("User-Agent", constant.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return link.Metadata{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return link.Metadata{}, fmt.Errorf("failed to fetch website: %s", resp.Status)
+	}
+	// 跳转目标可能是图片、压缩包等文件，没有元信息
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "" &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return link.Metadata{}, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, f.maxBytes))
+	if err != nil {
+		return link.Metadata{}, err
+	}
+
+	// 相对地址以跟随跳转后的地址为准
+	base := resp.Request.URL
+	title := metaContent(doc, "og:title")
+	if title == "" {
+		title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+	description := metaContent(doc, "og:description")
+	if description == "" {
+		description = metaContent(doc, "description")
+	}
+	favicon, _ := doc.Find("link[rel~='icon']").First().Attr("href")
+
+	return link.Metadata{
+		Title:       truncate(title, maxTitleLength),
+		Description: truncate(description, maxTitleLength),
+		Image:       resolveReference(base, metaContent(doc, "og:image")),
+		Favicon:     resolveReference(base, favicon),
+	}, nil
+}
+
+func (f *HtmlMetadataFetcher) cached(host string) (hostMetadata, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	m, ok := f.hosts[host]
+	if !ok || time.Now().After(m.expiresAt) {
+		return hostMetadata{}, false
+	}
+	return m, true
+}
+
+func (f *HtmlMetadataFetcher) store(host string, m hostMetadata) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	if len(f.hosts) >= maxCachedHosts {
+		for h, v := range f.hosts {
+			if now.After(v.expiresAt) {
+				delete(f.hosts, h)
+			}
+		}
+		if len(f.hosts) >= maxCachedHosts {
+			f.hosts = make(map[string]hostMetadata)
+		}
+	}
+	m.expiresAt = now.Add(f.cacheTTL)
+	f.hosts[host] = m
+}
+
+// metaContent Open Graph 使用 property 属性，其他 meta 标签使用 name 属性
+func metaContent(doc *goquery.Document, name string) string {
+	content, _ := doc.Find(fmt.Sprintf("meta[property='%s'], meta[name='%s']", name, name)).First().Attr("content")
+	return strings.TrimSpace(content)
+}
+
+// resolveReference 将相对地址转换为绝对地址，只保留 http 和 https 地址
+func resolveReference(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(strings.TrimSpace(ref))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
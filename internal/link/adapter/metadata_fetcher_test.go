@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHtmlMetadataFetcher_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			_, _ = w.Write([]byte(`<html><head><title>Plain title</title>
+<meta property="og:title" content="OG title">
+<meta name="description" content="Page description">
+<meta property="og:image" content="/cover.png">
+<link rel="shortcut icon" href="/static/icon.png">
+</head><body></body></html>`))
+		case "/plain":
+			_, _ = w.Write([]byte(`<html><head><title>No icon</title></head></html>`))
+		case "/large":
+			_, _ = w.Write([]byte("<html><head>" + strings.Repeat(" ", 4096) + "<title>Too late</title></head></html>"))
+		case "/file":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF"))
+		}
+	}))
+	defer srv.Close()
+
+	// 测试服务监听在本机地址，默认不允许连接
+	f := NewHtmlMetadataFetcher(time.Second, 1024, time.Minute)
+	f.client = newGuardedClient(func(net.IP) bool { return true }, time.Second)
+	ctx := context.Background()
+
+	m, err := f.Fetch(ctx, srv.URL+"/article")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "OG title" || m.Description != "Page description" ||
+		m.Image != srv.URL+"/cover.png" || m.Favicon != srv.URL+"/static/icon.png" {
+		t.Errorf("Fetch() = %+v", m)
+	}
+
+	// 页面没有声明图标时使用同一域名缓存的图标
+	if m, err = f.Fetch(ctx, srv.URL+"/plain"); err != nil || m.Title != "No icon" || m.Favicon != srv.URL+"/static/icon.png" {
+		t.Errorf("Fetch() = %+v, %v, want cached favicon", m, err)
+	}
+
+	// 超过大小限制的部分不会被读取
+	if m, err = f.Fetch(ctx, srv.URL+"/large"); err != nil || m.Title != "" {
+		t.Errorf("Fetch() = %+v, %v, want empty title", m, err)
+	}
+
+	if m, err = f.Fetch(ctx, srv.URL+"/file"); err != nil || m.Title != "" {
+		t.Errorf("Fetch() = %+v, %v, want empty metadata", m, err)
+	}
+}
+
+func TestHtmlMetadataFetcher_CacheFailure(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f := NewHtmlMetadataFetcher(time.Second, 1024, time.Minute)
+	f.client = newGuardedClient(func(net.IP) bool { return true }, time.Second)
+	for i := 0; i < 3; i++ {
+		if _, err := f.Fetch(context.Background(), srv.URL+"/a"); err == nil {
+			t.Errorf("Fetch() should fail")
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestHtmlMetadataFetcher_RefuseNonPublic(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`<html><head><title>Internal</title></head></html>`))
+	}))
+	defer srv.Close()
+
+	f := NewHtmlMetadataFetcher(time.Second, 1024, time.Minute)
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"loopback", srv.URL + "/admin"},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data/"},
+		{"private", "http://10.0.0.1/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if m, err := f.Fetch(context.Background(), tt.url); !errors.Is(err, errDialRefused) || m.Title != "" {
+				t.Errorf("Fetch() = %+v, %v, want %v", m, err, errDialRefused)
+			}
+		})
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d, want 0", requests)
+	}
+}
@@ -11,17 +11,39 @@ const TableNameLink = "t_link"
 // Link mapped from table <link>
 type Link struct {
 	database.BaseModel
-	Gid         string          `gorm:"column:gid;not null;comment:分组标识" json:"gid"`
-	ShortUri    string          `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`
-	OriginalUrl string          `gorm:"column:original_url;not null;comment:原始链接" json:"origin_url"`
-	Favicon     string          `gorm:"column:favicon;comment:网站图标" json:"favicon"`
-	Status      link.Status     `gorm:"column:status;not null;default:active;comment:可选值:,active,expired,disabled,pending,deleted,reserved" json:"status"`
-	CreateType  link.CreateType `gorm:"column:created_type;not null;comment:创建类型 0：接口创建 1：控制台创建" json:"created_type"`
-	ValidType   link.ValidType  `gorm:"column:valid_type;not null;comment:有效期类型 0：永久有效 1：自定义" json:"valid_date_type"`
-	StartDate   sql.NullTime    `gorm:"column:start_date;not null;default:CURRENT_TIMESTAMP;comment:有效期开始时间" json:"valid_start_date"`
-	EndDate     sql.NullTime    `gorm:"column:end_date;comment:有效期结束时间" json:"valid_end_date"`
-	Desc        string          `gorm:"column:desc;comment:描述" json:"desc"`
-	RecycleTime sql.NullTime    `gorm:"column:recycle_time;comment:回收时间" json:"recycle_time"`
+	Gid          string          `gorm:"column:gid;not null;comment:分组标识" json:"gid"`
+	Domain       string          `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`
+	ShortUri     string          `gorm:"column:short_uri;not null;comment:短链接 同一域名下唯一" json:"short_uri"`
+	FullShortUrl string          `gorm:"column:full_short_url;comment:完整短链接" json:"full_short_url"`
+	OriginalUrl  string          `gorm:"column:original_url;not null;comment:原始链接" json:"origin_url"`
+	Favicon      string          `gorm:"column:favicon;comment:网站图标" json:"favicon"`
+	Status       link.Status     `gorm:"column:status;not null;default:active;comment:可选值:,active,expired,disabled,pending,deleted,reserved" json:"status"`
+	CreateType   link.CreateType `gorm:"column:created_type;not null;comment:创建类型 0：接口创建 1：控制台创建" json:"created_type"`
+	ValidType    link.ValidType  `gorm:"column:valid_type;not null;comment:有效期类型 0：永久有效 1：自定义" json:"valid_date_type"`
+	StartDate    sql.NullTime    `gorm:"column:start_date;not null;default:CURRENT_TIMESTAMP;comment:有效期开始时间" json:"valid_start_date"`
+	EndDate      sql.NullTime    `gorm:"column:end_date;comment:有效期结束时间" json:"valid_end_date"`
+	Desc         string          `gorm:"column:desc;comment:描述" json:"desc"`
+	RecycleTime  sql.NullTime    `gorm:"column:recycle_time;comment:回收时间" json:"recycle_time"`
+	Password     string          `gorm:"column:password;comment:访问密码（哈希）" json:"-"`
+	MaxVisits    int             `gorm:"column:max_visits;not null;default:0;comment:最大访问次数 0：不限制" json:"max_visits"`
+	// 条件路由规则 JSON 数组
+	RoutingRules link.RoutingRules `gorm:"column:routing_rules;type:jsonb;comment:条件路由规则" json:"routing_rules"`
+	// 按权重分流的跳转目标 JSON 数组
+	Variants link.Variants `gorm:"column:variants;type:jsonb;comment:分流目标" json:"variants"`
+	// 跳转时对查询参数的处理 JSON 对象
+	UrlParams *link.UrlParams `gorm:"column:url_params;type:jsonb;comment:查询参数处理" json:"url_params"`
+	// 跳转状态码 0 表示使用 302
+	RedirectCode link.RedirectCode `gorm:"column:redirect_code;not null;default:0;comment:跳转状态码 301/302/307/308" json:"redirect_code"`
+	FallbackUrl  string            `gorm:"column:fallback_url;comment:备用链接" json:"fallback_url"`
+	// 标签 JSON 数组，使用 GIN 索引支持 @> 筛选
+	Tags link.Tags `gorm:"column:tags;type:jsonb;not null;default:'[]';index:idx_link_tags,type:gin;comment:标签" json:"tags"`
+	// 跳转目标页面的分享信息
+	OgImage       string `gorm:"column:og_image;comment:分享图片" json:"og_image"`
+	OgDescription string `gorm:"column:og_description;comment:页面描述" json:"og_description"`
+	// 跳转目标最近一次健康检查的结果
+	HealthStatus    int          `gorm:"column:health_status;not null;default:0;comment:响应状态码 0：无法访问" json:"health_status"`
+	HealthLatency   int          `gorm:"column:health_latency;not null;default:0;comment:响应耗时 单位：毫秒" json:"health_latency"`
+	HealthCheckTime sql.NullTime `gorm:"column:health_check_time;comment:检查时间" json:"health_check_time"`
 }
 
 func (*Link) TableName() string {
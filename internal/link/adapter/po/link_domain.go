@@ -0,0 +1,30 @@
+package po
+
+import (
+	"database/sql"
+	"shortlink/internal/base/database"
+	"shortlink/internal/link/domain/link"
+)
+
+const TableNameLinkDomain = "t_link_domain"
+
+// LinkDomain mapped from table <link_domain>
+//
+// 域名数量有限且按域名查询，不参与分片
+type LinkDomain struct {
+	database.BaseModel
+	Host   string            `gorm:"column:host;not null;uniqueIndex;comment:域名" json:"host"`
+	Owner  string            `gorm:"column:owner;not null;index;comment:注册域名的用户名" json:"owner"`
+	Status link.DomainStatus `gorm:"column:status;not null;default:unverified;comment:可选值:unverified,verified,failed" json:"status"`
+	UseTLS bool              `gorm:"column:use_tls;not null;default:false;comment:是否通过 https 访问" json:"use_tls"`
+	// 所有权验证令牌
+	VerifyToken string `gorm:"column:verify_token;not null;comment:所有权验证令牌" json:"-"`
+	// 最近一次验证的时间
+	CheckedTime sql.NullTime `gorm:"column:checked_time;comment:最近一次验证时间" json:"checked_time"`
+	// 通过验证后连续验证失败的次数
+	VerifyFailures int `gorm:"column:verify_failures;not null;default:0;comment:连续验证失败次数" json:"verify_failures"`
+}
+
+func (*LinkDomain) TableName() string {
+	return TableNameLinkDomain
+}
@@ -15,6 +15,7 @@ const TableNameLinkGoto = "t_link_goto"
 type LinkGoto struct {
 	ID         int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`
 	Gid        string         `gorm:"column:gid;not null;comment:分组标识" json:"gid"`
+	Domain     string         `gorm:"column:domain;not null;default:'';comment:自定义域名" json:"domain"`
 	ShortUri   string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`
 	DeleteTime gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间" json:"delete_time"`
 }
@@ -0,0 +1,24 @@
+package po
+
+import (
+	"shortlink/internal/base/database"
+	"shortlink/internal/link/domain/link"
+)
+
+const TableNameLinkGroupSetting = "t_link_group_setting"
+
+// LinkGroupSetting mapped from table <link_group_setting>
+//
+// 分组归属于用户服务，这里只保存和短链接相关的分组默认配置
+type LinkGroupSetting struct {
+	database.BaseModel
+	Gid string `gorm:"column:gid;not null;uniqueIndex;comment:分组标识" json:"gid"`
+	// 默认的查询参数处理 JSON 对象
+	UrlParams *link.UrlParams `gorm:"column:url_params;type:jsonb;comment:查询参数处理" json:"url_params"`
+	// 默认的备用链接
+	FallbackUrl string `gorm:"column:fallback_url;comment:备用链接" json:"fallback_url"`
+}
+
+func (*LinkGroupSetting) TableName() string {
+	return TableNameLinkGroupSetting
+}
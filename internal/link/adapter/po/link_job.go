@@ -0,0 +1,37 @@
+package po
+
+import (
+	"database/sql"
+	"shortlink/internal/base/database"
+	"shortlink/internal/link/domain/link"
+)
+
+const TableNameLinkJob = "t_link_job"
+
+// LinkJob mapped from table <link_job>
+//
+// 批量导入等后台任务，按任务标识查询，不参与分片
+type LinkJob struct {
+	database.BaseModel
+	JobId  string         `gorm:"column:job_id;not null;uniqueIndex;comment:任务标识" json:"job_id"`
+	Type   link.JobType   `gorm:"column:type;not null;comment:可选值:import,export" json:"type"`
+	Owner  string         `gorm:"column:owner;not null;index;comment:创建任务的用户名" json:"owner"`
+	Status link.JobStatus `gorm:"column:status;not null;default:pending;comment:可选值:pending,running,succeeded,failed" json:"status"`
+	DryRun bool           `gorm:"column:dry_run;not null;default:false;comment:只校验不写入" json:"dry_run"`
+	// 进度
+	Total     int `gorm:"column:total;not null;default:0;comment:总行数" json:"total"`
+	Processed int `gorm:"column:processed;not null;default:0;comment:已处理行数" json:"processed"`
+	Succeeded int `gorm:"column:succeeded;not null;default:0;comment:成功行数" json:"succeeded"`
+	Failed    int `gorm:"column:failed;not null;default:0;comment:失败行数" json:"failed"`
+	// 错误行 JSON 数组，最多保存 100 条
+	RowErrors link.RowErrors `gorm:"column:row_errors;type:jsonb;not null;default:'[]';comment:错误行" json:"row_errors"`
+	// 结果文件名
+	ResultFile string `gorm:"column:result_file;comment:结果文件名" json:"result_file"`
+	// 任务中断的原因
+	Message    string       `gorm:"column:message;comment:任务中断的原因" json:"message"`
+	FinishTime sql.NullTime `gorm:"column:finish_time;comment:结束时间" json:"finish_time"`
+}
+
+func (*LinkJob) TableName() string {
+	return TableNameLinkJob
+}
@@ -0,0 +1,177 @@
+package read
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"shortlink/internal/base/database"
+	"shortlink/internal/base/types"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/app/query"
+	"sort"
+	"sync"
+	"time"
+)
+
+func (q LinkQuery) CursorPageLink(
+	ctx context.Context,
+	filter query.LinkFilter,
+	after *types.Cursor,
+	limit int,
+) ([]query.Link, error) {
+	return cursorPageLink(ctx, q.db, po.TableNameLink, filter, after, limit)
+}
+
+func (q LinkShardingQuery) CursorPageLink(
+	ctx context.Context,
+	filter query.LinkFilter,
+	after *types.Cursor,
+	limit int,
+) ([]query.Link, error) {
+	// 指定分组时只需要查询所在的分片
+	if filter.Gid != nil {
+		return cursorPageLink(ctx, q.db, po.TableNameLink, filter, after, limit)
+	}
+	return mergeShards(limit, linkCreateKey, func(table string) ([]query.Link, error) {
+		return cursorPageLink(ctx, q.db, table, filter, after, limit)
+	})
+}
+
+func (q LinkQuery) CursorPageRecycleBin(
+	ctx context.Context,
+	gids []string,
+	after *types.Cursor,
+	limit int,
+) ([]query.Link, error) {
+	return cursorPageRecycleBin(ctx, q.db, po.TableNameLink, gids, after, limit)
+}
+
+func (q LinkShardingQuery) CursorPageRecycleBin(
+	ctx context.Context,
+	gids []string,
+	after *types.Cursor,
+	limit int,
+) ([]query.Link, error) {
+	if len(gids) == 1 && gids[0] != "" {
+		return cursorPageRecycleBin(ctx, q.db, po.TableNameLink, gids, after, limit)
+	}
+	return mergeShards(limit, linkUpdateKey, func(table string) ([]query.Link, error) {
+		return cursorPageRecycleBin(ctx, q.db, table, gids, after, limit)
+	})
+}
+
+// cursorPageLink 按 (create_time, id) 倒序查询游标之后的短链接
+func cursorPageLink(
+	ctx context.Context,
+	db *gorm.DB,
+	table string,
+	filter query.LinkFilter,
+	after *types.Cursor,
+	limit int,
+) ([]query.Link, error) {
+	q := linkWithTodayStats(ctx, db, table).
+		Where("l.recycle_time IS NULL AND l.delete_time IS NULL AND l.tenant_id = ?", ctx.Value("username"))
+	if filter.Gid != nil {
+		q = q.Where("l.gid = ?", *filter.Gid)
+	}
+	if cond, args := linkSearchCondition("l", filter); cond != "" {
+		q = q.Where(cond, args...)
+	}
+	if after != nil {
+		q = q.Where("(l.create_time, l.id) < (?, ?)", after.Time, after.Id)
+	}
+
+	var records []query.Link
+	if err := q.Order("l.create_time DESC, l.id DESC").Limit(limit).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to scan records: %w", err)
+	}
+	return records, nil
+}
+
+// cursorPageRecycleBin 按 (update_time, id) 倒序查询游标之后的回收站短链接
+func cursorPageRecycleBin(
+	ctx context.Context,
+	db *gorm.DB,
+	table string,
+	gids []string,
+	after *types.Cursor,
+	limit int,
+) ([]query.Link, error) {
+	q := linkWithTodayStats(ctx, db, table).
+		Where("l.recycle_time IS NOT NULL AND l.delete_time IS NULL AND l.tenant_id = ?", ctx.Value("username"))
+	if len(gids) > 0 && gids[0] != "" {
+		q = q.Where("l.gid IN ?", gids)
+	}
+	if after != nil {
+		q = q.Where("(l.update_time, l.id) < (?, ?)", after.Time, after.Id)
+	}
+
+	var records []query.Link
+	if err := q.Order("l.update_time DESC, l.id DESC").Limit(limit).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to scan records: %w", err)
+	}
+	return records, nil
+}
+
+// linkWithTodayStats 查询短链接及当天的访问统计
+func linkWithTodayStats(ctx context.Context, db *gorm.DB, table string) *gorm.DB {
+	return db.WithContext(ctx).
+		Table(table + " l").
+		Select("l.*, COALESCE(st.today_pv, 0) AS today_pv, COALESCE(st.today_uv, 0) AS today_uv, COALESCE(st.today_uip, 0) AS today_uip").
+		Joins("LEFT JOIN t_link_stats_today st ON l.domain = st.domain AND l.short_uri = st.short_uri AND st.date = current_date")
+}
+
+func linkCreateKey(l query.Link) (time.Time, int) {
+	return time.Time(l.CreateTime), l.ID
+}
+
+func linkUpdateKey(l query.Link) (time.Time, int) {
+	return time.Time(l.UpdateTime), l.ID
+}
+
+// mergeShards 并发查询全部分片，每个分片最多返回 limit 条，按排序键倒序归并后取前 limit 条
+//
+// 每个分片内的结果已经有序，全局的前 limit 条一定在各分片的前 limit 条之中
+func mergeShards(
+	limit int,
+	key func(query.Link) (time.Time, int),
+	queryFn func(table string) ([]query.Link, error),
+) ([]query.Link, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		merged   []query.Link
+		queryErr error
+	)
+	for i := 0; i < database.NumberOfShards; i++ {
+		wg.Add(1)
+		go func(table string) {
+			defer wg.Done()
+			records, err := queryFn(table)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				queryErr = err
+				return
+			}
+			merged = append(merged, records...)
+		}(fmt.Sprintf("%s_%d", po.TableNameLink, i))
+	}
+	wg.Wait()
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		ti, idi := key(merged[i])
+		tj, idj := key(merged[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return idi > idj
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
@@ -0,0 +1,48 @@
+package read
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"shortlink/internal/base/types"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/app/query"
+	"shortlink/internal/link/domain/link"
+)
+
+// 域名表不参与分片，单表版和分库分表版的查询逻辑相同
+
+func (q LinkQuery) ListShortDomains(ctx context.Context, owner string) ([]query.ShortDomain, error) {
+	return listShortDomains(ctx, q.db, owner)
+}
+
+func (q LinkShardingQuery) ListShortDomains(ctx context.Context, owner string) ([]query.ShortDomain, error) {
+	return listShortDomains(ctx, q.db, owner)
+}
+
+func listShortDomains(ctx context.Context, db *gorm.DB, owner string) ([]query.ShortDomain, error) {
+	var domainPos []po.LinkDomain
+	if err := db.WithContext(ctx).
+		Where("owner = ?", owner).
+		Order("create_time").
+		Find(&domainPos).Error; err != nil {
+		return nil, err
+	}
+	res := make([]query.ShortDomain, 0, len(domainPos))
+	for _, d := range domainPos {
+		var checkedTime *types.JsonTime
+		if d.CheckedTime.Valid {
+			t := types.JsonTime(d.CheckedTime.Time)
+			checkedTime = &t
+		}
+		res = append(res, query.ShortDomain{
+			Host:         d.Host,
+			Status:       d.Status,
+			UseTLS:       d.UseTLS,
+			VerifyToken:  d.VerifyToken,
+			VerifyRecord: link.NewShortDomainFromDB(d.Host, d.Owner, d.Status, d.UseTLS, d.VerifyToken, nil, 0).VerifyRecord(),
+			CheckedTime:  checkedTime,
+			CreateTime:   types.JsonTime(d.CreateTime),
+		})
+	}
+	return res, nil
+}
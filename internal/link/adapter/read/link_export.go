@@ -0,0 +1,106 @@
+package read
+
+import (
+	"context"
+	"database/sql"
+	"gorm.io/gorm"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+// exportBatchSize 导出时每批查询的短链接数量
+const exportBatchSize = 500
+
+// exportLinkRecord 导出查询的结果 短链接及截至当前的访问统计
+type exportLinkRecord struct {
+	Id           int
+	ShortUri     string
+	FullShortUrl string
+	OriginalUrl  string
+	Gid          string
+	Status       link.Status
+	Desc         string
+	Tags         link.Tags
+	ValidType    int
+	StartDate    sql.NullTime
+	EndDate      sql.NullTime
+	CreateTime   time.Time
+	TotalPv      int
+	TotalUv      int
+	TotalUip     int
+	TodayPv      int
+	TodayUv      int
+	TodayUip     int
+}
+
+func (q LinkQuery) ScanGroupLinks(ctx context.Context, gid string, fn func([]link.ExportRow) error) error {
+	return scanGroupLinks(ctx, q.db, gid, fn)
+}
+
+// ScanGroupLinks 分组只在一个分片中，按分片键查询即可
+func (q LinkShardingQuery) ScanGroupLinks(ctx context.Context, gid string, fn func([]link.ExportRow) error) error {
+	return scanGroupLinks(ctx, q.db, gid, fn)
+}
+
+// scanGroupLinks 按 ID 分批遍历分组下未删除的短链接，避免一次加载整个分组
+func scanGroupLinks(ctx context.Context, db *gorm.DB, gid string, fn func([]link.ExportRow) error) error {
+	lastId := 0
+	for {
+		var records []exportLinkRecord
+		if err := db.WithContext(ctx).
+			Table(po.TableNameLink+" l").
+			Select(`l.id, l.short_uri, l.full_short_url, l.original_url, l.gid, l.status, l."desc", l.tags,
+l.valid_type, l.start_date, l.end_date, l.create_time,
+COALESCE(ls.total_pv, 0) AS total_pv, COALESCE(ls.total_uv, 0) AS total_uv, COALESCE(ls.total_uip, 0) AS total_uip,
+COALESCE(st.today_pv, 0) AS today_pv, COALESCE(st.today_uv, 0) AS today_uv, COALESCE(st.today_uip, 0) AS today_uip`).
+			Joins("LEFT JOIN t_link_stats ls ON l.domain = ls.domain AND l.short_uri = ls.short_uri").
+			Joins("LEFT JOIN t_link_stats_today st ON l.domain = st.domain AND l.short_uri = st.short_uri AND st.date = current_date").
+			Where("l.gid = ? AND l.recycle_time IS NULL AND l.delete_time IS NULL AND l.tenant_id = ?", gid, ctx.Value("username")).
+			Where("l.id > ?", lastId).
+			Order("l.id").
+			Limit(exportBatchSize).
+			Find(&records).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		rows := make([]link.ExportRow, 0, len(records))
+		for _, r := range records {
+			row := link.ExportRow{
+				ShortUri:     r.ShortUri,
+				FullShortUrl: r.FullShortUrl,
+				OriginalUrl:  r.OriginalUrl,
+				Gid:          r.Gid,
+				Status:       r.Status,
+				Desc:         r.Desc,
+				Tags:         r.Tags,
+				ValidType:    r.ValidType,
+				CreateTime:   r.CreateTime,
+				TotalPv:      r.TotalPv,
+				TotalUv:      r.TotalUv,
+				TotalUip:     r.TotalUip,
+				TodayPv:      r.TodayPv,
+				TodayUv:      r.TodayUv,
+				TodayUip:     r.TodayUip,
+			}
+			if r.StartDate.Valid {
+				row.StartDate = &r.StartDate.Time
+			}
+			if r.EndDate.Valid {
+				row.EndDate = &r.EndDate.Time
+			}
+			rows = append(rows, row)
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+
+		if len(records) < exportBatchSize {
+			return nil
+		}
+		lastId = records[len(records)-1].Id
+	}
+}
@@ -0,0 +1,57 @@
+package read
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"shortlink/internal/base/types"
+	"shortlink/internal/link/app/query"
+	"shortlink/internal/link/domain/link"
+)
+
+// brokenCondition 与 link.HealthCheck.Broken 保持一致
+const brokenCondition = "(l.health_status = 0 OR l.health_status IN (404, 410) OR l.health_status >= 500)"
+
+func (q LinkQuery) PageBrokenLink(ctx context.Context, param query.PageBrokenLink) (*types.PageResp[query.Link], error) {
+	return pageBrokenLink(ctx, q.db, param)
+}
+
+func (q LinkShardingQuery) PageBrokenLink(ctx context.Context, param query.PageBrokenLink) (*types.PageResp[query.Link], error) {
+	return pageBrokenLink(ctx, q.db, param)
+}
+
+// pageBrokenLink 只查询可以访问的短链接，最近检查的排在前面
+func pageBrokenLink(ctx context.Context, db *gorm.DB, param query.PageBrokenLink) (*types.PageResp[query.Link], error) {
+	var records []query.Link
+	var total int64
+
+	baseQuery := db.WithContext(ctx).
+		Table("t_link l").
+		Where("l.recycle_time IS NULL and l.delete_time IS NULL and l.tenant_id = ?", ctx.Value("username")).
+		Where("l.status = ? AND l.health_check_time IS NOT NULL", link.StatusActive).
+		Where(brokenCondition)
+
+	if param.Gid != nil {
+		baseQuery = baseQuery.Where("l.gid = ?", *param.Gid)
+	}
+
+	if err := baseQuery.
+		Select("l.*").
+		Order("l.health_check_time DESC").
+		Limit(param.Limit()).
+		Offset(param.Offset()).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to scan records: %w", err)
+	}
+
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count total: %w", err)
+	}
+
+	return &types.PageResp[query.Link]{
+		Current: param.Current,
+		Size:    param.Size,
+		Total:   total,
+		Records: records,
+	}, nil
+}
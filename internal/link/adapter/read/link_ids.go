@@ -0,0 +1,42 @@
+package read
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/app/query"
+	"shortlink/internal/link/domain/link"
+)
+
+func (q LinkQuery) ListLinkIds(ctx context.Context, filter query.LinkFilter, limit int) ([]link.Identifier, error) {
+	return listLinkIds(ctx, q.db, filter, limit)
+}
+
+// ListLinkIds 过滤条件中必须包含分组，按分片键查询即可
+func (q LinkShardingQuery) ListLinkIds(ctx context.Context, filter query.LinkFilter, limit int) ([]link.Identifier, error) {
+	return listLinkIds(ctx, q.db, filter, limit)
+}
+
+func listLinkIds(ctx context.Context, db *gorm.DB, filter query.LinkFilter, limit int) ([]link.Identifier, error) {
+	tx := db.WithContext(ctx).
+		Table(po.TableNameLink+" l").
+		Select("l.gid, l.domain, l.short_uri").
+		Where("l.gid = ? AND l.recycle_time IS NULL AND l.delete_time IS NULL AND l.tenant_id = ?", *filter.Gid, ctx.Value("username"))
+	if cond, args := linkSearchCondition("l", filter); cond != "" {
+		tx = tx.Where(cond, args...)
+	}
+
+	var rows []struct {
+		Gid      string
+		Domain   string
+		ShortUri string
+	}
+	if err := tx.Order("l.id").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]link.Identifier, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, link.Identifier{Gid: row.Gid, Domain: row.Domain, ShortUri: row.ShortUri})
+	}
+	return ids, nil
+}
@@ -0,0 +1,53 @@
+package read
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/types"
+	"shortlink/internal/link/adapter/po"
+	"shortlink/internal/link/app/query"
+)
+
+// 任务表不参与分片，单表版和分库分表版的查询逻辑相同
+
+func (q LinkQuery) GetJob(ctx context.Context, id string) (query.Job, error) {
+	return getJob(ctx, q.db, id)
+}
+
+func (q LinkShardingQuery) GetJob(ctx context.Context, id string) (query.Job, error) {
+	return getJob(ctx, q.db, id)
+}
+
+func getJob(ctx context.Context, db *gorm.DB, id string) (query.Job, error) {
+	var jobPo po.LinkJob
+	if err := db.WithContext(ctx).Where("job_id = ?", id).First(&jobPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return query.Job{}, errno.LinkJobNotExists
+		}
+		return query.Job{}, err
+	}
+	var finishTime *types.JsonTime
+	if jobPo.FinishTime.Valid {
+		t := types.JsonTime(jobPo.FinishTime.Time)
+		finishTime = &t
+	}
+	return query.Job{
+		Id:         jobPo.JobId,
+		Type:       jobPo.Type,
+		Status:     jobPo.Status,
+		DryRun:     jobPo.DryRun,
+		Total:      jobPo.Total,
+		Processed:  jobPo.Processed,
+		Succeeded:  jobPo.Succeeded,
+		Failed:     jobPo.Failed,
+		RowErrors:  jobPo.RowErrors,
+		HasResult:  jobPo.ResultFile != "",
+		Message:    jobPo.Message,
+		CreateTime: types.JsonTime(jobPo.CreateTime),
+		FinishTime: finishTime,
+		Owner:      jobPo.Owner,
+		ResultFile: jobPo.ResultFile,
+	}, nil
+}
@@ -8,18 +8,19 @@ import (
 	"shortlink/internal/base/cache"
 	"shortlink/internal/base/errno"
 	"shortlink/internal/base/types"
+	"shortlink/internal/link/adapter/assembler"
 	"shortlink/internal/link/adapter/po"
 	"shortlink/internal/link/app/query"
 	"shortlink/internal/link/common/constant"
 	"shortlink/internal/link/domain/link"
 	"strconv"
-	"time"
 )
 
 type LinkQuery struct {
 	linkFactory      *link.Factory
 	db               *gorm.DB
 	distributedCache cache.DistributedCache
+	assembler        assembler.LinkAssembler
 }
 
 func NewLinkQuery(db *gorm.DB, factory *link.Factory, distributedCache cache.DistributedCache) *LinkQuery {
@@ -27,13 +28,14 @@ func NewLinkQuery(db *gorm.DB, factory *link.Factory, distributedCache cache.Dis
 		linkFactory:      factory,
 		db:               db,
 		distributedCache: distributedCache,
+		assembler:        assembler.NewLinkAssembler(factory),
 	}
 }
 
-func (q LinkQuery) GetLink(ctx context.Context, shortUri string) (*link.Link, error) {
+func (q LinkQuery) GetLink(ctx context.Context, domain, shortUri string) (*link.Link, error) {
 
 	var linkPo po.Link
-	if err := q.db.WithContext(ctx).Where("short_uri = ?", shortUri).First(&linkPo).Error; err != nil {
+	if err := q.db.WithContext(ctx).Where("domain = ? AND short_uri = ?", domain, shortUri).First(&linkPo).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			err = errno.LinkNotExists
 		}
@@ -41,23 +43,9 @@ func (q LinkQuery) GetLink(ctx context.Context, shortUri string) (*link.Link, er
 	}
 
 	// 将持久化对象转换为领域模型
-	lk := &link.Link{}
-
-	start, end := new(time.Time), new(time.Time)
-	if linkPo.StartDate.Valid {
-		*start = linkPo.StartDate.Time
-	}
-	if linkPo.EndDate.Valid {
-		*end = linkPo.EndDate.Time
-	}
-
-	if validDate, err := link.NewValidDate(link.ValidType(linkPo.ValidType), start, end); err != nil {
-		return nil, err
-	} else {
-		if lk, err = q.linkFactory.NewLinkFromDB(linkPo.ID, linkPo.Gid, linkPo.ShortUri, linkPo.OriginalUrl, link.Status(linkPo.Status),
-			link.CreateType(linkPo.CreateType), linkPo.Favicon, linkPo.Desc, validDate); err != nil {
-			return nil, err
-		}
+	lk := q.assembler.LinkPoToLinkEntity(linkPo)
+	if lk == nil {
+		return nil, errno.LinkNotExists
 	}
 
 	return lk, nil
@@ -77,11 +65,16 @@ func (q LinkQuery) PageLink(ctx context.Context, param query.PageLink) (*types.P
 		baseQuery = baseQuery.Where("l.gid = ?", *param.Gid)
 	}
 
-	// 3. 查询记录
+	// 3. 根据标签、状态、创建时间和关键词过滤
+	if cond, args := linkSearchCondition("l", param.LinkFilter); cond != "" {
+		baseQuery = baseQuery.Where(cond, args...)
+	}
+
+	// 4. 查询记录
 	queryB := baseQuery.
 		Select("l.*, COALESCE(st.today_pv, 0) AS todayPv, COALESCE(st.today_uv, 0) AS todayUv, COALESCE(st.today_uip, 0) AS todayUip").
-		Joins("LEFT JOIN t_link_stats_today st ON l.short_uri = st.short_uri AND st.date = current_date").
-		Joins("LEFT JOIN t_link_stats ls ON l.short_uri = ls.short_uri").
+		Joins("LEFT JOIN t_link_stats_today st ON l.domain = st.domain AND l.short_uri = st.short_uri AND st.date = current_date").
+		Joins("LEFT JOIN t_link_stats ls ON l.domain = ls.domain AND l.short_uri = ls.short_uri").
 		Order(getOrderClause(param.OrderTag)).
 		Limit(param.Limit()).
 		Offset(param.Offset())
@@ -90,12 +83,12 @@ func (q LinkQuery) PageLink(ctx context.Context, param query.PageLink) (*types.P
 		return nil, fmt.Errorf("failed to scan records: %w", err)
 	}
 
-	// 4. 查询总数
+	// 5. 查询总数
 	if err := baseQuery.Count(&total).Error; err != nil {
 		return nil, fmt.Errorf("failed to count total: %w", err)
 	}
 
-	// 5. 返回结果
+	// 6. 返回结果
 	return &types.PageResp[query.Link]{
 		Current: param.Current,
 		Size:    param.Size,
@@ -144,7 +137,7 @@ func (q LinkQuery) PageRecycleBin(ctx context.Context, param query.PageRecycleBi
 	// 2. 查询记录
 	queryB := baseQuery.
 		Select("l.*, COALESCE(t.today_pv, 0) AS todayPv, COALESCE(t.today_uv, 0) AS todayUv, COALESCE(t.today_uip, 0) AS todayUip").
-		Joins("LEFT JOIN t_link_stats_today t ON l.short_uri = t.short_uri AND t.date = current_date").
+		Joins("LEFT JOIN t_link_stats_today t ON l.domain = t.domain AND l.short_uri = t.short_uri AND t.date = current_date").
 		Order("l.update_time DESC").
 		Limit(param.Limit()).
 		Offset(param.Offset())
@@ -210,3 +203,15 @@ func (q LinkQuery) ListGroupLinkCount(ctx context.Context, gids []string) ([]que
 
 	return res, nil
 }
+
+func (q LinkQuery) GetGroupSetting(ctx context.Context, gid string) (query.GroupSetting, error) {
+	res := query.GroupSetting{Gid: gid}
+	var settingPo po.LinkGroupSetting
+	err := q.db.WithContext(ctx).Where("gid = ?", gid).First(&settingPo).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+	res.UrlParams = settingPo.UrlParams
+	res.FallbackUrl = settingPo.FallbackUrl
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package read
+
+import (
+	"fmt"
+	"shortlink/internal/link/app/query"
+	"strings"
+)
+
+// likeEscaper 转义 LIKE 中的通配符，Postgres 默认使用反斜杠作为转义字符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// linkSearchCondition 根据搜索条件生成过滤语句，alias 为短链接表的别名，没有条件时返回空字符串
+//
+// 关键词同时使用全文检索和模糊匹配：全文检索按单词匹配，中文等不以空格分词的内容依赖 pg_trgm 索引加速 ILIKE
+func linkSearchCondition(alias string, param query.LinkFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if len(param.Tags) > 0 {
+		conds = append(conds, alias+".tags @> ?")
+		args = append(args, param.Tags)
+	}
+	if param.Status != nil {
+		conds = append(conds, alias+".status = ?")
+		args = append(args, *param.Status)
+	}
+	if param.CreateType != nil {
+		conds = append(conds, alias+".created_type = ?")
+		args = append(args, *param.CreateType)
+	}
+	if param.StartTime != nil {
+		conds = append(conds, alias+".create_time >= ?")
+		args = append(args, *param.StartTime)
+	}
+	if param.EndTime != nil {
+		conds = append(conds, alias+".create_time < ?")
+		args = append(args, *param.EndTime)
+	}
+	if keyword := strings.TrimSpace(param.Keyword); keyword != "" {
+		pattern := "%" + likeEscaper.Replace(keyword) + "%"
+		conds = append(conds, fmt.Sprintf(
+			`(to_tsvector('simple', COALESCE(%[1]s."desc", '') || ' ' || %[1]s.original_url || ' ' || %[1]s.short_uri) @@ plainto_tsquery('simple', ?)`+
+				` OR %[1]s."desc" ILIKE ? OR %[1]s.original_url ILIKE ? OR %[1]s.short_uri ILIKE ?)`,
+			alias))
+		args = append(args, keyword, pattern, pattern, pattern)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
@@ -6,21 +6,23 @@ import (
 	"gorm.io/gorm"
 	"shortlink/internal/base/errno"
 	"shortlink/internal/base/types"
+	"shortlink/internal/link/adapter/assembler"
 	"shortlink/internal/link/adapter/po"
 	"shortlink/internal/link/app/query"
 	"shortlink/internal/link/domain/link"
-	"time"
 )
 
 type LinkShardingQuery struct {
 	linkFactory *link.Factory
 	db          *gorm.DB
+	assembler   assembler.LinkAssembler
 }
 
 func NewLinkShardingQuery(db *gorm.DB, factory *link.Factory) LinkShardingQuery {
 	return LinkShardingQuery{
 		linkFactory: factory,
 		db:          db,
+		assembler:   assembler.NewLinkAssembler(factory),
 	}
 }
 
@@ -28,7 +30,7 @@ func (q LinkShardingQuery) PageLink(ctx context.Context, param query.PageLink) (
 	rawSql := `
 SELECT t.*, COALESCE(s.today_pv, 0) AS todayPv, COALESCE(s.today_uv, 0) AS todayUv, COALESCE(s.today_uip, 0) AS todayUip
 FROM t_link t
-LEFT JOIN t_link_stats_today s ON t.short_uri = s.short_uri AND s.date = current_date
+LEFT JOIN t_link_stats_today s ON t.domain = s.domain AND t.short_uri = s.short_uri AND s.date = current_date
 WHERE t.delete_time is null
 `
 
@@ -37,6 +39,11 @@ WHERE t.delete_time is null
 		rawSql += " AND t.gid = ?"
 		args = append(args, *param.Gid)
 	}
+	cond, condArgs := linkSearchCondition("t", param.LinkFilter)
+	if cond != "" {
+		rawSql += " AND " + cond
+		args = append(args, condArgs...)
+	}
 	if param.OrderTag != nil {
 		rawSql += `
 ORDER BY 
@@ -65,9 +72,12 @@ ORDER BY
 	}
 
 	var total int64
-	qu := q.db.WithContext(ctx).Model(&po.Link{})
+	qu := q.db.WithContext(ctx).Table(po.TableNameLink + " t").Where("t.delete_time is null")
 	if param.Gid != nil {
-		qu = qu.Where("gid = ?", *param.Gid)
+		qu = qu.Where("t.gid = ?", *param.Gid)
+	}
+	if cond != "" {
+		qu = qu.Where(cond, condArgs...)
 	}
 
 	if err = qu.Count(&total).Error; err != nil {
@@ -100,7 +110,7 @@ func (q LinkShardingQuery) PageRecycleBin(
 	rawSql := `
 SELECT t.*, COALESCE(s.today_pv, 0) AS todayPv, COALESCE(s.today_uv, 0) AS todayUv, COALESCE(s.today_uip, 0) AS todayUip
 FROM t_link t
-LEFT JOIN t_link_stats_today s ON t.short_uri = s.short_uri AND s.date = current_date
+LEFT JOIN t_link_stats_today s ON t.domain = s.domain AND t.short_uri = s.short_uri AND s.date = current_date
 WHERE t.gid IN (?) AND t.status = 1
 ORDER BY t.update_time
 LIMIT ? OFFSET ?;
@@ -125,9 +135,9 @@ LIMIT ? OFFSET ?;
 	return
 }
 
-func (q LinkShardingQuery) GetLink(ctx context.Context, shortUri string) (lk *link.Link, err error) {
+func (q LinkShardingQuery) GetLink(ctx context.Context, domain, shortUri string) (lk *link.Link, err error) {
 	linkGotoPo := po.LinkGoto{}
-	if err = q.db.WithContext(ctx).Where("short_uri = ?", shortUri).First(&linkGotoPo).Error; err != nil {
+	if err = q.db.WithContext(ctx).Where("short_uri = ? AND domain = ?", shortUri, domain).First(&linkGotoPo).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			err = errno.LinkNotExists
 		}
@@ -135,7 +145,7 @@ func (q LinkShardingQuery) GetLink(ctx context.Context, shortUri string) (lk *li
 	}
 
 	linkPo := po.Link{}
-	if err = q.db.WithContext(ctx).Where("short_uri = ? AND gid = ?", shortUri, linkGotoPo.Gid).First(&linkPo).Error; err != nil {
+	if err = q.db.WithContext(ctx).Where("short_uri = ? AND domain = ? AND gid = ?", shortUri, domain, linkGotoPo.Gid).First(&linkPo).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			err = errno.LinkNotExists
 		}
@@ -143,25 +153,8 @@ func (q LinkShardingQuery) GetLink(ctx context.Context, shortUri string) (lk *li
 	}
 
 	// 将持久化对象转换为领域模型
-	lk = &link.Link{}
-
-	var start *time.Time
-	var end *time.Time
-	if linkPo.StartDate.Valid {
-		*start = linkPo.StartDate.Time
-	}
-	if linkPo.EndDate.Valid {
-		*end = linkPo.EndDate.Time
-	}
-
-	validDate := &link.ValidDate{}
-	if validDate, err = link.NewValidDate(linkPo.ValidType, start, end); err != nil {
-		return
-	}
-
-	if lk, err = q.linkFactory.NewLinkFromDB(linkPo.ID, linkPo.Gid, linkPo.ShortUri, linkPo.OriginalUrl, linkPo.Status,
-		linkPo.CreateType, linkPo.Favicon, linkPo.Desc, validDate); err != nil {
-		return
+	if lk = q.assembler.LinkPoToLinkEntity(linkPo); lk == nil {
+		err = errno.LinkNotExists
 	}
 
 	return
@@ -391,3 +384,16 @@ func (q LinkShardingQuery) GetLink(ctx context.Context, shortUri string) (lk *li
 //
 //	return res, nil
 //}
+
+// GetGroupSetting 分组默认配置表不参与分片，直接查询即可
+func (q LinkShardingQuery) GetGroupSetting(ctx context.Context, gid string) (query.GroupSetting, error) {
+	res := query.GroupSetting{Gid: gid}
+	var settingPo po.LinkGroupSetting
+	err := q.db.WithContext(ctx).Where("gid = ?", gid).First(&settingPo).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return res, err
+	}
+	res.UrlParams = settingPo.UrlParams
+	res.FallbackUrl = settingPo.FallbackUrl
+	return res, nil
+}
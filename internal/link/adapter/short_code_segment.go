@@ -0,0 +1,25 @@
+package adapter
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"shortlink/internal/link/common/constant"
+)
+
+// RedisSegmentAllocator 基于 Redis INCRBY 的号段分配器
+type RedisSegmentAllocator struct {
+	rdb *redis.Client
+}
+
+func NewRedisSegmentAllocator(rdb *redis.Client) *RedisSegmentAllocator {
+	if rdb == nil {
+		panic("nil redis client")
+	}
+	return &RedisSegmentAllocator{rdb: rdb}
+}
+
+func (a RedisSegmentAllocator) Allocate(step int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constant.DefaultTimeOut)
+	defer cancel()
+	return a.rdb.IncrBy(ctx, constant.ShortCodeSegmentKey, step).Result()
+}
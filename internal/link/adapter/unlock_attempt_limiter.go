@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"shortlink/internal/link/common/constant"
+	"time"
+)
+
+// RedisUnlockAttemptLimiter 基于 Redis 计数的密码尝试次数限制
+//
+// 计数在窗口期内累计，首次尝试时设置过期时间，窗口结束后自动清零
+type RedisUnlockAttemptLimiter struct {
+	rdb         *redis.Client
+	maxAttempts int64
+	window      time.Duration
+}
+
+func NewRedisUnlockAttemptLimiter(rdb *redis.Client, maxAttempts int, window time.Duration) *RedisUnlockAttemptLimiter {
+	if rdb == nil {
+		panic("nil redis client")
+	}
+	return &RedisUnlockAttemptLimiter{rdb: rdb, maxAttempts: int64(maxAttempts), window: window}
+}
+
+func (l RedisUnlockAttemptLimiter) Attempt(ctx context.Context, shortUri, remoteAddr string) (bool, error) {
+	// 先检查再计数时，并发请求都能通过检查，自增、设置过期时间和比较需要在同一个脚本中完成
+	luaScript := `
+        local count = redis.call("INCR", KEYS[1])
+        if count == 1 then
+            redis.call("PEXPIRE", KEYS[1], ARGV[1])
+        end
+        return count
+    `
+	count, err := l.rdb.Eval(ctx, luaScript, []string{l.key(shortUri, remoteAddr)}, l.window.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return count <= l.maxAttempts, nil
+}
+
+func (l RedisUnlockAttemptLimiter) Reset(ctx context.Context, shortUri, remoteAddr string) error {
+	return l.rdb.Del(ctx, l.key(shortUri, remoteAddr)).Err()
+}
+
+func (l RedisUnlockAttemptLimiter) key(shortUri, remoteAddr string) string {
+	return fmt.Sprintf(constant.LinkUnlockAttemptsKey, shortUri, remoteAddr)
+}
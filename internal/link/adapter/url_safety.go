@@ -0,0 +1,422 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2/log"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/toolkit"
+	"shortlink/internal/link/domain/link"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileBlocklistChecker 从本地文件加载黑名单，文件修改后自动重新加载
+//
+// 文件每行一个域名或链接前缀，空行和 # 开头的行会被忽略
+type FileBlocklistChecker struct {
+	path           string
+	reloadInterval time.Duration
+
+	mu        sync.RWMutex
+	blocklist link.Blocklist
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func NewFileBlocklistChecker(path string, reloadInterval time.Duration) (*FileBlocklistChecker, error) {
+	c := &FileBlocklistChecker{path: path, reloadInterval: reloadInterval, checkedAt: time.Now()}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *FileBlocklistChecker) Check(_ context.Context, rawUrl string) error {
+	c.reloadIfModified()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.blocklist.Blocks(rawUrl) {
+		return errno.LinkUnsafeUrl
+	}
+	return nil
+}
+
+// reloadIfModified 距离上次检查超过 reloadInterval 时检查文件修改时间，加载失败时继续使用原有黑名单
+func (c *FileBlocklistChecker) reloadIfModified() {
+	c.mu.RLock()
+	due := time.Since(c.checkedAt) >= c.reloadInterval
+	c.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.checkedAt) < c.reloadInterval {
+		return
+	}
+	c.checkedAt = time.Now()
+	if err := c.load(); err != nil {
+		log.Errorf("重新加载黑名单失败 %s: %v", c.path, err)
+	}
+}
+
+// load 调用方需持有写锁
+func (c *FileBlocklistChecker) load() error {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+	if c.blocklist != nil && info.ModTime().Equal(c.modTime) {
+		return nil
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	blocklist, err := parseBlocklist(f)
+	if err != nil {
+		return err
+	}
+	c.blocklist, c.modTime = blocklist, info.ModTime()
+	return nil
+}
+
+func parseBlocklist(r io.Reader) (link.Blocklist, error) {
+	blocklist := link.Blocklist{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist = append(blocklist, line)
+	}
+	return blocklist, scanner.Err()
+}
+
+// SafeBrowsingLookup 查询链接是否命中威胁列表，与 Google Safe Browsing Lookup API 对应
+type SafeBrowsingLookup interface {
+	// Lookup 返回命中威胁列表的链接
+	Lookup(ctx context.Context, urls []string) ([]string, error)
+}
+
+// SafeBrowsingChecker 命中威胁列表的跳转目标视为不安全
+type SafeBrowsingChecker struct {
+	lookup SafeBrowsingLookup
+}
+
+func NewSafeBrowsingChecker(lookup SafeBrowsingLookup) SafeBrowsingChecker {
+	if lookup == nil {
+		panic("nil lookup")
+	}
+	return SafeBrowsingChecker{lookup: lookup}
+}
+
+func (c SafeBrowsingChecker) Check(ctx context.Context, rawUrl string) error {
+	matches, err := c.lookup.Lookup(ctx, []string{rawUrl})
+	if err != nil {
+		return err
+	}
+	if len(matches) > 0 {
+		return errno.LinkUnsafeUrl
+	}
+	return nil
+}
+
+// safeBrowsingThreatTypes 查询的威胁类型
+var safeBrowsingThreatTypes = []string{
+	"MALWARE", "SOCIAL_ENGINEERING", "UNWANTED_SOFTWARE", "POTENTIALLY_HARMFUL_APPLICATION",
+}
+
+// SafeBrowsingApi 通过 Safe Browsing v4 threatMatches:find 接口查询，endpoint 可以指向兼容的服务
+type SafeBrowsingApi struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+func NewSafeBrowsingApi(endpoint string, apiKey string, client *http.Client) *SafeBrowsingApi {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &SafeBrowsingApi{endpoint: strings.TrimSuffix(endpoint, "/"), apiKey: apiKey, client: client}
+}
+
+type safeBrowsingEntry struct {
+	Url string `json:"url"`
+}
+
+type safeBrowsingRequest struct {
+	Client struct {
+		ClientId      string `json:"clientId"`
+		ClientVersion string `json:"clientVersion"`
+	} `json:"client"`
+	ThreatInfo struct {
+		ThreatTypes      []string            `json:"threatTypes"`
+		PlatformTypes    []string            `json:"platformTypes"`
+		ThreatEntryTypes []string            `json:"threatEntryTypes"`
+		ThreatEntries    []safeBrowsingEntry `json:"threatEntries"`
+	} `json:"threatInfo"`
+}
+
+type safeBrowsingResponse struct {
+	Matches []struct {
+		Threat safeBrowsingEntry `json:"threat"`
+	} `json:"matches"`
+}
+
+func (a SafeBrowsingApi) Lookup(ctx context.Context, urls []string) ([]string, error) {
+	body := safeBrowsingRequest{}
+	body.Client.ClientId = "shortlink"
+	body.Client.ClientVersion = "1.0"
+	body.ThreatInfo.ThreatTypes = safeBrowsingThreatTypes
+	body.ThreatInfo.PlatformTypes = []string{"ANY_PLATFORM"}
+	body.ThreatInfo.ThreatEntryTypes = []string{"URL"}
+	for _, u := range urls {
+		body.ThreatInfo.ThreatEntries = append(body.ThreatInfo.ThreatEntries, safeBrowsingEntry{Url: u})
+	}
+	payload, err := sonic.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := a.endpoint + "/v4/threatMatches:find?key=" + url.QueryEscape(a.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("safe browsing lookup failed: %s", resp.Status)
+	}
+
+	var res safeBrowsingResponse
+	if err = sonic.ConfigDefault.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	matches := make([]string, 0, len(res.Matches))
+	for _, m := range res.Matches {
+		matches = append(matches, m.Threat.Url)
+	}
+	return matches, nil
+}
+
+// StubSafeBrowsing 本地桩实现，命中给定域名或链接前缀即视为威胁，用于开发和测试环境
+type StubSafeBrowsing struct {
+	threats link.Blocklist
+}
+
+func NewStubSafeBrowsing(threats []string) StubSafeBrowsing {
+	return StubSafeBrowsing{threats: threats}
+}
+
+func (s StubSafeBrowsing) Lookup(_ context.Context, urls []string) ([]string, error) {
+	var matches []string
+	for _, u := range urls {
+		if s.threats.Blocks(u) {
+			matches = append(matches, u)
+		}
+	}
+	return matches, nil
+}
+
+// ShortDomainGetter 查询自定义域名，域名不存在时返回 errno.LinkDomainNotExists
+type ShortDomainGetter interface {
+	GetShortDomain(ctx context.Context, host string) (*link.ShortDomain, error)
+}
+
+// RedirectLoopChecker 跳转目标指向本站短链接时会形成循环，同时逐跳跟随跳转检查其他短链接服务中转回来的情况
+//
+// 跳转目标由用户填写，跟随跳转时不连接内网和本机地址
+type RedirectLoopChecker struct {
+	defaultDomain string
+	domains       ShortDomainGetter
+	client        *http.Client
+	maxHops       int
+	// 跟随整条跳转链的总超时时间，为 0 时不限制
+	timeout time.Duration
+}
+
+// errDialRefused 跳转目标解析到内网或本机地址
+var errDialRefused = errors.New("refuse to dial non-public address")
+
+// newGuardedTransport 只连接 allow 允许的地址
+//
+// 在建立连接时检查解析后的地址，DNS 重绑定和跳转后的每一跳同样会被检查
+func newGuardedTransport(allow func(ip net.IP) bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allow(ip) {
+				return errDialRefused
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// newNoRedirectClient 不跟随跳转的 HTTP 客户端，只连接 allow 允许的地址
+func newNoRedirectClient(allow func(ip net.IP) bool) *http.Client {
+	return &http.Client{
+		Transport:     newGuardedTransport(allow),
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// maxFollowRedirects 服务端请求跳转目标时最多跟随的跳转次数
+const maxFollowRedirects = 5
+
+// newGuardedClient 跟随跳转的 HTTP 客户端，只连接 allow 允许的地址，只能跳转到 http/https 链接
+//
+// 用于服务端主动请求用户提交的链接，避免通过短链接探测或读取内网服务
+func newGuardedClient(allow func(ip net.IP) bool, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: newGuardedTransport(allow),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFollowRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxFollowRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("unsupported redirect scheme: %s", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// isPublicIP 是否为公网地址
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	// 运营商级 NAT、文档示例等保留地址段
+	return !toolkit.IsReservedIP(ip.String())
+}
+
+// NewRedirectLoopChecker maxHops 为 0 时只检查跳转目标本身，timeout 为跟随整条跳转链的总超时时间
+func NewRedirectLoopChecker(
+	defaultDomain string,
+	domains ShortDomainGetter,
+	maxHops int,
+	timeout time.Duration,
+) *RedirectLoopChecker {
+	if domains == nil {
+		panic("nil domains")
+	}
+	return &RedirectLoopChecker{
+		defaultDomain: strings.ToLower(defaultDomain),
+		domains:       domains,
+		client:        newNoRedirectClient(isPublicIP),
+		maxHops:       maxHops,
+		timeout:       timeout,
+	}
+}
+
+func (c RedirectLoopChecker) Check(ctx context.Context, rawUrl string) error {
+	current, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		// 链接格式由其他校验负责
+		return nil
+	}
+	if c.timeout > 0 && c.maxHops > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	for hop := 0; ; hop++ {
+		own, err := c.isOwnHost(ctx, current)
+		if err != nil {
+			return err
+		}
+		if own {
+			return errno.LinkRedirectLoop
+		}
+		if hop >= c.maxHops {
+			return nil
+		}
+		if current = c.nextHop(ctx, current); current == nil {
+			return nil
+		}
+	}
+}
+
+// isOwnHost 注册的域名可以带端口，带端口和不带端口的形式都需要检查
+func (c RedirectLoopChecker) isOwnHost(ctx context.Context, u *url.URL) (bool, error) {
+	hosts := []string{strings.ToLower(u.Host)}
+	if u.Port() != "" {
+		hosts = append(hosts, strings.ToLower(u.Hostname()))
+	}
+	for _, host := range hosts {
+		if host == "" {
+			continue
+		}
+		if host == c.defaultDomain {
+			return true, nil
+		}
+		_, err := c.domains.GetShortDomain(ctx, host)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, errno.LinkDomainNotExists) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// nextHop 返回跳转的下一个地址，无法访问、超时或不再跳转时返回 nil
+func (c RedirectLoopChecker) nextHop(ctx context.Context, current *url.URL) *url.URL {
+	if current.Scheme != "http" && current.Scheme != "https" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, current.String(), nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return nil
+	}
+	next, err := resp.Location()
+	if err != nil {
+		return nil
+	}
+	return next
+}
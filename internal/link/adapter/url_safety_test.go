@@ -0,0 +1,143 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/domain/link"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileBlocklistChecker_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("# comment\nevil.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewFileBlocklistChecker(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err = c.Check(ctx, "https://evil.com"); !errors.Is(err, errno.LinkUnsafeUrl) {
+		t.Errorf("Check() = %v, want %v", err, errno.LinkUnsafeUrl)
+	}
+	if err = c.Check(ctx, "https://bad.com"); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+
+	if err = os.WriteFile(path, []byte("bad.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err = os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Check(ctx, "https://bad.com"); !errors.Is(err, errno.LinkUnsafeUrl) {
+		t.Errorf("Check() after reload = %v, want %v", err, errno.LinkUnsafeUrl)
+	}
+	if err = c.Check(ctx, "https://evil.com"); err != nil {
+		t.Errorf("Check() after reload = %v, want nil", err)
+	}
+}
+
+// stubShortDomains 测试用的自定义域名
+type stubShortDomains map[string]bool
+
+func (s stubShortDomains) GetShortDomain(_ context.Context, host string) (*link.ShortDomain, error) {
+	if !s[host] {
+		return nil, errno.LinkDomainNotExists
+	}
+	return link.NewShortDomainFromDB(host, "alice", link.DomainVerified, false, "", nil, 0), nil
+}
+
+func TestRedirectLoopChecker_Check(t *testing.T) {
+	// 其他短链接服务 /loop 跳转回本站，/safe 跳转到外部网站
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/loop":
+			http.Redirect(w, r, "/hop", http.StatusFound)
+		case "/hop":
+			http.Redirect(w, r, "https://s.brand.invalid/abc", http.StatusMovedPermanently)
+		case "/safe":
+			http.Redirect(w, r, "https://example.invalid", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	// 测试服务监听在本机地址，默认不允许连接
+	allowAll := newNoRedirectClient(func(net.IP) bool { return true })
+	c := NewRedirectLoopChecker("nurl.ink", stubShortDomains{"s.brand.invalid": true}, 3, time.Second)
+	c.client = allowAll
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{"default domain", "https://nurl.ink/abc", errno.LinkRedirectLoop},
+		{"custom domain with port", "https://s.brand.invalid:443/abc", errno.LinkRedirectLoop},
+		{"redirect chain", srv.URL + "/loop", errno.LinkRedirectLoop},
+		{"external redirect", srv.URL + "/safe", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.Check(context.Background(), tt.url); !errors.Is(err, tt.want) {
+				t.Errorf("Check() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 跳数不足时无法发现循环
+	shallow := NewRedirectLoopChecker("nurl.ink", stubShortDomains{"s.brand.invalid": true}, 1, time.Second)
+	shallow.client = allowAll
+	if err := shallow.Check(context.Background(), srv.URL+"/loop"); err != nil {
+		t.Errorf("Check() with 1 hop = %v, want nil", err)
+	}
+
+	// 不连接本机地址
+	hits.Store(0)
+	guarded := NewRedirectLoopChecker("nurl.ink", stubShortDomains{"s.brand.invalid": true}, 3, time.Second)
+	if err := guarded.Check(context.Background(), srv.URL+"/loop"); err != nil {
+		t.Errorf("Check() on loopback = %v, want nil", err)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("loopback address should not be dialed, got %d requests", hits.Load())
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+// MaxBulkLinks 单次批量操作的短链接数量上限
+const MaxBulkLinks = 1000
+
+type bulkLinksHandler struct {
+	repo         domain.Repository
+	linkFactory  *link.Factory
+	groupChecker GroupChecker
+	logger       *slog.Logger
+}
+
+type BulkLinksHandler decorator.CommandHandler[*BulkLinks]
+
+func NewBulkLinksHandler(
+	repo domain.Repository,
+	linkFactory *link.Factory,
+	groupChecker GroupChecker,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) BulkLinksHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if linkFactory == nil {
+		panic("nil linkFactory")
+	}
+	if groupChecker == nil {
+		panic("nil groupChecker")
+	}
+
+	return decorator.ApplyCommandDecorators[*BulkLinks](
+		bulkLinksHandler{repo: repo, linkFactory: linkFactory, groupChecker: groupChecker, logger: logger},
+		logger,
+		metricsClient,
+	)
+}
+
+// BulkLinks 对多个短链接执行移动分组、打标签、修改有效期、启停用或移入回收站
+//
+// 单个短链接失败不影响其他短链接，结果中逐个返回
+type BulkLinks struct {
+	// 需要操作的短链接 Gid 可以为空
+	Ids []link.Identifier
+	// 操作
+	Action link.BulkAction
+	// 执行结果
+	result *BulkLinksResult
+}
+
+type BulkLinksResult struct {
+	// 与去重后的 Ids 一一对应
+	Items     []link.BulkItemResult
+	Succeeded int
+	Failed    int
+}
+
+func (c BulkLinks) ExecutionResult() *BulkLinksResult {
+	return c.result
+}
+
+func (h bulkLinksHandler) Handle(ctx context.Context, cmd *BulkLinks) error {
+	action, err := cmd.Action.Validate()
+	if err != nil {
+		return err
+	}
+
+	ids := make([]link.Identifier, 0, len(cmd.Ids))
+	seen := make(map[string]struct{}, len(cmd.Ids))
+	for _, id := range cmd.Ids {
+		key := link.Key(id.Domain, id.ShortUri)
+		if _, ok := seen[key]; ok || id.ShortUri == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return errno.LinkBulkEmpty
+	}
+	if len(ids) > MaxBulkLinks {
+		return errno.LinkBulkTooMany
+	}
+
+	if action.Op == link.BulkMove {
+		// 只能移入自己的分组，API Key 不能移入未授权的分组
+		if err = apikey.CheckGroup(ctx, action.Gid); err != nil {
+			return err
+		}
+		if err = checkGroupOwned(ctx, h.groupChecker, action.Gid); err != nil {
+			return err
+		}
+		// 按全部移入目标分组计算，已经在目标分组中的短链接同样计入
+		count, err := h.repo.CountLinksByGid(ctx, action.Gid)
+		if err != nil {
+			return err
+		}
+		if err = h.linkFactory.CheckGroupLinkCount(count + len(ids) - 1); err != nil {
+			return err
+		}
+	}
+
+	items, err := h.repo.BulkUpdateLinks(ctx, ids, action)
+	if items == nil {
+		return err
+	}
+	if err != nil {
+		// 数据库已经提交，缓存会在过期后恢复一致
+		h.logger.WarnContext(ctx, "批量操作后更新缓存失败", "op", action.Op, "error", err)
+	}
+
+	res := &BulkLinksResult{Items: items}
+	for _, item := range items {
+		if item.Err == nil {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+	cmd.result = res
+	return nil
+}
+
+// GroupChecker 查询分组的所有者，分组归属于用户服务
+type GroupChecker interface {
+	OwnedBy(ctx context.Context, gid, username string) (bool, error)
+}
+
+// checkGroupOwned 分组不属于当前用户时按分组不存在处理
+func checkGroupOwned(ctx context.Context, checker GroupChecker, gid string) error {
+	username, _ := ctx.Value("username").(string)
+	owned, err := checker.OwnedBy(ctx, gid, username)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return errno.LinkGroupNotExists
+	}
+	return nil
+}
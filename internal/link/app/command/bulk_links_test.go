@@ -0,0 +1,88 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+	"testing"
+)
+
+// stubBulkRepository 测试用的仓库，只实现批量操作用到的方法
+type stubBulkRepository struct {
+	domain.Repository
+	updated bool
+}
+
+func (r *stubBulkRepository) CountLinksByGid(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *stubBulkRepository) BulkUpdateLinks(_ context.Context, ids []link.Identifier, _ link.BulkAction) ([]link.BulkItemResult, error) {
+	r.updated = true
+	return make([]link.BulkItemResult, len(ids)), nil
+}
+
+// stubGroupChecker 分组标识到所有者的映射
+type stubGroupChecker map[string]string
+
+func (c stubGroupChecker) OwnedBy(_ context.Context, gid, username string) (bool, error) {
+	owner, ok := c[gid]
+	return ok && owner == username, nil
+}
+
+func TestBulkLinksHandler_MoveTargetGroup(t *testing.T) {
+	linkFactory, err := link.NewFactory(link.FactoryConfig{
+		Domain:            "nurl.ink",
+		MaxAttempts:       1,
+		MaxLinksPerGroup:  100,
+		DefaultFavicon:    "https://nurl.ink/favicon.ico",
+		DefaultGid:        "default",
+		DefaultExpiration: 15,
+		DefaultCreateType: link.CreateByApi,
+		DefaultValidType:  link.ValidTypeTemporary,
+		AliasMinLength:    4,
+		AliasMaxLength:    8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := stubGroupChecker{"mine": "alice", "allowed": "alice", "other": "bob"}
+
+	tests := []struct {
+		name string
+		gid  string
+		key  *apikey.Key
+		want error
+	}{
+		{"own group", "mine", nil, nil},
+		{"foreign group", "other", nil, errno.LinkGroupNotExists},
+		{"missing group", "missing", nil, errno.LinkGroupNotExists},
+		{"api key allowed group", "allowed", &apikey.Key{Gids: []string{"allowed"}}, nil},
+		{"api key unauthorized group", "mine", &apikey.Key{Gids: []string{"allowed"}}, errno.ApiKeyGroupDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubBulkRepository{}
+			h := bulkLinksHandler{repo: repo, linkFactory: linkFactory, groupChecker: groups, logger: slog.Default()}
+
+			ctx := context.WithValue(context.Background(), "username", "alice")
+			if tt.key != nil {
+				ctx = context.WithValue(ctx, apikey.LocalKey, tt.key)
+			}
+			err := h.Handle(ctx, &BulkLinks{
+				Ids:    []link.Identifier{{ShortUri: "abc123"}},
+				Action: link.BulkAction{Op: link.BulkMove, Gid: tt.gid},
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Handle() = %v, want %v", err, tt.want)
+			}
+			if repo.updated != (tt.want == nil) {
+				t.Errorf("BulkUpdateLinks called = %v, want %v", repo.updated, tt.want == nil)
+			}
+		})
+	}
+}
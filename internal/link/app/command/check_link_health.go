@@ -0,0 +1,99 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+	"sync"
+)
+
+type checkLinkHealthHandler struct {
+	repo     domain.Repository
+	prober   DestinationProber
+	notifier BrokenLinkNotifier
+}
+
+type CheckLinkHealthHandler decorator.CommandHandler[CheckLinkHealth]
+
+// NewCheckLinkHealthHandler notifier 为 nil 时不发送通知
+func NewCheckLinkHealthHandler(
+	repo domain.Repository,
+	prober DestinationProber,
+	notifier BrokenLinkNotifier,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) CheckLinkHealthHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if prober == nil {
+		panic("nil prober")
+	}
+
+	return decorator.ApplyCommandDecorators[CheckLinkHealth](
+		checkLinkHealthHandler{repo: repo, prober: prober, notifier: notifier},
+		logger,
+		metricsClient,
+	)
+}
+
+// CheckLinkHealth 抽样检查短链接的跳转目标是否可以访问
+type CheckLinkHealth struct {
+	// 每个分组检查的短链接数量
+	SamplePerGroup int
+	// 同时检查的短链接数量
+	Concurrency int
+}
+
+// DestinationProber 请求跳转目标，无法访问时状态码为 0
+type DestinationProber interface {
+	Probe(ctx context.Context, rawUrl string) link.HealthCheck
+}
+
+// BrokenLinkNotifier 通知分组所有者跳转目标失效的短链接
+type BrokenLinkNotifier interface {
+	NotifyBrokenLinks(ctx context.Context, gid string, reports []link.HealthReport) error
+}
+
+func (h checkLinkHealthHandler) Handle(ctx context.Context, cmd CheckLinkHealth) error {
+	targets, err := h.repo.SampleActiveLinks(ctx, cmd.SamplePerGroup)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]link.HealthReport, len(targets))
+	sem := make(chan struct{}, max(cmd.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			reports[i] = link.HealthReport{HealthTarget: t, Check: h.prober.Probe(ctx, t.OriginalUrl)}
+		}()
+	}
+	wg.Wait()
+
+	// 只通知新发现失效的短链接，持续失效的短链接不重复通知
+	broken := make(map[string][]link.HealthReport)
+	for _, r := range reports {
+		err = errors.Join(err, h.repo.SaveHealthCheck(ctx, r.Identifier, r.Check))
+		if r.BecameBroken(r.Check) {
+			broken[r.Gid] = append(broken[r.Gid], r)
+		}
+	}
+	if h.notifier == nil {
+		return err
+	}
+	for gid, rs := range broken {
+		err = errors.Join(err, h.notifier.NotifyBrokenLinks(ctx, gid, rs))
+	}
+	return err
+}
@@ -2,15 +2,21 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/base_event"
 	"shortlink/internal/base/cache"
 	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
 	"shortlink/internal/base/lock"
 	"shortlink/internal/base/metrics"
 	"shortlink/internal/link/common/constant"
 	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/event"
 	"shortlink/internal/link/domain/link"
+	"strings"
 	"time"
 )
 
@@ -18,7 +24,12 @@ type createLinkHandler struct {
 	repo             domain.Repository
 	locker           lock.DistributedLock
 	linkFactory      *link.Factory
+	urlChecker       link.UrlSafetyChecker
+	eventBus         base_event.EventBus
 	distributedCache cache.DistributedCache
+	// 为空时不检查邮箱是否已验证
+	accountChecker AccountChecker
+	logger         *slog.Logger
 }
 
 type CreateLink struct {
@@ -36,6 +47,26 @@ type CreateLink struct {
 	EndDate *time.Time
 	// 描述
 	Desc string
+	// 自定义短链接 为空时随机生成
+	Alias string
+	// 访问密码 为空时不设置保护
+	Password string
+	// 最大访问次数 0 表示不限制
+	MaxVisits int
+	// 条件路由规则
+	RoutingRules link.RoutingRules
+	// 按权重分流的跳转目标
+	Variants link.Variants
+	// 跳转时对查询参数的处理 为 nil 时使用分组默认配置
+	UrlParams *link.UrlParams
+	// 跳转状态码 0 表示使用 302
+	RedirectCode link.RedirectCode
+	// 备用链接 为空时使用分组默认配置
+	FallbackUrl string
+	// 自定义域名 为空时使用默认域名，只能使用自己注册的域名
+	Domain string
+	// 标签
+	Tags link.Tags
 	// 是否加锁
 	WithLock bool
 	// 执行结果
@@ -58,6 +89,9 @@ func NewCreateLinkHandler(
 	linkFactory *link.Factory,
 	repo domain.Repository,
 	locker lock.DistributedLock,
+	urlChecker link.UrlSafetyChecker,
+	eventBus base_event.EventBus,
+	accountChecker AccountChecker,
 	logger *slog.Logger,
 	metrics metrics.Client,
 ) CreateLinkHandler {
@@ -67,9 +101,23 @@ func NewCreateLinkHandler(
 	if locker == nil {
 		panic("nil locker")
 	}
+	if urlChecker == nil {
+		panic("nil urlChecker")
+	}
+	if eventBus == nil {
+		panic("nil eventBus")
+	}
 
 	return decorator.ApplyCommandDecorators[*CreateLink](
-		createLinkHandler{repo: repo, locker: locker, linkFactory: linkFactory},
+		createLinkHandler{
+			repo:           repo,
+			locker:         locker,
+			linkFactory:    linkFactory,
+			urlChecker:     urlChecker,
+			eventBus:       eventBus,
+			accountChecker: accountChecker,
+			logger:         logger,
+		},
 		logger,
 		metrics,
 	)
@@ -80,6 +128,15 @@ func (h createLinkHandler) Handle(
 	cmd *CreateLink,
 ) (err error) {
 
+	if err = checkMailVerified(ctx, h.accountChecker); err != nil {
+		return
+	}
+	// 通过 API Key 创建时只能使用授权的分组
+	if err = apikey.CheckGroup(ctx, cmd.Gid); err != nil {
+		return
+	}
+	cmd.CreateType = apiCreateType(ctx, cmd.CreateType)
+
 	// 获取分布式锁
 	if cmd.WithLock {
 		lockKey := fmt.Sprintf(constant.LinkCreateLockKey, cmd.OriginalUrl)
@@ -104,12 +161,53 @@ func (h createLinkHandler) Handle(
 		return err
 	}
 
+	// 未指定查询参数处理和备用链接时使用分组默认配置
+	urlParams, fallbackUrl := cmd.UrlParams, cmd.FallbackUrl
+	if urlParams == nil || fallbackUrl == "" {
+		var setting *link.GroupSetting
+		if setting, err = h.repo.GetGroupSetting(ctx, cmd.Gid); err != nil {
+			return err
+		}
+		if urlParams == nil {
+			urlParams = setting.UrlParams()
+		}
+		if fallbackUrl == "" {
+			fallbackUrl = setting.FallbackUrl()
+		}
+	}
+
+	// 自定义域名 不属于当前用户的域名视为不存在
+	var domain *link.ShortDomain
+	var host string
+	if cmd.Domain != "" {
+		if domain, err = h.repo.GetShortDomain(ctx, strings.ToLower(cmd.Domain)); err != nil {
+			return err
+		}
+		if username, _ := ctx.Value("username").(string); !domain.OwnedBy(username) {
+			return errno.LinkDomainNotExists
+		}
+		host = domain.Host()
+	}
+
 	// 创建短链接实体
 	lk := &link.Link{}
 	lk, err = h.linkFactory.NewAvailableLink(
 		cmd.OriginalUrl, cmd.Gid, cmd.CreateType, cmd.ValidType, cmd.StartDate, cmd.EndDate, cmd.Desc,
+		link.CreateOptions{
+			Alias:        cmd.Alias,
+			Password:     cmd.Password,
+			MaxVisits:    cmd.MaxVisits,
+			RoutingRules: cmd.RoutingRules,
+			Variants:     cmd.Variants,
+			UrlParams:    urlParams,
+			RedirectCode: cmd.RedirectCode,
+			FallbackUrl:  fallbackUrl,
+			Domain:       domain,
+			Tags:         cmd.Tags,
+		},
+		// 短链接只需在同一域名下唯一
 		func(shortUri string) (exists bool, err error) {
-			if exists, err = h.repo.ShortUriExists(ctx, shortUri); err != nil {
+			if exists, err = h.repo.ShortUriExists(ctx, link.Key(host, shortUri)); err != nil {
 				return exists, err
 			}
 			return exists, nil
@@ -119,8 +217,17 @@ func (h createLinkHandler) Handle(
 		return
 	}
 
+	// 跳转目标安全检查
+	if err = link.CheckDestinations(ctx, h.urlChecker, lk.Destinations()); err != nil {
+		return
+	}
+
 	// 持久化短链接
 	if err = h.repo.CreateLink(ctx, lk); err != nil {
+		// 布隆过滤器存在误判，自定义短链接仍可能在落库时发生冲突
+		if cmd.Alias != "" && errors.Is(err, errno.LinkAlreadyExists) {
+			err = errno.LinkAliasAlreadyExists
+		}
 		return
 	}
 
@@ -130,5 +237,52 @@ func (h createLinkHandler) Handle(
 		FullShortUrl: lk.FullShortUrl(),
 		OriginalUrl:  lk.OriginalUrl(),
 	}
-	return
+
+	// 异步获取跳转目标的标题和图标
+	publishLinkCreated(ctx, h.eventBus, h.logger, lk)
+	return nil
+}
+
+// publishLinkCreated 发布短链接创建事件
+//
+// 短链接已经落库，发布失败只影响标题和图标的获取，不能让调用方误以为创建失败而重试
+func publishLinkCreated(ctx context.Context, eventBus base_event.EventBus, logger *slog.Logger, lk *link.Link) {
+	if err := eventBus.Publish(ctx, event.NewLinkCreatedEvent(event.LinkCreated{
+		Gid:         lk.Gid(),
+		Domain:      lk.Domain(),
+		ShortUri:    lk.ShortUri(),
+		OriginalUrl: lk.OriginalUrl(),
+	})); err != nil {
+		logger.WarnContext(ctx, "发布短链接创建事件失败", "short_uri", lk.ShortUri(), "error", err)
+	}
+}
+
+// AccountChecker 查询创建者的账号状态，用户归属于用户服务
+type AccountChecker interface {
+	MailVerified(ctx context.Context, username string) (bool, error)
+}
+
+// checkMailVerified 邮箱未验证的用户不能创建短链接，checker 为空时不检查
+func checkMailVerified(ctx context.Context, checker AccountChecker) error {
+	if checker == nil {
+		return nil
+	}
+	username, _ := ctx.Value("username").(string)
+	verified, err := checker.MailVerified(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return errno.UserMailUnverified
+	}
+	return nil
+}
+
+// apiCreateType 通过 API Key 创建的短链接固定为接口创建，不使用请求中的创建类型
+func apiCreateType(ctx context.Context, createType *link.CreateType) *link.CreateType {
+	if _, ok := apikey.FromContext(ctx); ok {
+		byApi := link.CreateByApi
+		return &byApi
+	}
+	return createType
 }
@@ -3,6 +3,8 @@ package command
 import (
 	"context"
 	"log/slog"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/base_event"
 	"shortlink/internal/base/decorator"
 	"shortlink/internal/base/metrics"
 	"shortlink/internal/link/domain"
@@ -13,6 +15,11 @@ import (
 type createLinkBatchHandler struct {
 	repo        domain.Repository
 	linkFactory *link.Factory
+	urlChecker  link.UrlSafetyChecker
+	eventBus    base_event.EventBus
+	// 为空时不检查邮箱是否已验证
+	accountChecker AccountChecker
+	logger         *slog.Logger
 }
 
 type CreateLinkBatchHandler decorator.CommandHandler[*CreateLinkBatch]
@@ -20,6 +27,9 @@ type CreateLinkBatchHandler decorator.CommandHandler[*CreateLinkBatch]
 func NewCreateLinkBatchHandler(
 	linkFactory *link.Factory,
 	repo domain.Repository,
+	urlChecker link.UrlSafetyChecker,
+	eventBus base_event.EventBus,
+	accountChecker AccountChecker,
 	logger *slog.Logger,
 	metricsClient metrics.Client,
 ) CreateLinkBatchHandler {
@@ -29,9 +39,22 @@ func NewCreateLinkBatchHandler(
 	if repo == nil {
 		panic("repo is nil")
 	}
+	if urlChecker == nil {
+		panic("urlChecker is nil")
+	}
+	if eventBus == nil {
+		panic("eventBus is nil")
+	}
 
 	return decorator.ApplyCommandDecorators[*CreateLinkBatch](
-		createLinkBatchHandler{repo: repo, linkFactory: linkFactory},
+		createLinkBatchHandler{
+			repo:           repo,
+			linkFactory:    linkFactory,
+			urlChecker:     urlChecker,
+			eventBus:       eventBus,
+			accountChecker: accountChecker,
+			logger:         logger,
+		},
 		logger,
 		metricsClient,
 	)
@@ -52,6 +75,8 @@ type CreateLinkBatch struct {
 	StartDate *time.Time
 	// 有效期 - 结束时间
 	EndDate *time.Time
+	// 标签 所有短链接使用相同的标签
+	Tags link.Tags
 	// 执行结果
 	result *CreateLinkBatchResult
 }
@@ -70,6 +95,16 @@ func (h createLinkBatchHandler) Handle(
 	cmd *CreateLinkBatch,
 ) (err error) {
 
+	if err = checkMailVerified(ctx, h.accountChecker); err != nil {
+		return
+	}
+	if err = apikey.CheckGroup(ctx, cmd.Gid); err != nil {
+		return
+	}
+	cmd.CreateType = apiCreateType(ctx, cmd.CreateType)
+
+	builder := newBatchLinkBuilder(h.repo, h.linkFactory, h.urlChecker)
+
 	lks := make([]*link.Link, 0)
 	linkInfos := make([]CreateLinkResult, len(cmd.OriginalUrls))
 	for idx, originalUrl := range cmd.OriginalUrls {
@@ -78,16 +113,17 @@ func (h createLinkBatchHandler) Handle(
 			desc = cmd.Descs[idx]
 		}
 
-		lk := &link.Link{}
-		lk, err = h.linkFactory.NewAvailableLink(
-			originalUrl, cmd.Gid, cmd.CreateType, cmd.ValidType, cmd.StartDate, cmd.EndDate, desc,
-			func(shortUri string) (exists bool, err error) {
-				if exists, err = h.repo.ShortUriExists(ctx, shortUri); err != nil {
-					return exists, err
-				}
-				return exists, nil
-			},
-		)
+		var lk *link.Link
+		lk, err = builder.build(ctx, batchLink{
+			OriginalUrl: originalUrl,
+			Desc:        desc,
+			Gid:         cmd.Gid,
+			CreateType:  cmd.CreateType,
+			ValidType:   cmd.ValidType,
+			StartDate:   cmd.StartDate,
+			EndDate:     cmd.EndDate,
+			Tags:        cmd.Tags,
+		})
 		if err != nil {
 			return err
 		}
@@ -110,5 +146,100 @@ func (h createLinkBatchHandler) Handle(
 		LinkInfos:    linkInfos,
 	}
 
+	for _, lk := range lks {
+		publishLinkCreated(ctx, h.eventBus, h.logger, lk)
+	}
 	return nil
 }
+
+// batchLink 批量创建的单个短链接参数
+type batchLink struct {
+	OriginalUrl string
+	Desc        string
+	Gid         string
+	CreateType  *link.CreateType
+	ValidType   *link.ValidType
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Tags        link.Tags
+}
+
+// batchLinkBuilder 逐个创建并校验批量创建的短链接实体，批量创建和批量导入共用
+//
+// 批量创建的短链接统一使用分组默认配置；同一批次内已创建的短链接计入分组数量限制，
+// 生成的短链接在批次内去重，避免写入前相互冲突
+type batchLinkBuilder struct {
+	repo        domain.Repository
+	linkFactory *link.Factory
+	urlChecker  link.UrlSafetyChecker
+	settings    map[string]*link.GroupSetting
+	counts      map[string]int
+	shortUris   map[string]struct{}
+}
+
+func newBatchLinkBuilder(
+	repo domain.Repository,
+	linkFactory *link.Factory,
+	urlChecker link.UrlSafetyChecker,
+) *batchLinkBuilder {
+	return &batchLinkBuilder{
+		repo:        repo,
+		linkFactory: linkFactory,
+		urlChecker:  urlChecker,
+		settings:    make(map[string]*link.GroupSetting),
+		counts:      make(map[string]int),
+		shortUris:   make(map[string]struct{}),
+	}
+}
+
+func (b *batchLinkBuilder) build(ctx context.Context, params batchLink) (*link.Link, error) {
+	setting, ok := b.settings[params.Gid]
+	if !ok {
+		var err error
+		if setting, err = b.repo.GetGroupSetting(ctx, params.Gid); err != nil {
+			return nil, err
+		}
+		b.settings[params.Gid] = setting
+	}
+
+	count, ok := b.counts[params.Gid]
+	if !ok {
+		var err error
+		if count, err = b.repo.CountLinksByGid(ctx, params.Gid); err != nil {
+			return nil, err
+		}
+		b.counts[params.Gid] = count
+	}
+	if err := b.linkFactory.CheckGroupLinkCount(count); err != nil {
+		return nil, err
+	}
+
+	lk, err := b.linkFactory.NewAvailableLink(
+		params.OriginalUrl, params.Gid, params.CreateType, params.ValidType, params.StartDate, params.EndDate, params.Desc,
+		link.CreateOptions{
+			UrlParams:   setting.UrlParams(),
+			FallbackUrl: setting.FallbackUrl(),
+			Tags:        params.Tags,
+		},
+		func(shortUri string) (exists bool, err error) {
+			if _, ok := b.shortUris[shortUri]; ok {
+				return true, nil
+			}
+			if exists, err = b.repo.ShortUriExists(ctx, shortUri); err != nil {
+				return exists, err
+			}
+			return exists, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = link.CheckDestinations(ctx, b.urlChecker, lk.Destinations()); err != nil {
+		return nil, err
+	}
+
+	b.counts[params.Gid] = count + 1
+	b.shortUris[lk.ShortUri()] = struct{}{}
+	return lk, nil
+}
@@ -0,0 +1,169 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+type exportLinksHandler struct {
+	repo        domain.Repository
+	scanner     GroupLinkScanner
+	resultStore JobResultStore
+	// 分组内短链接不超过该数量时直接返回文件，否则在后台导出
+	syncMaxLinks int
+	logger       *slog.Logger
+}
+
+type ExportLinksHandler decorator.CommandHandler[*ExportLinks]
+
+func NewExportLinksHandler(
+	repo domain.Repository,
+	scanner GroupLinkScanner,
+	resultStore JobResultStore,
+	syncMaxLinks int,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) ExportLinksHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if scanner == nil {
+		panic("nil scanner")
+	}
+	if resultStore == nil {
+		panic("nil resultStore")
+	}
+
+	return decorator.ApplyCommandDecorators[*ExportLinks](
+		exportLinksHandler{
+			repo:         repo,
+			scanner:      scanner,
+			resultStore:  resultStore,
+			syncMaxLinks: syncMaxLinks,
+			logger:       logger,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+// ExportLinks 导出分组下的短链接及访问统计
+//
+// 分组较小时直接返回文件内容，较大时创建后台任务，通过任务下载结果文件
+type ExportLinks struct {
+	// 分组ID
+	Gid string
+	// 文件格式
+	Format link.ExportFormat
+	// 执行结果
+	result *ExportLinksResult
+}
+
+type ExportLinksResult struct {
+	// 后台导出的任务ID 为空时表示已同步导出
+	JobId string
+	// 同步导出的文件名
+	Name string
+	// 同步导出的文件内容
+	Data []byte
+}
+
+func (c ExportLinks) ExecutionResult() *ExportLinksResult {
+	return c.result
+}
+
+// GroupLinkScanner 分批读取分组下的短链接及访问统计
+type GroupLinkScanner interface {
+	ScanGroupLinks(ctx context.Context, gid string, fn func([]link.ExportRow) error) error
+}
+
+func (h exportLinksHandler) Handle(ctx context.Context, cmd *ExportLinks) error {
+	count, err := h.repo.CountLinksByGid(ctx, cmd.Gid)
+	if err != nil {
+		return err
+	}
+
+	if count <= h.syncMaxLinks {
+		var buf bytes.Buffer
+		if err = h.export(ctx, cmd, &buf, nil); err != nil {
+			return err
+		}
+		cmd.result = &ExportLinksResult{
+			Name: fmt.Sprintf("links-%s%s", cmd.Gid, cmd.Format.Ext()),
+			Data: buf.Bytes(),
+		}
+		return nil
+	}
+
+	username, _ := ctx.Value("username").(string)
+	job, err := link.NewJob(link.JobExport, username, false, count)
+	if err != nil {
+		return err
+	}
+	if err = h.repo.SaveJob(ctx, job); err != nil {
+		return err
+	}
+	cmd.result = &ExportLinksResult{JobId: job.Id()}
+
+	go h.run(detachContext(ctx), job, *cmd)
+
+	return nil
+}
+
+func (h exportLinksHandler) run(ctx context.Context, job *link.Job, cmd ExportLinks) {
+	defer func() {
+		if r := recover(); r != nil {
+			failJob(ctx, h.repo, h.logger, job, fmt.Errorf("%v", r))
+		}
+	}()
+
+	job.Start()
+	if err := h.repo.SaveJob(ctx, job); err != nil {
+		failJob(ctx, h.repo, h.logger, job, err)
+		return
+	}
+
+	name := job.Id() + cmd.Format.Ext()
+	if err := writeJobResult(ctx, h.resultStore, name, func(w io.Writer) error {
+		// 每批写入后保存一次进度
+		return h.export(ctx, &cmd, w, func(n int) error {
+			job.RowsSucceeded(n)
+			return h.repo.SaveJob(ctx, job)
+		})
+	}); err != nil {
+		failJob(ctx, h.repo, h.logger, job, err)
+		return
+	}
+
+	job.Succeed(name)
+	if err := h.repo.SaveJob(ctx, job); err != nil {
+		h.logger.ErrorContext(ctx, "保存任务失败", "job", job.Id(), "error", err)
+	}
+}
+
+// export 将分组下的短链接写入 w，progress 为 nil 时不记录进度
+func (h exportLinksHandler) export(ctx context.Context, cmd *ExportLinks, w io.Writer, progress func(n int) error) error {
+	ew, err := link.NewExportWriter(cmd.Format, w)
+	if err != nil {
+		return err
+	}
+	if err = h.scanner.ScanGroupLinks(ctx, cmd.Gid, func(rows []link.ExportRow) error {
+		if err := ew.Write(rows); err != nil {
+			return err
+		}
+		if progress != nil {
+			return progress(len(rows))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return ew.Close()
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+type fetchLinkMetadataHandler struct {
+	repo    domain.Repository
+	fetcher MetadataFetcher
+}
+
+type FetchLinkMetadataHandler decorator.CommandHandler[FetchLinkMetadata]
+
+func NewFetchLinkMetadataHandler(
+	repo domain.Repository,
+	fetcher MetadataFetcher,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) FetchLinkMetadataHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if fetcher == nil {
+		panic("nil fetcher")
+	}
+
+	return decorator.ApplyCommandDecorators[FetchLinkMetadata](
+		fetchLinkMetadataHandler{repo: repo, fetcher: fetcher},
+		logger,
+		metricsClient,
+	)
+}
+
+// FetchLinkMetadata 获取跳转目标的标题、描述和图标，并更新到短链接
+type FetchLinkMetadata struct {
+	link.Identifier
+	// 原始链接
+	OriginalUrl string
+}
+
+// MetadataFetcher 获取跳转目标页面的元信息
+type MetadataFetcher interface {
+	Fetch(ctx context.Context, rawUrl string) (link.Metadata, error)
+}
+
+func (h fetchLinkMetadataHandler) Handle(ctx context.Context, cmd FetchLinkMetadata) error {
+	metadata, err := h.fetcher.Fetch(ctx, cmd.OriginalUrl)
+	if err != nil {
+		// 目标页面无法访问时保留默认图标，重试通常也无法成功
+		slog.WarnContext(ctx, "failed to fetch link metadata", "url", cmd.OriginalUrl, "error", err)
+		return nil
+	}
+	if metadata == (link.Metadata{}) {
+		return nil
+	}
+	return h.repo.SaveLinkMetadata(ctx, cmd.Identifier, metadata)
+}
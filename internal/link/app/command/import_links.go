@@ -0,0 +1,248 @@
+package command
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+	"strconv"
+)
+
+// importChunkSize 每批写入的行数，每批写入后保存一次进度
+const importChunkSize = 100
+
+type importLinksHandler struct {
+	repo        domain.Repository
+	linkFactory *link.Factory
+	urlChecker  link.UrlSafetyChecker
+	eventBus    base_event.EventBus
+	resultStore JobResultStore
+	// 为空时不检查邮箱是否已验证
+	accountChecker AccountChecker
+	logger         *slog.Logger
+}
+
+type ImportLinksHandler decorator.CommandHandler[*ImportLinks]
+
+func NewImportLinksHandler(
+	linkFactory *link.Factory,
+	repo domain.Repository,
+	urlChecker link.UrlSafetyChecker,
+	eventBus base_event.EventBus,
+	resultStore JobResultStore,
+	accountChecker AccountChecker,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) ImportLinksHandler {
+	if linkFactory == nil {
+		panic("linkFactory is nil")
+	}
+	if repo == nil {
+		panic("repo is nil")
+	}
+	if urlChecker == nil {
+		panic("urlChecker is nil")
+	}
+	if eventBus == nil {
+		panic("eventBus is nil")
+	}
+	if resultStore == nil {
+		panic("resultStore is nil")
+	}
+
+	return decorator.ApplyCommandDecorators[*ImportLinks](
+		importLinksHandler{
+			repo:           repo,
+			linkFactory:    linkFactory,
+			urlChecker:     urlChecker,
+			eventBus:       eventBus,
+			resultStore:    resultStore,
+			accountChecker: accountChecker,
+			logger:         logger,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+// ImportLinks 批量导入短链接
+//
+// 创建任务后立即返回，导入在后台执行，通过任务查询进度和下载结果文件
+type ImportLinks struct {
+	// 已解析的导入文件
+	Rows []link.ImportRow
+	// 行内未指定分组时使用的分组
+	Gid string
+	// 创建类型 0:接口创建 1:控制台创建
+	CreateType *link.CreateType
+	// 只校验不写入
+	DryRun bool
+	// 执行结果
+	result *ImportLinksResult
+}
+
+type ImportLinksResult struct {
+	JobId string
+}
+
+func (c ImportLinks) ExecutionResult() *ImportLinksResult {
+	return c.result
+}
+
+// importResult 单行的导入结果
+type importResult struct {
+	row link.ImportRow
+	gid string
+	lk  *link.Link
+	err error
+}
+
+func (h importLinksHandler) Handle(ctx context.Context, cmd *ImportLinks) error {
+	if len(cmd.Rows) == 0 {
+		return errno.LinkImportEmpty
+	}
+	if err := checkMailVerified(ctx, h.accountChecker); err != nil {
+		return err
+	}
+
+	username, _ := ctx.Value("username").(string)
+	job, err := link.NewJob(link.JobImport, username, cmd.DryRun, len(cmd.Rows))
+	if err != nil {
+		return err
+	}
+	if err = h.repo.SaveJob(ctx, job); err != nil {
+		return err
+	}
+	cmd.result = &ImportLinksResult{JobId: job.Id()}
+
+	go h.run(detachContext(ctx), job, *cmd)
+
+	return nil
+}
+
+func (h importLinksHandler) run(ctx context.Context, job *link.Job, cmd ImportLinks) {
+	defer func() {
+		if r := recover(); r != nil {
+			failJob(ctx, h.repo, h.logger, job, fmt.Errorf("%v", r))
+		}
+	}()
+
+	job.Start()
+	if err := h.repo.SaveJob(ctx, job); err != nil {
+		failJob(ctx, h.repo, h.logger, job, err)
+		return
+	}
+
+	builder := newBatchLinkBuilder(h.repo, h.linkFactory, h.urlChecker)
+	results := make([]importResult, len(cmd.Rows))
+	var pending []int
+	for idx, row := range cmd.Rows {
+		res := &results[idx]
+		res.row = row
+		res.gid = row.Gid
+		if res.gid == "" {
+			res.gid = cmd.Gid
+		}
+
+		switch {
+		case row.Err != nil:
+			res.err = row.Err
+		case res.gid == "":
+			res.err = errno.LinkImportMissingGid
+		default:
+			res.lk, res.err = builder.build(ctx, batchLink{
+				OriginalUrl: row.OriginalUrl,
+				Desc:        row.Desc,
+				Gid:         res.gid,
+				CreateType:  cmd.CreateType,
+				ValidType:   row.ValidType,
+				StartDate:   row.StartDate,
+				EndDate:     row.EndDate,
+			})
+		}
+		switch {
+		case res.err != nil:
+			job.RowFailed(row.Line, res.err)
+		case cmd.DryRun:
+			job.RowSucceeded()
+		default:
+			pending = append(pending, idx)
+		}
+
+		if (idx+1)%importChunkSize == 0 {
+			h.flush(ctx, job, results, pending)
+			pending = pending[:0]
+			if err := h.repo.SaveJob(ctx, job); err != nil {
+				failJob(ctx, h.repo, h.logger, job, err)
+				return
+			}
+		}
+	}
+	h.flush(ctx, job, results, pending)
+
+	name := job.Id() + ".csv"
+	if err := writeJobResult(ctx, h.resultStore, name, func(w io.Writer) error {
+		return writeImportResult(w, results, cmd.DryRun)
+	}); err != nil {
+		failJob(ctx, h.repo, h.logger, job, err)
+		return
+	}
+
+	job.Succeed(name)
+	if err := h.repo.SaveJob(ctx, job); err != nil {
+		h.logger.ErrorContext(ctx, "保存任务失败", "job", job.Id(), "error", err)
+	}
+}
+
+// flush 写入校验通过的短链接，批量写入失败时逐条写入以定位出错的行
+func (h importLinksHandler) flush(ctx context.Context, job *link.Job, results []importResult, pending []int) {
+	if len(pending) == 0 {
+		return
+	}
+	lks := make([]*link.Link, 0, len(pending))
+	for _, idx := range pending {
+		lks = append(lks, results[idx].lk)
+	}
+
+	batchErr := h.repo.CreateLinkBatch(ctx, lks)
+	for _, idx := range pending {
+		res := &results[idx]
+		if batchErr != nil {
+			if err := h.repo.CreateLink(ctx, res.lk); err != nil {
+				res.err = err
+				job.RowFailed(res.row.Line, err)
+				continue
+			}
+		}
+		job.RowSucceeded()
+		publishLinkCreated(ctx, h.eventBus, h.logger, res.lk)
+	}
+}
+
+// writeImportResult 生成结果文件 每行对应导入文件中的一行
+func writeImportResult(out io.Writer, results []importResult, dryRun bool) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"line", "original_url", "gid", "full_short_url", "status", "error"})
+	for _, res := range results {
+		record := []string{strconv.Itoa(res.row.Line), res.row.OriginalUrl, res.gid, "", "", ""}
+		switch {
+		case res.err != nil:
+			record[4], record[5] = "failed", res.err.Error()
+		case dryRun:
+			// 只校验时短链接不会保留，不输出
+			record[4] = "valid"
+		default:
+			record[3], record[4] = res.lk.FullShortUrl(), "created"
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+// JobResultStore 保存任务的结果文件
+type JobResultStore interface {
+	// Create 创建结果文件，Close 之后才可以下载
+	Create(ctx context.Context, name string) (link.JobResultWriter, error)
+}
+
+// detachContext 请求结束后请求上下文会被回收，后台任务只保留用户信息
+func detachContext(ctx context.Context) context.Context {
+	detached := context.Background()
+	for _, key := range []string{"username", "tenant_id"} {
+		if v := ctx.Value(key); v != nil {
+			detached = context.WithValue(detached, key, v)
+		}
+	}
+	return detached
+}
+
+// failJob 记录任务中断
+func failJob(ctx context.Context, repo domain.Repository, logger *slog.Logger, job *link.Job, err error) {
+	logger.ErrorContext(ctx, "后台任务中断", "job", job.Id(), "type", job.Type(), "error", err)
+	job.Fail(err)
+	if saveErr := repo.SaveJob(ctx, job); saveErr != nil {
+		logger.ErrorContext(ctx, "保存任务失败", "job", job.Id(), "error", saveErr)
+	}
+}
+
+// writeJobResult 创建并写入结果文件，全部写入成功后结果文件才可以下载
+func writeJobResult(ctx context.Context, store JobResultStore, name string, write func(w io.Writer) error) error {
+	f, err := store.Create(ctx, name)
+	if err != nil {
+		return err
+	}
+	// 写入失败时丢弃结果文件，避免不完整的文件被当作结果下载
+	if err = write(f); err != nil {
+		_ = f.Abort()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/event"
+	"shortlink/internal/link/domain/link"
+	"time"
+)
+
+type refreshLinkStatusHandler struct {
+	repo     domain.Repository
+	eventBus base_event.EventBus
+}
+
+type RefreshLinkStatusHandler decorator.CommandHandler[RefreshLinkStatus]
+
+func NewRefreshLinkStatusHandler(
+	repo domain.Repository,
+	eventBus base_event.EventBus,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) RefreshLinkStatusHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if eventBus == nil {
+		panic("nil eventBus")
+	}
+
+	return decorator.ApplyCommandDecorators[RefreshLinkStatus](
+		refreshLinkStatusHandler{repo: repo, eventBus: eventBus},
+		logger,
+		metricsClient,
+	)
+}
+
+// RefreshLinkStatus 按有效期流转短链接状态
+type RefreshLinkStatus struct {
+	// 基准时间
+	Now time.Time
+}
+
+func (h refreshLinkStatusHandler) Handle(ctx context.Context, cmd RefreshLinkStatus) error {
+	// 先激活再过期，开始和结束时间都已经过去的短链接最终为过期
+	activated, activateErr := h.repo.ActivatePendingLinks(ctx, cmd.Now)
+	publishErr := publishStatusChanges(ctx, h.eventBus, activated)
+
+	expired, expireErr := h.repo.ExpireOverdueLinks(ctx, cmd.Now)
+	publishErr = errors.Join(publishErr, publishStatusChanges(ctx, h.eventBus, expired))
+
+	return errors.Join(activateErr, expireErr, publishErr)
+}
+
+// publishStatusChanges 发布短链接状态变化事件
+func publishStatusChanges(ctx context.Context, eventBus base_event.EventBus, changes []link.StatusChange) (err error) {
+	for _, c := range changes {
+		e := event.NewLinkStatusChangedEvent(event.LinkStatusChanged{
+			Gid:      c.Gid,
+			Domain:   c.Domain,
+			ShortUri: c.ShortUri,
+			From:     c.From,
+			To:       c.To,
+		})
+		err = errors.Join(err, eventBus.Publish(ctx, e))
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+type registerShortDomainHandler struct {
+	repo domain.Repository
+}
+
+type RegisterShortDomainHandler decorator.CommandHandler[RegisterShortDomain]
+
+func NewRegisterShortDomainHandler(
+	repo domain.Repository,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) RegisterShortDomainHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[RegisterShortDomain](
+		registerShortDomainHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+// RegisterShortDomain 注册自定义域名，归属于当前用户
+type RegisterShortDomain struct {
+	// 域名
+	Host string
+	// 是否通过 https 访问
+	UseTLS bool
+}
+
+func (h registerShortDomainHandler) Handle(ctx context.Context, cmd RegisterShortDomain) error {
+	username, _ := ctx.Value("username").(string)
+	d, err := link.NewShortDomain(cmd.Host, username, cmd.UseTLS)
+	if err != nil {
+		return err
+	}
+	return h.repo.CreateShortDomain(ctx, d)
+}
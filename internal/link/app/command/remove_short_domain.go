@@ -0,0 +1,52 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"strings"
+)
+
+type removeShortDomainHandler struct {
+	repo domain.Repository
+}
+
+type RemoveShortDomainHandler decorator.CommandHandler[RemoveShortDomain]
+
+func NewRemoveShortDomainHandler(
+	repo domain.Repository,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) RemoveShortDomainHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[RemoveShortDomain](
+		removeShortDomainHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+// RemoveShortDomain 删除自定义域名
+type RemoveShortDomain struct {
+	// 域名
+	Host string
+}
+
+func (h removeShortDomainHandler) Handle(ctx context.Context, cmd RemoveShortDomain) error {
+	host := strings.ToLower(cmd.Host)
+	d, err := h.repo.GetShortDomain(ctx, host)
+	if err != nil {
+		return err
+	}
+	// 不属于当前用户的域名视为不存在
+	if username, _ := ctx.Value("username").(string); !d.OwnedBy(username) {
+		return errno.LinkDomainNotExists
+	}
+	return h.repo.RemoveShortDomain(ctx, host)
+}
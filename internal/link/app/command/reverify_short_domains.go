@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"time"
+)
+
+type reverifyShortDomainsHandler struct {
+	repo     domain.Repository
+	verifier DomainVerifier
+	eventBus base_event.EventBus
+	// 已验证的域名连续验证失败多少次后撤销
+	maxFailures int
+}
+
+type ReverifyShortDomainsHandler decorator.CommandHandler[ReverifyShortDomains]
+
+func NewReverifyShortDomainsHandler(
+	repo domain.Repository,
+	verifier DomainVerifier,
+	eventBus base_event.EventBus,
+	maxFailures int,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) ReverifyShortDomainsHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if verifier == nil {
+		panic("nil verifier")
+	}
+	if eventBus == nil {
+		panic("nil eventBus")
+	}
+
+	return decorator.ApplyCommandDecorators[ReverifyShortDomains](
+		reverifyShortDomainsHandler{repo: repo, verifier: verifier, eventBus: eventBus, maxFailures: maxFailures},
+		logger,
+		metricsClient,
+	)
+}
+
+// ReverifyShortDomains 定期重新验证所有自定义域名，域名过期或解析被修改后及时停用其下的短链接
+type ReverifyShortDomains struct {
+	// 基准时间
+	Now time.Time
+}
+
+func (h reverifyShortDomainsHandler) Handle(ctx context.Context, cmd ReverifyShortDomains) error {
+	domains, err := h.repo.ListAllShortDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 单个域名失败不影响其他域名，暂时无法验证的域名保持原状态等待下次验证
+	var errs error
+	for _, d := range domains {
+		passed, verifyErr := h.verifier.Verify(ctx, d)
+		if verifyErr != nil {
+			errs = errors.Join(errs, fmt.Errorf("verify %s: %w", d.Host(), verifyErr))
+			continue
+		}
+		if err = recordVerification(ctx, h.repo, h.eventBus, d, passed, cmd.Now, h.maxFailures); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("record %s: %w", d.Host(), err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+type scanLinkSafetyHandler struct {
+	repo       domain.Repository
+	urlChecker link.UrlSafetyChecker
+	eventBus   base_event.EventBus
+}
+
+type ScanLinkSafetyHandler decorator.CommandHandler[ScanLinkSafety]
+
+func NewScanLinkSafetyHandler(
+	repo domain.Repository,
+	urlChecker link.UrlSafetyChecker,
+	eventBus base_event.EventBus,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) ScanLinkSafetyHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if urlChecker == nil {
+		panic("nil urlChecker")
+	}
+	if eventBus == nil {
+		panic("nil eventBus")
+	}
+
+	return decorator.ApplyCommandDecorators[ScanLinkSafety](
+		scanLinkSafetyHandler{repo: repo, urlChecker: urlChecker, eventBus: eventBus},
+		logger,
+		metricsClient,
+	)
+}
+
+// ScanLinkSafety 重新检查已有短链接的跳转目标，黑名单或威胁列表更新后命中的短链接会被禁用
+type ScanLinkSafety struct{}
+
+func (h scanLinkSafetyHandler) Handle(ctx context.Context, _ ScanLinkSafety) error {
+	// 单个短链接检查失败不影响其他短链接
+	var checkErr error
+	scanErr := h.repo.ScanAvailableLinks(ctx, func(targets []link.LinkTargets) error {
+		var flagged []link.Identifier
+		for _, t := range targets {
+			if err := link.CheckDestinations(ctx, h.urlChecker, t.Urls); err != nil {
+				if !link.IsUnsafe(err) {
+					checkErr = errors.Join(checkErr, err)
+					continue
+				}
+				flagged = append(flagged, t.Identifier)
+			}
+		}
+		changes, err := h.repo.ForbidLinks(ctx, flagged)
+		return errors.Join(err, publishStatusChanges(ctx, h.eventBus, changes))
+	})
+	return errors.Join(scanErr, checkErr)
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain/link"
+)
+
+type unlockLinkHandler struct {
+	readModel UnlockLinkReadModel
+	limiter   UnlockAttemptLimiter
+}
+
+type UnlockLinkHandler decorator.CommandHandler[UnlockLink]
+
+func NewUnlockLinkHandler(
+	readModel UnlockLinkReadModel,
+	limiter UnlockAttemptLimiter,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) UnlockLinkHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+	if limiter == nil {
+		panic("nil limiter")
+	}
+
+	return decorator.ApplyCommandDecorators[UnlockLink](
+		unlockLinkHandler{readModel: readModel, limiter: limiter},
+		logger,
+		metricsClient,
+	)
+}
+
+// UnlockLink 使用访问密码解锁短链接
+type UnlockLink struct {
+	// 访问的自定义域名 为空表示默认域名
+	Domain string
+	// 短链接
+	ShortUri string
+	// 访问密码
+	Password string
+	// 访问者 IP 用于限制失败次数
+	RemoteAddr string
+}
+
+type UnlockLinkReadModel interface {
+	GetLink(ctx context.Context, domain, shortUri string) (*link.Link, error)
+}
+
+// UnlockAttemptLimiter 按 IP + 短链接 限制密码尝试次数
+type UnlockAttemptLimiter interface {
+	// Attempt 记录一次尝试并返回是否允许，计数和检查需要是原子的，否则并发请求可以超出次数限制
+	Attempt(ctx context.Context, shortUri, remoteAddr string) (bool, error)
+	// Reset 解锁成功后清空尝试次数
+	Reset(ctx context.Context, shortUri, remoteAddr string) error
+}
+
+func (h unlockLinkHandler) Handle(ctx context.Context, cmd UnlockLink) (err error) {
+	key := link.Key(cmd.Domain, cmd.ShortUri)
+
+	var allowed bool
+	if allowed, err = h.limiter.Attempt(ctx, key, cmd.RemoteAddr); err != nil {
+		return
+	}
+	if !allowed {
+		return errno.LinkUnlockTooManyAttempts
+	}
+
+	var lk *link.Link
+	if lk, err = h.readModel.GetLink(ctx, cmd.Domain, cmd.ShortUri); err != nil {
+		return
+	}
+
+	if !link.NewCacheValue(lk).VerifyPassword(cmd.Password) {
+		return errno.LinkPasswordIncorrect
+	}
+
+	return h.limiter.Reset(ctx, key, cmd.RemoteAddr)
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+)
+
+type updateGroupSettingHandler struct {
+	repo domain.Repository
+}
+
+type UpdateGroupSettingHandler decorator.CommandHandler[UpdateGroupSetting]
+
+func NewUpdateGroupSettingHandler(
+	repo domain.Repository,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) UpdateGroupSettingHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[UpdateGroupSetting](
+		updateGroupSettingHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+type UpdateGroupSetting struct {
+	// 分组ID
+	Gid string
+	// 默认的查询参数处理 为 nil 时清空
+	UrlParams *link.UrlParams
+	// 默认的备用链接 为空时清空
+	FallbackUrl string
+}
+
+func (h updateGroupSettingHandler) Handle(ctx context.Context, cmd UpdateGroupSetting) error {
+	setting, err := link.NewGroupSetting(cmd.Gid, cmd.UrlParams, cmd.FallbackUrl)
+	if err != nil {
+		return err
+	}
+	return h.repo.SaveGroupSetting(ctx, setting)
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"log/slog"
+	"shortlink/internal/base/apikey"
 	"shortlink/internal/base/decorator"
 	"shortlink/internal/base/metrics"
 	"shortlink/internal/link/domain"
@@ -11,28 +12,40 @@ import (
 )
 
 type updateLinkHandler struct {
-	repo domain.Repository
+	repo        domain.Repository
+	linkFactory *link.Factory
+	urlChecker  link.UrlSafetyChecker
 }
 
 type UpdateLinkHandler decorator.CommandHandler[UpdateLink]
 
 func NewUpdateLinkHandler(
+	linkFactory *link.Factory,
 	repo domain.Repository,
+	urlChecker link.UrlSafetyChecker,
 	logger *slog.Logger,
 	metricsClient metrics.Client,
 ) UpdateLinkHandler {
+	if linkFactory == nil {
+		panic("nil linkFactory")
+	}
 	if repo == nil {
 		panic("nil repo")
 	}
+	if urlChecker == nil {
+		panic("nil urlChecker")
+	}
 
 	return decorator.ApplyCommandDecorators[UpdateLink](
-		updateLinkHandler{repo: repo},
+		updateLinkHandler{repo: repo, linkFactory: linkFactory, urlChecker: urlChecker},
 		logger,
 		metricsClient,
 	)
 }
 
 type UpdateLink struct {
+	// 自定义域名 为空表示默认域名
+	Domain string
 	// 短链接
 	ShortUri string
 	// 原始链接
@@ -49,17 +62,54 @@ type UpdateLink struct {
 	ValidEndDate *time.Time
 	// 描述
 	Desc *string
+	// 访问密码 为空字符串时取消密码保护
+	Password *string
+	// 最大访问次数 0 表示不限制
+	MaxVisits *int
+	// 条件路由规则 传空数组时清空
+	RoutingRules *link.RoutingRules
+	// 按权重分流的跳转目标 传空数组时清空
+	Variants *link.Variants
+	// 跳转时对查询参数的处理
+	UrlParams *link.UrlParams
+	// 跳转状态码 301/302/307/308
+	RedirectCode *link.RedirectCode
+	// 备用链接 传空字符串时清空
+	FallbackUrl *string
+	// 标签 传空数组时清空
+	Tags *link.Tags
 }
 
 func (h updateLinkHandler) Handle(ctx context.Context, cmd UpdateLink) (err error) {
+	// 修改后的跳转目标与创建时相同需要满足白名单限制
+	if err = h.linkFactory.VerifyUpdate(cmd.OriginalUrl, cmd.RoutingRules, cmd.Variants, cmd.FallbackUrl); err != nil {
+		return err
+	}
 	return h.repo.UpdateLink(
 		ctx,
+		cmd.Domain,
 		cmd.ShortUri,
 		func(ctx context.Context, lk *link.Link) (*link.Link, error) {
-			err = lk.Update(cmd.Gid, cmd.OriginalUrl, cmd.Status, cmd.ValidType, cmd.ValidEndDate, cmd.Desc)
+			// API Key 不能修改未授权分组的短链接，也不能移入未授权的分组
+			if err = apikey.CheckGroup(ctx, lk.Gid()); err != nil {
+				return nil, err
+			}
+			destinations, status := lk.Destinations(), lk.Status()
+			err = lk.Update(cmd.Gid, cmd.OriginalUrl, cmd.Status, cmd.ValidType, cmd.ValidEndDate, cmd.Desc, cmd.Password, cmd.MaxVisits, cmd.RoutingRules, cmd.Variants, cmd.UrlParams, cmd.RedirectCode, cmd.FallbackUrl, cmd.Tags)
 			if err != nil {
 				return nil, err
 			}
+			if err = apikey.CheckGroup(ctx, lk.Gid()); err != nil {
+				return nil, err
+			}
+			// 跳转目标没有变化时不重新检查，因安全问题被禁用的短链接重新启用时需要检查
+			recheck := link.DestinationsChanged(destinations, lk.Destinations()) ||
+				(status == link.StatusForbidden && lk.Status() != link.StatusForbidden)
+			if recheck {
+				if err = link.CheckDestinations(ctx, h.urlChecker, lk.Destinations()); err != nil {
+					return nil, err
+				}
+			}
 			return lk, nil
 		},
 	)
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain"
+	"shortlink/internal/link/domain/link"
+	"strings"
+	"time"
+)
+
+type verifyShortDomainHandler struct {
+	repo     domain.Repository
+	verifier DomainVerifier
+	eventBus base_event.EventBus
+	// 已验证的域名连续验证失败多少次后撤销
+	maxFailures int
+}
+
+type VerifyShortDomainHandler decorator.CommandHandler[VerifyShortDomain]
+
+func NewVerifyShortDomainHandler(
+	repo domain.Repository,
+	verifier DomainVerifier,
+	eventBus base_event.EventBus,
+	maxFailures int,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) VerifyShortDomainHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if verifier == nil {
+		panic("nil verifier")
+	}
+	if eventBus == nil {
+		panic("nil eventBus")
+	}
+
+	return decorator.ApplyCommandDecorators[VerifyShortDomain](
+		verifyShortDomainHandler{repo: repo, verifier: verifier, eventBus: eventBus, maxFailures: maxFailures},
+		logger,
+		metricsClient,
+	)
+}
+
+// VerifyShortDomain 立即验证自定义域名的所有权
+type VerifyShortDomain struct {
+	// 域名
+	Host string
+}
+
+// DomainVerifier 验证用户对域名的所有权
+type DomainVerifier interface {
+	// Verify 返回验证是否通过，err 不为空表示暂时无法完成验证
+	Verify(ctx context.Context, domain *link.ShortDomain) (bool, error)
+}
+
+func (h verifyShortDomainHandler) Handle(ctx context.Context, cmd VerifyShortDomain) error {
+	d, err := h.repo.GetShortDomain(ctx, strings.ToLower(cmd.Host))
+	if err != nil {
+		return err
+	}
+	// 不属于当前用户的域名视为不存在
+	if username, _ := ctx.Value("username").(string); !d.OwnedBy(username) {
+		return errno.LinkDomainNotExists
+	}
+
+	passed, err := h.verifier.Verify(ctx, d)
+	if err != nil {
+		return err
+	}
+	if err = recordVerification(ctx, h.repo, h.eventBus, d, passed, time.Now(), h.maxFailures); err != nil {
+		return err
+	}
+	if !passed {
+		return errno.LinkDomainVerifyFailed
+	}
+	return nil
+}
+
+// recordVerification 保存验证结果，域名由验证通过变为验证失败时停用其下的短链接
+//
+// 重新通过验证后不会自动启用这些短链接，需要用户确认后手动启用
+func recordVerification(
+	ctx context.Context,
+	repo domain.Repository,
+	eventBus base_event.EventBus,
+	d *link.ShortDomain,
+	passed bool,
+	now time.Time,
+	maxFailures int,
+) error {
+	revoked := d.RecordVerification(passed, now, maxFailures)
+	if err := repo.SaveShortDomainVerification(ctx, d); err != nil {
+		return err
+	}
+	if !revoked {
+		return nil
+	}
+	changes, disableErr := repo.DisableDomainLinks(ctx, d.Host())
+	return errors.Join(disableErr, publishStatusChanges(ctx, eventBus, changes))
+}
@@ -0,0 +1,31 @@
+package listener
+
+import (
+	"context"
+	"github.com/bytedance/sonic"
+	"shortlink/internal/link/app"
+	"shortlink/internal/link/app/command"
+	"shortlink/internal/link/domain/event"
+	"shortlink/internal/link/domain/link"
+)
+
+// FetchLinkMetadataListener 短链接创建后获取跳转目标的标题和图标
+type FetchLinkMetadataListener struct {
+	app app.Application
+}
+
+func NewFetchLinkMetadataListener(app app.Application) FetchLinkMetadataListener {
+	return FetchLinkMetadataListener{app: app}
+}
+
+func (l FetchLinkMetadataListener) Process(ctx context.Context, e string) error {
+	var created event.LinkCreatedEvent
+	if err := sonic.UnmarshalString(e, &created); err != nil {
+		return err
+	}
+	c := created.Created
+	return l.app.Commands.FetchLinkMetadata.Handle(ctx, command.FetchLinkMetadata{
+		Identifier:  link.Identifier{Gid: c.Gid, Domain: c.Domain, ShortUri: c.ShortUri},
+		OriginalUrl: c.OriginalUrl,
+	})
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/base/types"
+	"time"
+)
+
+// linkCursorKind 短链接列表的游标标识
+const linkCursorKind = "link"
+
+type cursorPageLinkHandler struct {
+	readModel CursorPageLinkReadModel
+	codec     types.CursorCodec
+}
+
+type CursorPageLinkHandler decorator.QueryHandler[CursorPageLink, *types.CursorResp[Link]]
+
+func NewCursorPageLinkHandler(
+	readModel CursorPageLinkReadModel,
+	codec types.CursorCodec,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) CursorPageLinkHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[CursorPageLink, *types.CursorResp[Link]](
+		cursorPageLinkHandler{readModel: readModel, codec: codec},
+		logger,
+		metricsClient,
+	)
+}
+
+// CursorPageLink 按游标分页查询短链接，固定按创建时间倒序
+type CursorPageLink struct {
+	// 游标分页请求
+	types.CursorReq
+	// 过滤条件
+	LinkFilter
+}
+
+type CursorPageLinkReadModel interface {
+	// CursorPageLink 查询排在 after 之后的 limit 条短链接，after 为 nil 时从头开始
+	CursorPageLink(ctx context.Context, filter LinkFilter, after *types.Cursor, limit int) ([]Link, error)
+}
+
+func (h cursorPageLinkHandler) Handle(ctx context.Context, param CursorPageLink) (*types.CursorResp[Link], error) {
+	filter, err := param.LinkFilter.normalize()
+	if err != nil {
+		return nil, err
+	}
+	if err = filter.checkApiKeyGroup(ctx); err != nil {
+		return nil, err
+	}
+	after, err := h.codec.Decode(linkCursorKind, param.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 多查询一条用于判断是否还有下一页
+	records, err := h.readModel.CursorPageLink(ctx, filter, after, param.Limit()+1)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewCursorResp(h.codec, linkCursorKind, records, param.Limit(), func(l Link) (time.Time, int64) {
+		return time.Time(l.CreateTime), int64(l.ID)
+	})
+}
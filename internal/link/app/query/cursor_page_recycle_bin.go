@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/base/types"
+	"time"
+)
+
+// recycleBinCursorKind 回收站列表的游标标识
+const recycleBinCursorKind = "recycle_bin"
+
+type cursorPageRecycleBinHandler struct {
+	readModel CursorPageRecycleBinReadModel
+	codec     types.CursorCodec
+}
+
+type CursorPageRecycleBinHandler decorator.QueryHandler[CursorPageRecycleBin, *types.CursorResp[Link]]
+
+func NewCursorPageRecycleBinHandler(
+	readModel CursorPageRecycleBinReadModel,
+	codec types.CursorCodec,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) CursorPageRecycleBinHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[CursorPageRecycleBin, *types.CursorResp[Link]](
+		cursorPageRecycleBinHandler{readModel: readModel, codec: codec},
+		logger,
+		metricsClient,
+	)
+}
+
+// CursorPageRecycleBin 按游标分页查询回收站，固定按移入回收站的时间倒序
+type CursorPageRecycleBin struct {
+	types.CursorReq
+	Gids []string
+}
+
+type CursorPageRecycleBinReadModel interface {
+	// CursorPageRecycleBin 查询排在 after 之后的 limit 条短链接，after 为 nil 时从头开始
+	CursorPageRecycleBin(ctx context.Context, gids []string, after *types.Cursor, limit int) ([]Link, error)
+}
+
+func (h cursorPageRecycleBinHandler) Handle(ctx context.Context, param CursorPageRecycleBin) (*types.CursorResp[Link], error) {
+	after, err := h.codec.Decode(recycleBinCursorKind, param.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := h.readModel.CursorPageRecycleBin(ctx, param.Gids, after, param.Limit()+1)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewCursorResp(h.codec, recycleBinCursorKind, records, param.Limit(), func(l Link) (time.Time, int64) {
+		return time.Time(l.UpdateTime), int64(l.ID)
+	})
+}
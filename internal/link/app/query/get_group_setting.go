@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+)
+
+type getGroupSettingHandler struct {
+	readModel GetGroupSettingReadModel
+}
+
+type GetGroupSetting struct {
+	Gid string
+}
+
+type GetGroupSettingHandler decorator.QueryHandler[GetGroupSetting, GroupSetting]
+
+func NewGetGroupSettingHandler(
+	readModel GetGroupSettingReadModel,
+	logger *slog.Logger,
+	metrics metrics.Client,
+) GetGroupSettingHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[GetGroupSetting, GroupSetting](
+		getGroupSettingHandler{readModel: readModel},
+		logger,
+		metrics,
+	)
+}
+
+type GetGroupSettingReadModel interface {
+	GetGroupSetting(ctx context.Context, gid string) (GroupSetting, error)
+}
+
+func (h getGroupSettingHandler) Handle(ctx context.Context, q GetGroupSetting) (GroupSetting, error) {
+	return h.readModel.GetGroupSetting(ctx, q.Gid)
+}
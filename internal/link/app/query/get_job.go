@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+)
+
+type getJobHandler struct {
+	readModel GetJobReadModel
+}
+
+// GetJob 查询当前用户创建的后台任务
+type GetJob struct {
+	Id string
+}
+
+type GetJobHandler decorator.QueryHandler[GetJob, Job]
+
+func NewGetJobHandler(
+	readModel GetJobReadModel,
+	logger *slog.Logger,
+	metrics metrics.Client,
+) GetJobHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[GetJob, Job](
+		getJobHandler{readModel: readModel},
+		logger,
+		metrics,
+	)
+}
+
+type GetJobReadModel interface {
+	GetJob(ctx context.Context, id string) (Job, error)
+}
+
+func (h getJobHandler) Handle(ctx context.Context, q GetJob) (Job, error) {
+	job, err := h.readModel.GetJob(ctx, q.Id)
+	if err != nil {
+		return Job{}, err
+	}
+	// 不属于当前用户的任务视为不存在
+	if username, _ := ctx.Value("username").(string); job.Owner != username {
+		return Job{}, errno.LinkJobNotExists
+	}
+	return job, nil
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+)
+
+type getJobResultHandler struct {
+	readModel   GetJobReadModel
+	resultStore JobResultReader
+}
+
+// GetJobResult 下载后台任务的结果文件
+type GetJobResult struct {
+	Id string
+}
+
+type GetJobResultHandler decorator.QueryHandler[GetJobResult, JobResult]
+
+func NewGetJobResultHandler(
+	readModel GetJobReadModel,
+	resultStore JobResultReader,
+	logger *slog.Logger,
+	metrics metrics.Client,
+) GetJobResultHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+	if resultStore == nil {
+		panic("nil resultStore")
+	}
+
+	return decorator.ApplyQueryDecorators[GetJobResult, JobResult](
+		getJobResultHandler{readModel: readModel, resultStore: resultStore},
+		logger,
+		metrics,
+	)
+}
+
+// JobResultReader 读取任务的结果文件
+type JobResultReader interface {
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+func (h getJobResultHandler) Handle(ctx context.Context, q GetJobResult) (JobResult, error) {
+	job, err := getJobHandler{readModel: h.readModel}.Handle(ctx, GetJob{Id: q.Id})
+	if err != nil {
+		return JobResult{}, err
+	}
+	if !job.HasResult {
+		return JobResult{}, errno.LinkJobNotFinished
+	}
+
+	body, err := h.resultStore.Open(ctx, job.ResultFile)
+	if err != nil {
+		return JobResult{}, err
+	}
+	return JobResult{Name: job.ResultFile, Body: body}, nil
+}
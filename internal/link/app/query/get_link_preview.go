@@ -0,0 +1,75 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain/link"
+)
+
+type getLinkPreviewHandler struct {
+	readModel GetLinkPreviewReadModel
+}
+
+type GetLinkPreview struct {
+	// 访问的自定义域名 为空表示默认域名
+	Domain   string
+	ShortUri string
+}
+
+type GetLinkPreviewHandler decorator.QueryHandler[GetLinkPreview, LinkPreview]
+
+func NewGetLinkPreviewHandler(
+	readModel GetLinkPreviewReadModel,
+	logger *slog.Logger,
+	metrics metrics.Client,
+) GetLinkPreviewHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[GetLinkPreview, LinkPreview](
+		getLinkPreviewHandler{readModel: readModel},
+		logger,
+		metrics,
+	)
+}
+
+type GetLinkPreviewReadModel interface {
+	GetLink(ctx context.Context, domain, shortUri string) (*link.Link, error)
+}
+
+// Handle 预览只展示原始链接，不经过路由规则和分流，也不计入访问统计
+func (h getLinkPreviewHandler) Handle(ctx context.Context, q GetLinkPreview) (res LinkPreview, err error) {
+	var lk *link.Link
+	if lk, err = h.readModel.GetLink(ctx, q.Domain, q.ShortUri); err != nil {
+		return
+	}
+
+	switch lk.Status() {
+	case link.StatusActive, link.StatusPending:
+	case link.StatusExpired:
+		return res, errno.LinkExpired
+	case link.StatusForbidden:
+		return res, errno.LinkForbidden
+	case link.StatusReserved:
+		return res, errno.LinkReserved
+	default:
+		return res, errno.LinkNotExists
+	}
+
+	// 受密码保护的短链接不能通过预览绕过密码
+	if lk.Protected() {
+		return res, errno.LinkPasswordRequired
+	}
+
+	return LinkPreview{
+		ShortUri:     lk.ShortUri(),
+		FullShortUrl: lk.FullShortUrl(),
+		OriginalUrl:  lk.OriginalUrl(),
+		Title:        lk.Desc(),
+		Favicon:      lk.Favicon(),
+	}, nil
+}
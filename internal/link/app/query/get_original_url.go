@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/url"
 	"reflect"
 	"shortlink/internal/base/base_event"
 	"shortlink/internal/base/cache"
@@ -17,50 +18,84 @@ import (
 
 type getOriginalUrlHandler struct {
 	readModel        GetOriginalUrlReadModel
+	visitQuota       VisitQuota
+	countryResolver  CountryResolver
 	eventBus         base_event.EventBus
 	distributedCache cache.DistributedCache
+	whitelist        link.Whitelist
 }
 
-type GetOriginalUrlHandler decorator.QueryHandler[GetOriginalUrl, string]
+type GetOriginalUrlHandler decorator.QueryHandler[GetOriginalUrl, Redirection]
 
 func NewGetOriginalUrlHandler(
 	readModel GetOriginalUrlReadModel,
+	visitQuota VisitQuota,
+	countryResolver CountryResolver,
 	eventBus base_event.EventBus,
 	distributedCache cache.DistributedCache,
+	whitelist link.Whitelist,
 	logger *slog.Logger,
 	metrics metrics.Client,
 ) GetOriginalUrlHandler {
 	if readModel == nil {
 		panic("nil readModel")
 	}
+	if visitQuota == nil {
+		panic("nil visitQuota")
+	}
+	if countryResolver == nil {
+		panic("nil countryResolver")
+	}
 
-	return decorator.ApplyQueryDecorators[GetOriginalUrl, string](
-		getOriginalUrlHandler{readModel: readModel, eventBus: eventBus, distributedCache: distributedCache},
+	return decorator.ApplyQueryDecorators[GetOriginalUrl, Redirection](
+		getOriginalUrlHandler{
+			readModel:        readModel,
+			visitQuota:       visitQuota,
+			countryResolver:  countryResolver,
+			eventBus:         eventBus,
+			distributedCache: distributedCache,
+			whitelist:        whitelist,
+		},
 		logger,
 		metrics,
 	)
 }
 
 type GetOriginalUrl struct {
+	// 访问的自定义域名 为空表示默认域名
+	Domain        string
 	ShortUri      string
 	UserVisitInfo event.UserVisitInfo
+	// 访问者是否已通过密码解锁
+	Unlocked bool
+	// 访问者语言 按 Accept-Language 优先级排列
+	Languages []string
+	// 短链接上携带的查询参数 按透传策略合并到跳转目标
+	Query url.Values
 }
 
 type GetOriginalUrlReadModel interface {
-	GetLink(ctx context.Context, shortUri string) (*link.Link, error)
+	GetLink(ctx context.Context, domain, shortUri string) (*link.Link, error)
 }
 
-func (h getOriginalUrlHandler) Handle(ctx context.Context, q GetOriginalUrl) (res string, err error) {
+// VisitQuota 限制短链接的最大访问次数
+type VisitQuota interface {
+	// ConsumeVisit 原子地消耗一次访问次数，返回本次访问是否被允许
+	//
+	// 次数用完时负责将短链接置为过期并清除缓存
+	ConsumeVisit(ctx context.Context, domain, shortUri string, cacheValue *link.CacheValue) (bool, error)
+}
 
-	// $$ 发布事件 UserVisitEvent
-	e := event.NewUserVisitEvent(q.UserVisitInfo)
-	if err = h.eventBus.Publish(ctx, e); err != nil {
-		return "", err
-	}
+// CountryResolver 根据 IP 查询所属国家的 ISO 3166-1 两位代码，查询失败时返回空字符串
+type CountryResolver interface {
+	Country(ip string) string
+}
+
+func (h getOriginalUrlHandler) Handle(ctx context.Context, q GetOriginalUrl) (res Redirection, err error) {
 
 	fetchFn := func() (res interface{}, err error) {
 		lk := &link.Link{}
-		if lk, err = h.readModel.GetLink(ctx, q.ShortUri); err != nil || lk == nil {
+		if lk, err = h.readModel.GetLink(ctx, q.Domain, q.ShortUri); err != nil || lk == nil {
 			return
 		}
 		originalUrl := lk.OriginalUrl()
@@ -69,14 +104,9 @@ func (h getOriginalUrlHandler) Handle(ctx context.Context, q GetOriginalUrl) (re
 			return
 		}
 		switch lk.Status() {
-		case link.StatusActive:
+		case link.StatusActive, link.StatusPending, link.StatusExpired, link.StatusDisabled:
+			// 有效期在读取缓存后统一校验，过期和停用的短链接可能需要跳转到备用链接
 			res = link.NewCacheValue(lk)
-			//if _, err = res.Validate(); err != nil {
-			//	return nil, err
-			//}
-			return
-		case link.StatusExpired:
-			err = errno.LinkExpired
 			return
 		case link.StatusForbidden:
 			err = errno.LinkForbidden
@@ -90,16 +120,17 @@ func (h getOriginalUrlHandler) Handle(ctx context.Context, q GetOriginalUrl) (re
 		}
 	}
 
+	key := link.Key(q.Domain, q.ShortUri)
 	var result interface{}
 	result, err = h.distributedCache.SafeGetWithCacheCheckFilter(
 		ctx,
-		constant.GotoLinkKey+q.ShortUri,
+		constant.GotoLinkKey+key,
 		reflect.TypeOf(link.CacheValue{}),
 		fetchFn,
 		constant.NeverExpire,
 		cache.ShortUriCreateBloomFilter,
-		q.ShortUri,
-		constant.GotoIsNullLinkKey+q.ShortUri,
+		key,
+		constant.GotoIsNullLinkKey+key,
 	)
 	if err != nil {
 		if errors.Is(err, errno.RedisKeyNotExist) {
@@ -108,9 +139,86 @@ func (h getOriginalUrlHandler) Handle(ctx context.Context, q GetOriginalUrl) (re
 		return
 	}
 
-	if cacheValue, ok := result.(*link.CacheValue); ok {
-		res = cacheValue.OriginalUrl
+	cacheValue, ok := result.(*link.CacheValue)
+	if !ok {
+		return
+	}
+
+	// 校验状态和有效期，状态由定时任务流转，存在延迟，因此需要在跳转时再次校验
+	if _, err = cacheValue.Validate(); err != nil {
+		return h.fallback(cacheValue, err)
+	}
+
+	// 受密码保护的短链接需要先解锁，未解锁的访问不计入统计
+	if cacheValue.Protected && !q.Unlocked {
+		return res, errno.LinkPasswordRequired
+	}
+
+	// 限制访问次数的短链接，超出次数的访问直接拒绝
+	if cacheValue.MaxVisits > 0 {
+		var allowed bool
+		if allowed, err = h.visitQuota.ConsumeVisit(ctx, q.Domain, q.ShortUri, cacheValue); err != nil {
+			return res, err
+		}
+		if !allowed {
+			return h.fallback(cacheValue, errno.LinkVisitsExhausted)
+		}
+	}
+
+	// 选出跳转目标，命中的分流需要记录到访问信息中
+	target, variant := h.resolveTarget(q, cacheValue)
+
+	// 追加 UTM 参数并按透传策略合并查询参数
+	if target, err = cacheValue.UrlParams.Apply(target, q.Query); err != nil {
+		return res, err
+	}
+
+	// $$ 发布事件 UserVisitEvent
+	visitInfo := q.UserVisitInfo
+	visitInfo.Variant = variant
+	e := event.NewUserVisitEvent(visitInfo)
+	if err = h.eventBus.Publish(ctx, e); err != nil {
+		return res, err
+	}
+
+	return Redirection{
+		Url:          target,
+		StatusCode:   cacheValue.RedirectCode.StatusCode(),
+		Interstitial: !h.whitelist.Allows(target),
+	}, nil
+}
+
+// fallback 短链接不可用时跳转到备用链接，未设置备用链接时返回不可用的原因
+func (h getOriginalUrlHandler) fallback(cacheValue *link.CacheValue, reason error) (Redirection, error) {
+	target, ok := cacheValue.Fallback(reason)
+	if !ok {
+		return Redirection{}, reason
+	}
+	// 短链接的状态会发生变化，不能使用会被浏览器缓存的永久跳转
+	return Redirection{
+		Url:          target,
+		StatusCode:   link.RedirectFound.StatusCode(),
+		Interstitial: !h.whitelist.Allows(target),
+	}, nil
+}
+
+// resolveTarget 按条件路由规则和分流配置选出跳转目标
+func (h getOriginalUrlHandler) resolveTarget(q GetOriginalUrl, cacheValue *link.CacheValue) (string, string) {
+	if len(cacheValue.RoutingRules) == 0 && len(cacheValue.Variants) == 0 {
+		return cacheValue.OriginalUrl, ""
+	}
+
+	visitor := link.Visitor{
+		OS:        q.UserVisitInfo.OS,
+		Browser:   q.UserVisitInfo.Browser,
+		Device:    q.UserVisitInfo.Device,
+		Network:   q.UserVisitInfo.Network,
+		Languages: q.Languages,
+	}
+	if cacheValue.RoutingRules.NeedCountry() {
+		visitor.Country = h.countryResolver.Country(q.UserVisitInfo.RemoteAddr)
 	}
 
-	return
+	// 分流以 uv Cookie 作为访客标识，保证同一访客每次命中相同的目标
+	return cacheValue.Resolve(q.ShortUri, q.UserVisitInfo.UV, visitor)
 }
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/link/domain/link"
+)
+
+type listLinkIdsHandler struct {
+	readModel ListLinkIdsReadModel
+}
+
+// ListLinkIds 查询满足过滤条件的短链接标识，用于按条件批量操作
+type ListLinkIds struct {
+	LinkFilter
+	// 数量上限 超过时返回错误，不截断
+	Limit int
+}
+
+type ListLinkIdsHandler decorator.QueryHandler[ListLinkIds, []link.Identifier]
+
+func NewListLinkIdsHandler(
+	readModel ListLinkIdsReadModel,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) ListLinkIdsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[ListLinkIds, []link.Identifier](
+		listLinkIdsHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+type ListLinkIdsReadModel interface {
+	// ListLinkIds 最多返回 limit 个短链接
+	ListLinkIds(ctx context.Context, filter LinkFilter, limit int) ([]link.Identifier, error)
+}
+
+func (h listLinkIdsHandler) Handle(ctx context.Context, q ListLinkIds) ([]link.Identifier, error) {
+	// 分片版按分组定位分片，不支持跨分组筛选
+	if q.Gid == nil || *q.Gid == "" {
+		return nil, errno.LinkImportMissingGid
+	}
+	filter, err := q.LinkFilter.normalize()
+	if err != nil {
+		return nil, err
+	}
+	// 多查一条用于判断是否超过上限
+	ids, err := h.readModel.ListLinkIds(ctx, filter, q.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > q.Limit {
+		return nil, errno.LinkBulkTooMany
+	}
+	return ids, nil
+}
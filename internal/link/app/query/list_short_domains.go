@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+)
+
+type listShortDomainsHandler struct {
+	readModel ListShortDomainsReadModel
+}
+
+// ListShortDomains 查询当前用户注册的自定义域名
+type ListShortDomains struct{}
+
+type ListShortDomainsHandler decorator.QueryHandler[ListShortDomains, []ShortDomain]
+
+func NewListShortDomainsHandler(
+	readModel ListShortDomainsReadModel,
+	logger *slog.Logger,
+	metrics metrics.Client,
+) ListShortDomainsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[ListShortDomains, []ShortDomain](
+		listShortDomainsHandler{readModel: readModel},
+		logger,
+		metrics,
+	)
+}
+
+type ListShortDomainsReadModel interface {
+	ListShortDomains(ctx context.Context, owner string) ([]ShortDomain, error)
+}
+
+func (h listShortDomainsHandler) Handle(ctx context.Context, _ ListShortDomains) ([]ShortDomain, error) {
+	username, _ := ctx.Value("username").(string)
+	return h.readModel.ListShortDomains(ctx, username)
+}
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/base/types"
+)
+
+type pageBrokenLinkHandler struct {
+	readModel PageBrokenLinkReadModel
+}
+
+type PageBrokenLinkHandler decorator.QueryHandler[PageBrokenLink, *types.PageResp[Link]]
+
+func NewPageBrokenLinkHandler(
+	readModel PageBrokenLinkReadModel,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) PageBrokenLinkHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[PageBrokenLink, *types.PageResp[Link]](
+		pageBrokenLinkHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+// PageBrokenLink 分页查询最近一次健康检查中跳转目标失效的短链接
+type PageBrokenLink struct {
+	// 分页请求
+	types.PageReq
+	// 分组ID
+	Gid *string
+}
+
+type PageBrokenLinkReadModel interface {
+	PageBrokenLink(ctx context.Context, param PageBrokenLink) (*types.PageResp[Link], error)
+}
+
+func (h pageBrokenLinkHandler) Handle(ctx context.Context, param PageBrokenLink) (*types.PageResp[Link], error) {
+	return h.readModel.PageBrokenLink(ctx, param)
+}
@@ -3,9 +3,13 @@ package query
 import (
 	"context"
 	"log/slog"
+	"shortlink/internal/base/apikey"
 	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
 	"shortlink/internal/base/metrics"
 	"shortlink/internal/base/types"
+	"shortlink/internal/link/domain/link"
+	"time"
 )
 
 type pageLinkHandler struct {
@@ -33,18 +37,61 @@ func NewPageLinkHandler(
 type PageLink struct {
 	// 分页请求
 	types.PageReq
-	// 分组ID
-	Gid *string
+	// 过滤条件
+	LinkFilter
 	// 排序标识
 	// 取值为: todayPv, todayUv, todayUip, totalPv, totalUv, totalUip
 	// 默认为 create_time
 	OrderTag *string
 }
 
+// LinkFilter 分页查询短链接的过滤条件
+type LinkFilter struct {
+	// 分组ID
+	Gid *string
+	// 标签 需要同时包含全部标签
+	Tags link.Tags
+	// 状态
+	Status *link.Status
+	// 创建类型
+	CreateType *link.CreateType
+	// 创建时间范围 左闭右开
+	StartTime *time.Time
+	EndTime   *time.Time
+	// 关键词 匹配描述、原始链接和短链接
+	Keyword string
+}
+
+// normalize 校验时间范围，标签保存时统一为小写，查询时同样需要转换
+func (f LinkFilter) normalize() (LinkFilter, error) {
+	if f.StartTime != nil && f.EndTime != nil && f.EndTime.Before(*f.StartTime) {
+		return f, errno.LinkInvalidSearchTime
+	}
+	var err error
+	f.Tags, err = f.Tags.Normalize()
+	return f, err
+}
+
+// checkApiKeyGroup 限制了分组的 API Key 只能查询授权的分组，必须指定分组
+func (f LinkFilter) checkApiKeyGroup(ctx context.Context) error {
+	gid := ""
+	if f.Gid != nil {
+		gid = *f.Gid
+	}
+	return apikey.CheckGroup(ctx, gid)
+}
+
 type PageLinkReadModel interface {
 	PageLink(ctx context.Context, param PageLink) (*types.PageResp[Link], error)
 }
 
 func (h pageLinkHandler) Handle(ctx context.Context, param PageLink) (*types.PageResp[Link], error) {
+	var err error
+	if param.LinkFilter, err = param.LinkFilter.normalize(); err != nil {
+		return nil, err
+	}
+	if err = param.LinkFilter.checkApiKeyGroup(ctx); err != nil {
+		return nil, err
+	}
 	return h.readModel.PageLink(ctx, param)
 }
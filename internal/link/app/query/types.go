@@ -1,6 +1,7 @@
 package query
 
 import (
+	"io"
 	"shortlink/internal/base/types"
 	"shortlink/internal/link/domain/link"
 )
@@ -26,11 +27,101 @@ type Link struct {
 	EndDate      types.JsonTime  `json:"end_date,omitempty"`
 	Desc         string          `json:"desc,omitempty"`
 	Favicon      string          `json:"favicon,omitempty"`
-	ClickNum     int             `json:"click_num,omitempty"`
-	TotalPv      int             `json:"total_pv,omitempty"`
-	TotalUv      int             `json:"total_uv,omitempty"`
-	TotalUip     int             `json:"total_uip,omitempty"`
-	TodayPv      int             `json:"today_pv,omitempty"`
-	TodayUv      int             `json:"today_uv,omitempty"`
-	TodayUip     int             `json:"today_uip,omitempty"`
+	Tags         link.Tags       `json:"tags,omitempty"`
+	// 跳转目标页面的分享图片和描述
+	OgImage       string `json:"og_image,omitempty"`
+	OgDescription string `json:"og_description,omitempty"`
+	ClickNum      int    `json:"click_num,omitempty"`
+	TotalPv       int    `json:"total_pv,omitempty"`
+	TotalUv       int    `json:"total_uv,omitempty"`
+	TotalUip      int    `json:"total_uip,omitempty"`
+	TodayPv       int    `json:"today_pv,omitempty"`
+	TodayUv       int    `json:"today_uv,omitempty"`
+	TodayUip      int    `json:"today_uip,omitempty"`
+	// 跳转目标最近一次健康检查的结果
+	HealthStatus    int             `json:"health_status,omitempty"`
+	HealthLatency   int             `json:"health_latency,omitempty"`
+	HealthCheckTime *types.JsonTime `json:"health_check_time,omitempty"`
+	// 游标分页使用的排序键
+	CreateTime types.JsonTime `json:"create_time"`
+	UpdateTime types.JsonTime `json:"update_time"`
+}
+
+type GroupSetting struct {
+	// 分组ID
+	Gid string `json:"gid"`
+	// 默认的查询参数处理
+	UrlParams *link.UrlParams `json:"url_params"`
+	// 默认的备用链接
+	FallbackUrl string `json:"fallback_url"`
+}
+
+// ShortDomain 自定义域名
+type ShortDomain struct {
+	// 域名
+	Host string `json:"host"`
+	// 验证状态
+	Status link.DomainStatus `json:"status"`
+	// 是否通过 https 访问
+	UseTLS bool `json:"use_tls"`
+	// 验证令牌 设置为 VerifyRecord 的 TXT 记录值
+	VerifyToken string `json:"verify_token"`
+	// 用于验证的 DNS TXT 记录名
+	VerifyRecord string `json:"verify_record"`
+	// 最近一次验证时间
+	CheckedTime *types.JsonTime `json:"checked_time,omitempty"`
+	// 注册时间
+	CreateTime types.JsonTime `json:"create_time"`
+}
+
+// Redirection 短链接跳转结果
+type Redirection struct {
+	// 跳转目标
+	Url string
+	// 跳转状态码
+	StatusCode int
+	// 跳转目标不在白名单内，需要先展示警告页面
+	Interstitial bool
+}
+
+// LinkPreview 短链接预览信息
+type LinkPreview struct {
+	ShortUri     string
+	FullShortUrl string
+	OriginalUrl  string
+	Title        string
+	Favicon      string
+}
+
+// Job 后台任务
+type Job struct {
+	Id     string         `json:"id"`
+	Type   link.JobType   `json:"type"`
+	Status link.JobStatus `json:"status"`
+	DryRun bool           `json:"dry_run"`
+	// 进度
+	Total     int `json:"total"`
+	Processed int `json:"processed"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	// 错误行 最多 100 条，完整结果见结果文件
+	RowErrors link.RowErrors `json:"row_errors"`
+	// 是否可以下载结果文件
+	HasResult bool `json:"has_result"`
+	// 任务中断的原因
+	Message    string          `json:"message,omitempty"`
+	CreateTime types.JsonTime  `json:"create_time"`
+	FinishTime *types.JsonTime `json:"finish_time,omitempty"`
+	// 创建任务的用户
+	Owner string `json:"-"`
+	// 结果文件名
+	ResultFile string `json:"-"`
+}
+
+// JobResult 任务结果文件
+type JobResult struct {
+	// 文件名
+	Name string
+	// 文件内容 由调用方关闭
+	Body io.ReadCloser
 }
@@ -14,6 +14,22 @@ type Commands struct {
 	CreateLink      command.CreateLinkHandler
 	CreateLinkBatch command.CreateLinkBatchHandler
 	UpdateLink      command.UpdateLinkHandler
+	UnlockLink      command.UnlockLinkHandler
+	ImportLinks     command.ImportLinksHandler
+	ExportLinks     command.ExportLinksHandler
+	BulkLinks       command.BulkLinksHandler
+
+	FetchLinkMetadata command.FetchLinkMetadataHandler
+
+	UpdateGroupSetting command.UpdateGroupSettingHandler
+	RefreshLinkStatus  command.RefreshLinkStatusHandler
+	ScanLinkSafety     command.ScanLinkSafetyHandler
+	CheckLinkHealth    command.CheckLinkHealthHandler
+
+	RegisterShortDomain  command.RegisterShortDomainHandler
+	RemoveShortDomain    command.RemoveShortDomainHandler
+	VerifyShortDomain    command.VerifyShortDomainHandler
+	ReverifyShortDomains command.ReverifyShortDomainsHandler
 
 	SaveToRecycleBin      command.SaveToRecycleBinHandler
 	RemoveFromRecycleBin  command.RemoveFromRecycleBinHandler
@@ -22,8 +38,20 @@ type Commands struct {
 
 type Queries struct {
 	PageLink       query.PageLinkHandler
+	CursorPageLink query.CursorPageLinkHandler
+	PageBrokenLink query.PageBrokenLinkHandler
 	ListGroupCount query.ListGroupCountHandler
 	GetOriginalUrl query.GetOriginalUrlHandler
+	GetLinkPreview query.GetLinkPreviewHandler
+	ListLinkIds    query.ListLinkIdsHandler
+
+	GetGroupSetting query.GetGroupSettingHandler
+
+	GetJob       query.GetJobHandler
+	GetJobResult query.GetJobResultHandler
+
+	ListShortDomains query.ListShortDomainsHandler
 
-	PageRecycleBin query.PageRecycleBinHandler
+	PageRecycleBin       query.PageRecycleBinHandler
+	CursorPageRecycleBin query.CursorPageRecycleBinHandler
 }
@@ -19,7 +19,72 @@ type Config struct {
 		MaxAttempts       int      `mapstructure:"max_attempts"`
 		BaseRoutePrefix   string   `mapstructure:"base_route_prefix"`
 		MaxLinksPerGroup  int      `mapstructure:"max_links_per_group"`
-		Default           struct {
+		Alias             struct {
+			MinLength     int      `mapstructure:"min_length"`
+			MaxLength     int      `mapstructure:"max_length"`
+			ReservedWords []string `mapstructure:"reserved_words"`
+		} `mapstructure:"alias"`
+		ShortCode struct {
+			Generator   string `mapstructure:"generator"`
+			Length      int    `mapstructure:"length"`
+			Salt        string `mapstructure:"salt"`
+			SegmentStep int64  `mapstructure:"segment_step"`
+			NodeId      int64  `mapstructure:"node_id"`
+		} `mapstructure:"short_code"`
+		Protect struct {
+			CookieSecret  string `mapstructure:"cookie_secret"`
+			CookieTTL     int    `mapstructure:"cookie_ttl"`
+			MaxAttempts   int    `mapstructure:"max_attempts"`
+			AttemptWindow int    `mapstructure:"attempt_window"`
+		} `mapstructure:"protect"`
+		Redirect struct {
+			Preview      bool `mapstructure:"preview"`
+			Interstitial bool `mapstructure:"interstitial"`
+		} `mapstructure:"redirect"`
+		Safety struct {
+			BlocklistFile           string `mapstructure:"blocklist_file"`
+			BlocklistReloadInterval int    `mapstructure:"blocklist_reload_interval"`
+			MaxRedirectHops         int    `mapstructure:"max_redirect_hops"`
+			CheckTimeout            int    `mapstructure:"check_timeout"`
+			SafeBrowsing            struct {
+				Endpoint string   `mapstructure:"endpoint"`
+				ApiKey   string   `mapstructure:"api_key"`
+				Stub     []string `mapstructure:"stub"`
+			} `mapstructure:"safe_browsing"`
+		} `mapstructure:"safety"`
+		HealthCheck struct {
+			SamplePerGroup int  `mapstructure:"sample_per_group"`
+			Concurrency    int  `mapstructure:"concurrency"`
+			Timeout        int  `mapstructure:"timeout"`
+			Notify         bool `mapstructure:"notify"`
+		} `mapstructure:"health_check"`
+		Metadata struct {
+			Timeout  int   `mapstructure:"timeout"`
+			MaxBytes int64 `mapstructure:"max_bytes"`
+			CacheTTL int   `mapstructure:"cache_ttl"`
+		} `mapstructure:"metadata"`
+		GeoIp struct {
+			Timeout   int `mapstructure:"timeout"`
+			CacheSize int `mapstructure:"cache_size"`
+		} `mapstructure:"geo_ip"`
+		ShortDomain struct {
+			VerifyTimeout       int `mapstructure:"verify_timeout"`
+			RevokeAfterFailures int `mapstructure:"revoke_after_failures"`
+		} `mapstructure:"short_domain"`
+		Import struct {
+			MaxRows   int    `mapstructure:"max_rows"`
+			ResultDir string `mapstructure:"result_dir"`
+		} `mapstructure:"import"`
+		Export struct {
+			SyncMaxLinks int `mapstructure:"sync_max_links"`
+		} `mapstructure:"export"`
+		Schedule struct {
+			LinkStatusInterval   int `mapstructure:"link_status_interval"`
+			DomainVerifyInterval int `mapstructure:"domain_verify_interval"`
+			SafetyScanInterval   int `mapstructure:"safety_scan_interval"`
+			HealthCheckInterval  int `mapstructure:"health_check_interval"`
+		} `mapstructure:"schedule"`
+		Default struct {
 			Gid        string `mapstructure:"gid"`
 			Expiration int    `mapstructure:"expiration"`
 		} `mapstructure:"default"`
@@ -10,4 +10,8 @@ const (
 
 const (
 	UserVisitEvent = "user_visit_event"
+
+	LinkStatusChangedEvent = "link_status_changed_event"
+
+	LinkCreatedEvent = "link_created_event"
 )
@@ -30,10 +30,13 @@ const (
 	// DelayQueueStatKey 短链接延迟队列消费统计 Key
 	DelayQueueStatKey = "short-link:delay-queue:stats"
 
-	// LinkStatsUvKey 短链接统计判断是否新用户缓存标识
+	// LinkStatsUvKey 短链接统计判断是否新用户缓存标识，后接 link.Key(domain, shortUri)
 	LinkStatsUvKey = "short-link:stats:uv:"
 
-	// LinkStatsUipKey 短链接统计判断是否新 IP 缓存标识
+	// LinkStatsVariantUvKey 短链接分流统计判断是否新用户缓存标识，后接 link.Key(domain, shortUri):variant
+	LinkStatsVariantUvKey = "short-link:stats:variant-uv:"
+
+	// LinkStatsUipKey 短链接统计判断是否新 IP 缓存标识，后接 link.Key(domain, shortUri)
 	LinkStatsUipKey = "short-link:stats:uip:"
 
 	// LinkStatsStreamTopicKey 短链接监控消息保存队列 Topic 缓存标识
@@ -44,4 +47,16 @@ const (
 
 	// LinkCreateLockKey 创建短链接锁标识
 	LinkCreateLockKey = "short-link:lock:create:%s"
+
+	// LinkVisitCountKey 限制访问次数的短链接已访问次数 Key
+	LinkVisitCountKey = "short-link:visit-count:"
+
+	// LinkUnlockAttemptsKey 短链接密码错误次数 Key，参数为 shortUri 和 IP
+	LinkUnlockAttemptsKey = "short-link:unlock-attempts:%s:%s"
+
+	// LockScheduleJobKey 定时任务锁前缀 Key，多实例部署时同一时刻只有一个实例执行
+	LockScheduleJobKey = "short-link:lock:schedule:"
+
+	// ShortCodeSegmentKey 短链接计数器号段分配 Key
+	ShortCodeSegmentKey = "short-link:short-code:segment"
 )
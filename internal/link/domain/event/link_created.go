@@ -0,0 +1,50 @@
+package event
+
+import (
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/link/common/constant"
+)
+
+// LinkCreated 新建的短链接
+type LinkCreated struct {
+	// 分组ID
+	Gid string `json:"gid"`
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
+	// 短链接
+	ShortUri string `json:"shortUri"`
+	// 原始链接
+	OriginalUrl string `json:"originalUrl"`
+}
+
+// LinkCreatedEvent 短链接创建成功，用于异步获取跳转目标的标题和图标
+type LinkCreatedEvent struct {
+	base_event.CommonEvent
+	Created LinkCreated
+}
+
+func (e LinkCreatedEvent) Name() string {
+	return constant.LinkCreatedEvent
+}
+
+func (e LinkCreatedEvent) Topic() string {
+	return constant.AppShortLinkTopic
+}
+
+func (e LinkCreatedEvent) Tag() string {
+	return LinkCreatedTag
+}
+
+func (e LinkCreatedEvent) Keys() string {
+	return e.Created.ShortUri
+}
+
+// LinkCreatedTag 订阅短链接创建事件时使用
+const LinkCreatedTag = "link_created"
+
+func NewLinkCreatedEvent(created LinkCreated) LinkCreatedEvent {
+	return LinkCreatedEvent{
+		CommonEvent: base_event.NewCommonEvent(),
+		Created:     created,
+	}
+}
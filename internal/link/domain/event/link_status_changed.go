@@ -0,0 +1,50 @@
+package event
+
+import (
+	"shortlink/internal/base/base_event"
+	"shortlink/internal/link/common/constant"
+	"shortlink/internal/link/domain/link"
+)
+
+// LinkStatusChanged 短链接状态变化信息
+type LinkStatusChanged struct {
+	// 分组ID
+	Gid string `json:"gid"`
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
+	// 短链接
+	ShortUri string `json:"shortUri"`
+	// 原状态
+	From link.Status `json:"from"`
+	// 新状态
+	To link.Status `json:"to"`
+}
+
+// LinkStatusChangedEvent 短链接状态发生变化，如到达开始时间被激活、超过结束时间被置为过期、所属域名验证失败被停用
+type LinkStatusChangedEvent struct {
+	base_event.CommonEvent
+	Change LinkStatusChanged
+}
+
+func (e LinkStatusChangedEvent) Name() string {
+	return constant.LinkStatusChangedEvent
+}
+
+func (e LinkStatusChangedEvent) Topic() string {
+	return constant.AppShortLinkTopic
+}
+
+func (e LinkStatusChangedEvent) Tag() string {
+	return "link_status_changed"
+}
+
+func (e LinkStatusChangedEvent) Keys() string {
+	return e.Change.ShortUri
+}
+
+func NewLinkStatusChangedEvent(change LinkStatusChanged) LinkStatusChangedEvent {
+	return LinkStatusChangedEvent{
+		CommonEvent: base_event.NewCommonEvent(),
+		Change:      change,
+	}
+}
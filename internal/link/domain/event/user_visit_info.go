@@ -7,6 +7,8 @@ import (
 )
 
 type UserVisitInfo struct {
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
 	// 短链接
 	ShortUri string `json:"shortUri"`
 	// 访问用户IP
@@ -21,6 +23,8 @@ type UserVisitInfo struct {
 	Network string `json:"network"`
 	// UV
 	UV string `json:"uv"`
+	// 命中的分流名称 未参与分流时为空
+	Variant string `json:"variant,omitempty"`
 	// UV访问标识
 	UVFirstFlag bool `json:"uvFirstFlag"`
 	// UIP访问标识
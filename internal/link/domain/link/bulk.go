@@ -0,0 +1,135 @@
+package link
+
+import (
+	"shortlink/internal/base/errno"
+	"time"
+)
+
+// BulkOp 批量操作类型
+type BulkOp string
+
+const (
+	// BulkMove 移动到其他分组
+	BulkMove BulkOp = "move"
+	// BulkTag 追加标签
+	BulkTag BulkOp = "tag"
+	// BulkSetExpiry 修改有效期
+	BulkSetExpiry BulkOp = "set_expiry"
+	// BulkSetStatus 启用或停用
+	BulkSetStatus BulkOp = "set_status"
+	// BulkRecycle 移入回收站
+	BulkRecycle BulkOp = "recycle"
+)
+
+// BulkAction 对多个短链接执行的同一操作
+type BulkAction struct {
+	Op BulkOp
+	// 目标分组 移动时必填
+	Gid string
+	// 追加的标签 打标签时必填
+	Tags Tags
+	// 有效期类型 修改有效期时必填
+	ValidType *ValidType
+	// 有效期结束时间 自定义有效期时必填
+	EndDate *time.Time
+	// 目标状态 只能启用或停用
+	Status Status
+}
+
+// Validate 校验操作参数，返回标签统一为小写后的操作
+func (a BulkAction) Validate() (BulkAction, error) {
+	switch a.Op {
+	case BulkMove:
+		if a.Gid == "" {
+			return a, errno.LinkBulkInvalidAction
+		}
+	case BulkTag:
+		tags, err := a.Tags.Normalize()
+		if err != nil {
+			return a, err
+		}
+		if len(tags) == 0 {
+			return a, errno.LinkBulkInvalidAction
+		}
+		a.Tags = tags
+	case BulkSetExpiry:
+		if a.ValidType == nil {
+			return a, errno.LinkBulkInvalidAction
+		}
+		switch *a.ValidType {
+		case ValidTypePermanent:
+			a.EndDate = nil
+		case ValidTypeTemporary:
+			if a.EndDate == nil || !a.EndDate.After(time.Now()) {
+				return a, errno.LinkEndTimeBeforeStartTime
+			}
+		default:
+			return a, errno.LinkInvalidValidType
+		}
+	case BulkSetStatus:
+		if a.Status != StatusActive && a.Status != StatusDisabled {
+			return a, errno.LinkInvalidStatus
+		}
+	case BulkRecycle:
+	default:
+		return a, errno.LinkBulkInvalidAction
+	}
+	return a, nil
+}
+
+// Apply 修改短链接，移入回收站不修改实体，由仓库记录回收时间
+func (a BulkAction) Apply(lk *Link) error {
+	switch a.Op {
+	case BulkMove:
+		lk.gid = a.Gid
+	case BulkTag:
+		tags, err := append(append(Tags{}, lk.tags...), a.Tags...).Normalize()
+		if err != nil {
+			return err
+		}
+		lk.tags = tags
+	case BulkSetExpiry:
+		lk.validDate = &ValidDate{
+			validType: *a.ValidType,
+			startDate: lk.validDate.startDate,
+			endDate:   a.EndDate,
+		}
+		// 因到期而过期的短链接延长有效期后重新启用，访问次数用完的不受影响
+		if lk.status == StatusExpired && lk.maxVisits == 0 {
+			lk.status = lk.activeStatus()
+		}
+	case BulkSetStatus:
+		switch lk.status {
+		case StatusForbidden, StatusDeleted, StatusReserved:
+			// 被禁用的短链接需要修改跳转目标后才能启用
+			return errno.LinkInvalidStatus
+		case StatusExpired:
+			if a.Status == StatusActive {
+				return errno.LinkExpired
+			}
+		}
+		lk.status = a.Status
+		if a.Status == StatusActive {
+			lk.status = lk.activeStatus()
+		}
+	}
+	return nil
+}
+
+// activeStatus 启用后的状态 未到开始时间时为待生效
+func (lk *Link) activeStatus() Status {
+	if start := lk.validDate.startDate; start != nil && start.After(time.Now()) {
+		return StatusPending
+	}
+	return StatusActive
+}
+
+// BulkItemResult 批量操作中单个短链接的结果
+type BulkItemResult struct {
+	Domain   string
+	ShortUri string
+	// 操作前所在的分组 短链接不存在时为空
+	Gid string
+	// 失败原因 为 nil 表示成功
+	Err error
+}
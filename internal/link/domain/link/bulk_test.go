@@ -0,0 +1,78 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+	"time"
+)
+
+func TestBulkAction_Validate(t *testing.T) {
+	permanent, temporary, invalid := ValidTypePermanent, ValidTypeTemporary, ValidType(9)
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tests := []struct {
+		name    string
+		action  BulkAction
+		wantErr error
+	}{
+		{"move", BulkAction{Op: BulkMove, Gid: "g2"}, nil},
+		{"move without gid", BulkAction{Op: BulkMove}, errno.LinkBulkInvalidAction},
+		{"tag", BulkAction{Op: BulkTag, Tags: Tags{" Go "}}, nil},
+		{"tag without tags", BulkAction{Op: BulkTag}, errno.LinkBulkInvalidAction},
+		{"permanent", BulkAction{Op: BulkSetExpiry, ValidType: &permanent, EndDate: &past}, nil},
+		{"temporary in past", BulkAction{Op: BulkSetExpiry, ValidType: &temporary, EndDate: &past}, errno.LinkEndTimeBeforeStartTime},
+		{"temporary", BulkAction{Op: BulkSetExpiry, ValidType: &temporary, EndDate: &future}, nil},
+		{"invalid valid type", BulkAction{Op: BulkSetExpiry, ValidType: &invalid}, errno.LinkInvalidValidType},
+		{"disable", BulkAction{Op: BulkSetStatus, Status: StatusDisabled}, nil},
+		{"forbid", BulkAction{Op: BulkSetStatus, Status: StatusForbidden}, errno.LinkInvalidStatus},
+		{"recycle", BulkAction{Op: BulkRecycle}, nil},
+		{"unknown", BulkAction{Op: "delete"}, errno.LinkBulkInvalidAction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.action.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBulkAction_Apply(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	temporary := ValidTypeTemporary
+	newLink := func(status Status, start time.Time) *Link {
+		return &Link{gid: "g1", status: status, tags: Tags{"a"},
+			validDate: &ValidDate{validType: ValidTypeTemporary, startDate: &start, endDate: &past}}
+	}
+
+	tests := []struct {
+		name       string
+		lk         *Link
+		action     BulkAction
+		wantErr    error
+		wantStatus Status
+	}{
+		{"extend expired", newLink(StatusExpired, past), BulkAction{Op: BulkSetExpiry, ValidType: &temporary, EndDate: &future}, nil, StatusActive},
+		{"enable before start", newLink(StatusDisabled, future), BulkAction{Op: BulkSetStatus, Status: StatusActive}, nil, StatusPending},
+		{"enable forbidden", newLink(StatusForbidden, past), BulkAction{Op: BulkSetStatus, Status: StatusActive}, errno.LinkInvalidStatus, StatusForbidden},
+		{"enable expired", newLink(StatusExpired, past), BulkAction{Op: BulkSetStatus, Status: StatusActive}, errno.LinkExpired, StatusExpired},
+		{"disable expired", newLink(StatusExpired, past), BulkAction{Op: BulkSetStatus, Status: StatusDisabled}, nil, StatusDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.action.Apply(tt.lk); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.lk.Status() != tt.wantStatus {
+				t.Errorf("status = %s, want %s", tt.lk.Status(), tt.wantStatus)
+			}
+		})
+	}
+
+	lk := newLink(StatusActive, past)
+	_ = BulkAction{Op: BulkTag, Tags: Tags{"b", "a"}}.Apply(lk)
+	_ = BulkAction{Op: BulkMove, Gid: "g2"}.Apply(lk)
+	if len(lk.Tags()) != 2 || lk.Gid() != "g2" {
+		t.Errorf("tags = %v, gid = %s", lk.Tags(), lk.Gid())
+	}
+}
@@ -9,6 +9,7 @@ type Status string
 
 const (
 	StatusActive    Status = "active"    // 激活状态
+	StatusPending   Status = "pending"   // 未到开始时间
 	StatusExpired   Status = "expired"   // 已过期
 	StatusDisabled  Status = "disabled"  // 已停用
 	StatusForbidden Status = "forbidden" // 已禁用
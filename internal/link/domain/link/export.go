@@ -0,0 +1,273 @@
+package link
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"shortlink/internal/base/errno"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat 导出文件格式
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ParseExportFormat 解析导出文件格式 为空时使用 csv
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch f := ExportFormat(strings.ToLower(strings.TrimSpace(format))); f {
+	case "":
+		return ExportCSV, nil
+	case ExportCSV, ExportJSON, ExportXLSX:
+		return f, nil
+	}
+	return "", errno.LinkExportFormat
+}
+
+// Ext 文件扩展名
+func (f ExportFormat) Ext() string {
+	return "." + string(f)
+}
+
+// ExportRow 导出的一条短链接 包含截至导出时的访问统计
+type ExportRow struct {
+	ShortUri     string     `json:"short_uri"`
+	FullShortUrl string     `json:"full_short_url"`
+	OriginalUrl  string     `json:"original_url"`
+	Gid          string     `json:"gid"`
+	Status       Status     `json:"status"`
+	Desc         string     `json:"desc"`
+	Tags         Tags       `json:"tags"`
+	ValidType    int        `json:"valid_type"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	CreateTime   time.Time  `json:"create_time"`
+	TotalPv      int        `json:"total_pv"`
+	TotalUv      int        `json:"total_uv"`
+	TotalUip     int        `json:"total_uip"`
+	TodayPv      int        `json:"today_pv"`
+	TodayUv      int        `json:"today_uv"`
+	TodayUip     int        `json:"today_uip"`
+}
+
+// exportColumns CSV 和 XLSX 的表头 与 ExportRow.values 的顺序一致
+var exportColumns = []string{
+	"short_uri", "full_short_url", "original_url", "gid", "status", "desc", "tags",
+	"valid_type", "start_date", "end_date", "create_time",
+	"total_pv", "total_uv", "total_uip", "today_pv", "today_uv", "today_uip",
+}
+
+func (r ExportRow) values() []string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.DateTime)
+	}
+	return []string{
+		r.ShortUri, r.FullShortUrl, r.OriginalUrl, r.Gid, string(r.Status), r.Desc, strings.Join(r.Tags, ","),
+		strconv.Itoa(r.ValidType), formatTime(r.StartDate), formatTime(r.EndDate), formatTime(&r.CreateTime),
+		strconv.Itoa(r.TotalPv), strconv.Itoa(r.TotalUv), strconv.Itoa(r.TotalUip),
+		strconv.Itoa(r.TodayPv), strconv.Itoa(r.TodayUv), strconv.Itoa(r.TodayUip),
+	}
+}
+
+// ExportWriter 逐批写入导出文件，写完后需要调用 Close 补全文件结尾
+type ExportWriter interface {
+	Write(rows []ExportRow) error
+	Close() error
+}
+
+// NewExportWriter 创建导出文件写入器，Close 不会关闭 w
+func NewExportWriter(format ExportFormat, w io.Writer) (ExportWriter, error) {
+	switch format {
+	case ExportCSV:
+		return newCsvExportWriter(w)
+	case ExportJSON:
+		return &jsonExportWriter{w: bufio.NewWriter(w)}, nil
+	case ExportXLSX:
+		return newXlsxExportWriter(w)
+	}
+	return nil, errno.LinkExportFormat
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCsvExportWriter(w io.Writer) (*csvExportWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: cw}, nil
+}
+
+func (e *csvExportWriter) Write(rows []ExportRow) error {
+	for _, row := range rows {
+		if err := e.w.Write(row.values()); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExportWriter 输出 JSON 数组，逐条编码避免整体加载到内存
+type jsonExportWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (e *jsonExportWriter) Write(rows []ExportRow) error {
+	for _, row := range rows {
+		sep := ",\n"
+		if e.count == 0 {
+			sep = "[\n"
+		}
+		if _, err := e.w.WriteString(sep); err != nil {
+			return err
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if _, err = e.w.Write(data); err != nil {
+			return err
+		}
+		e.count++
+	}
+	return e.w.Flush()
+}
+
+func (e *jsonExportWriter) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := e.w.WriteString(end); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// xlsxExportWriter 只包含一个工作表的最小 XLSX 文件
+//
+// 单元格使用内联字符串，不需要共享字符串表，工作表可以边查询边写入
+type xlsxExportWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	// 下一行的行号 第 1 行为表头
+	row int
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="links" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxNumberColumns 以数字写入的列 从 total_pv 开始
+var xlsxNumberColumns = len(exportColumns) - 6
+
+func newXlsxExportWriter(w io.Writer) (*xlsxExportWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+	// zip 同一时间只能写一个文件，工作表放在最后
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	e := &xlsxExportWriter{zw: zw, sheet: bufio.NewWriter(f), row: 1}
+	if _, err = e.sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, err
+	}
+	if err = e.writeRow(exportColumns, len(exportColumns)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *xlsxExportWriter) Write(rows []ExportRow) error {
+	for _, row := range rows {
+		if err := e.writeRow(row.values(), xlsxNumberColumns); err != nil {
+			return err
+		}
+	}
+	return e.sheet.Flush()
+}
+
+// writeRow 写入一行，下标从 numberFrom 开始的列写为数字
+func (e *xlsxExportWriter) writeRow(values []string, numberFrom int) error {
+	row := strconv.Itoa(e.row)
+	e.sheet.WriteString(`<row r="` + row + `">`)
+	for idx, v := range values {
+		ref := xlsxColumnName(idx) + row
+		if idx >= numberFrom {
+			e.sheet.WriteString(`<c r="` + ref + `"><v>` + v + `</v></c>`)
+			continue
+		}
+		e.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t>`)
+		if err := xml.EscapeText(e.sheet, []byte(v)); err != nil {
+			return err
+		}
+		e.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := e.sheet.WriteString(`</row>`)
+	e.row++
+	return err
+}
+
+func (e *xlsxExportWriter) Close() error {
+	if _, err := e.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	return e.zw.Close()
+}
+
+// xlsxColumnName 列下标转为 A、B、...、Z、AA
+func xlsxColumnName(idx int) string {
+	name := ""
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('A'+(idx-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,97 @@
+package link
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportWriter(t *testing.T) {
+	rows := []ExportRow{
+		{ShortUri: "abc", OriginalUrl: "https://a.com/?x=1&y=<2>", Gid: "g1", Status: StatusActive, Tags: Tags{"go", "营销"},
+			CreateTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local), TotalPv: 10, TotalUv: 5},
+		{ShortUri: "def", OriginalUrl: "https://b.com", Desc: "with, comma", TodayPv: 1},
+	}
+
+	tests := []struct {
+		format ExportFormat
+		check  func(t *testing.T, data []byte)
+	}{
+		{ExportCSV, func(t *testing.T, data []byte) {
+			records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 3 || records[1][6] != "go,营销" || records[2][5] != "with, comma" || records[1][11] != "10" {
+				t.Errorf("unexpected csv %v", records)
+			}
+		}},
+		{ExportJSON, func(t *testing.T, data []byte) {
+			var got []ExportRow
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 2 || got[0].TotalPv != 10 || got[1].Desc != "with, comma" {
+				t.Errorf("unexpected json %+v", got)
+			}
+		}},
+		{ExportXLSX, func(t *testing.T, data []byte) {
+			zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sheet string
+			for _, f := range zr.File {
+				if f.Name == "xl/worksheets/sheet1.xml" {
+					rc, _ := f.Open()
+					b, _ := io.ReadAll(rc)
+					sheet = string(b)
+				}
+			}
+			for _, want := range []string{`<row r="3">`, `<c r="L2"><v>10</v></c>`, "x=1&amp;y=&lt;2&gt;", "</worksheet>"} {
+				if !strings.Contains(sheet, want) {
+					t.Errorf("sheet missing %q", want)
+				}
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewExportWriter(tt.format, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 分两批写入
+			if err = w.Write(rows[:1]); err != nil {
+				t.Fatal(err)
+			}
+			if err = w.Write(rows[1:]); err != nil {
+				t.Fatal(err)
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, buf.Bytes())
+		})
+	}
+}
+
+func TestExportWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewExportWriter(ExportJSON, &buf)
+	if err := w.Close(); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty json export = %q, %v", buf.String(), err)
+	}
+	if _, err := ParseExportFormat("pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if name := xlsxColumnName(27); name != "AB" {
+		t.Errorf("xlsxColumnName(27) = %s, want AB", name)
+	}
+}
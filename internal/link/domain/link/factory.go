@@ -3,24 +3,36 @@ package link
 import (
 	"errors"
 	"fmt"
-	"github.com/google/uuid"
+	"regexp"
 	"shortlink/internal/base/errno"
 	"shortlink/internal/base/toolkit"
 	"strings"
 	"time"
 )
 
+// aliasPattern 自定义短链接允许的字符：字母、数字、下划线和中划线
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// builtinReservedWords 与系统路由冲突的保留字，无论配置如何都不允许作为自定义短链接
+var builtinReservedWords = []string{"api", "page", "metrics", "get-title", "favicon.ico"}
+
 type FactoryConfig struct {
 	Domain            string
 	UseSSL            bool
 	Whitelist         []string
-	MaxAttempts       int // 生成唯一短链接的最大尝试次数
+	Interstitial      bool // 白名单外的跳转目标允许创建，跳转时展示警告页面
+	MaxAttempts       int  // 生成唯一短链接的最大尝试次数
 	MaxLinksPerGroup  int
 	DefaultFavicon    string
 	DefaultGid        string
 	DefaultExpiration int
 	DefaultCreateType CreateType
 	DefaultValidType  ValidType
+	AliasMinLength    int      // 自定义短链接最小长度
+	AliasMaxLength    int      // 自定义短链接最大长度
+	ReservedWords     []string // 自定义短链接保留字
+	// 短链接生成策略 为空时使用 HashGenerator
+	Generator ShortCodeGenerator
 }
 
 // Validate 这个方法会在配置初始化的时候被调用
@@ -70,6 +82,18 @@ func (f FactoryConfig) validate() error {
 			errors.New("default valid type should be 0 or 1, but is "+fmt.Sprint(f.DefaultValidType)),
 		)
 	}
+	if f.AliasMinLength < 1 {
+		err = errors.Join(
+			err,
+			errors.New("alias min length should be at least 1, but is "+fmt.Sprint(f.AliasMinLength)),
+		)
+	}
+	if f.AliasMaxLength < f.AliasMinLength {
+		err = errors.Join(
+			err,
+			errors.New("alias max length should not be less than min length, but is "+fmt.Sprint(f.AliasMaxLength)),
+		)
+	}
 
 	return err
 }
@@ -84,9 +108,37 @@ func NewFactory(fc FactoryConfig) (*Factory, error) {
 		return &Factory{}, errors.Join(err, errors.New("invalid config passed to link factory"))
 	}
 
+	if fc.Generator == nil {
+		fc.Generator = NewHashGenerator()
+	}
+
 	return &Factory{fc: fc}, nil
 }
 
+// CreateOptions 创建短链接时的可选配置，零值表示不启用对应功能
+type CreateOptions struct {
+	// 自定义短链接 为空时随机生成
+	Alias string
+	// 访问密码（明文）
+	Password string
+	// 最大访问次数 0 表示不限制
+	MaxVisits int
+	// 条件路由规则
+	RoutingRules RoutingRules
+	// 按权重分流的跳转目标
+	Variants Variants
+	// 跳转时对查询参数的处理 为 nil 时丢弃短链接上的查询参数
+	UrlParams *UrlParams
+	// 跳转状态码 0 表示使用 302
+	RedirectCode RedirectCode
+	// 备用链接 过期、停用或访问次数用完时跳转
+	FallbackUrl string
+	// 自定义域名 为 nil 时使用默认域名
+	Domain *ShortDomain
+	// 标签
+	Tags Tags
+}
+
 func (f Factory) NewAvailableLink(
 	originalUrl string,
 	gid string,
@@ -95,6 +147,7 @@ func (f Factory) NewAvailableLink(
 	startDate *time.Time,
 	endDate *time.Time,
 	desc string,
+	opts CreateOptions,
 	ifExistsFunc func(string) (bool, error),
 ) (lk *Link, err error) {
 
@@ -103,32 +156,77 @@ func (f Factory) NewAvailableLink(
 		return nil, err
 	}
 
-	// 短链接
-	var shortUri string
-	if shortUri, err = f.genUniqueShortUri(originalUrl, f.fc.MaxAttempts, ifExistsFunc); err != nil {
+	// 最大访问次数
+	if opts.MaxVisits < 0 {
+		return nil, errno.LinkInvalidMaxVisits
+	}
+
+	// 路由规则 跳转目标同样需要校验白名单
+	if err = f.verifyRoutingRules(opts.RoutingRules); err != nil {
 		return nil, err
 	}
 
-	// 完整短链接
-	var fullShortUrl string
-	if f.fc.UseSSL {
-		fullShortUrl = fmt.Sprintf("https://%s/%s", f.fc.Domain, shortUri)
-	} else {
-		fullShortUrl = fmt.Sprintf("http://%s/%s", f.fc.Domain, shortUri)
+	// 分流目标 同样需要校验白名单
+	if err = f.verifyVariants(opts.Variants); err != nil {
+		return nil, err
+	}
+
+	// 查询参数处理
+	if err = opts.UrlParams.validate(); err != nil {
+		return nil, err
 	}
 
-	// 标题和图标
-	title, favicon, err := toolkit.GetTitleAndFavicon(originalUrl)
-	if err == nil {
-		// 标题可以为空 但图标有默认值
-		if desc == "" {
-			desc = title
+	// 跳转状态码
+	if !opts.RedirectCode.valid() {
+		return nil, errno.LinkInvalidRedirectCode
+	}
+
+	// 备用链接 同样需要校验白名单
+	if opts.FallbackUrl != "" {
+		if err = validateFallbackUrl(opts.FallbackUrl); err != nil {
+			return nil, err
+		}
+		if err = f.verifyWhiteList(opts.FallbackUrl); err != nil {
+			return nil, err
 		}
-		if favicon == "" {
-			favicon = f.fc.DefaultFavicon
+	}
+
+	// 标签
+	var tags Tags
+	if tags, err = opts.Tags.Normalize(); err != nil {
+		return nil, err
+	}
+
+	// 自定义域名
+	var domain string
+	if opts.Domain != nil {
+		if !opts.Domain.Usable() {
+			return nil, errno.LinkDomainUnavailable
 		}
+		domain = opts.Domain.Host()
 	}
 
+	// 短链接 指定了自定义短链接时直接使用，否则随机生成
+	var shortUri string
+	if opts.Alias != "" {
+		if shortUri, err = f.useAlias(opts.Alias, ifExistsFunc); err != nil {
+			return nil, err
+		}
+	} else {
+		if shortUri, err = f.genUniqueShortUri(originalUrl, f.fc.MaxAttempts, ifExistsFunc); err != nil {
+			return nil, err
+		}
+	}
+
+	// 完整短链接
+	fullShortUrl := shortUrl(f.fc.Domain, f.fc.UseSSL, shortUri)
+	if opts.Domain != nil {
+		fullShortUrl = shortUrl(opts.Domain.Host(), opts.Domain.UseTLS(), shortUri)
+	}
+
+	// 标题和图标 创建后异步获取，先使用默认图标
+	favicon := f.fc.DefaultFavicon
+
 	// 赋予默认值
 	if createType == nil {
 		createType = &f.fc.DefaultCreateType
@@ -151,42 +249,69 @@ func (f Factory) NewAvailableLink(
 		return nil, err
 	}
 
+	// 未到开始时间的短链接由定时任务激活
+	status := StatusActive
+	if startDate.After(time.Now()) {
+		status = StatusPending
+	}
+
+	// 访问密码
+	var hashedPassword string
+	if hashedPassword, err = hashPassword(opts.Password); err != nil {
+		return nil, err
+	}
+
 	return &Link{
-		domain:       f.fc.Domain,
+		domain:       domain,
 		shortUri:     shortUri,
 		fullShortUrl: fullShortUrl,
 		originalUrl:  originalUrl,
 		gid:          gid,
-		status:       StatusActive,
+		status:       status,
 		createType:   *createType,
 		validDate:    validDate,
 		desc:         desc,
 		favicon:      favicon,
+		password:     hashedPassword,
+		maxVisits:    opts.MaxVisits,
+		routingRules: opts.RoutingRules,
+		variants:     opts.Variants,
+		urlParams:    opts.UrlParams,
+		redirectCode: opts.RedirectCode,
+		fallbackUrl:  opts.FallbackUrl,
+		tags:         tags,
 	}, nil
 }
 
 func (f Factory) NewLinkFromDB(
 	id uint,
 	gid string,
+	domain string,
 	shortUri string,
+	fullShortUrl string,
 	originalUrl string,
 	status Status,
 	createType CreateType,
 	favicon string,
 	desc string,
 	validDate *ValidDate,
+	password string,
+	maxVisits int,
+	routingRules RoutingRules,
+	variants Variants,
+	urlParams *UrlParams,
+	redirectCode RedirectCode,
+	fallbackUrl string,
+	tags Tags,
 ) (*Link, error) {
-	// 完整短链接
-	var fullShortUrl string
-	if f.fc.UseSSL {
-		fullShortUrl = fmt.Sprintf("https://%s/%s", f.fc.Domain, shortUri)
-	} else {
-		fullShortUrl = fmt.Sprintf("http://%s/%s", f.fc.Domain, shortUri)
+	// 完整短链接 在创建时确定，之前创建的短链接没有保存，都属于默认域名
+	if fullShortUrl == "" {
+		fullShortUrl = shortUrl(f.fc.Domain, f.fc.UseSSL, shortUri)
 	}
 
 	return &Link{
 		id:           id,
-		domain:       f.fc.Domain,
+		domain:       domain,
 		shortUri:     shortUri,
 		fullShortUrl: fullShortUrl,
 		originalUrl:  originalUrl,
@@ -196,17 +321,33 @@ func (f Factory) NewLinkFromDB(
 		desc:         desc,
 		favicon:      favicon,
 		validDate:    validDate,
+		password:     password,
+		maxVisits:    maxVisits,
+		routingRules: routingRules,
+		variants:     variants,
+		urlParams:    urlParams,
+		redirectCode: redirectCode,
+		fallbackUrl:  fallbackUrl,
+		tags:         tags,
 	}, nil
 
 }
 
+// genUniqueShortUri 生成未被占用的短链接，存在碰撞概率的生成器最多尝试 maxAttempts 次
 func (f Factory) genUniqueShortUri(
 	originalUrl string,
 	maxAttempts int,
 	ifExistsFunc func(string) (bool, error),
 ) (shortUri string, err error) {
+	// 天然唯一的生成器只会与自定义短链接冲突，重试没有意义，直接失败
+	collisionFree := f.fc.Generator.Collision() == CollisionFree
+	if collisionFree {
+		maxAttempts = 1
+	}
 	for i := 0; i < maxAttempts; i++ {
-		shortUri = toolkit.HashToBase62(originalUrl + uuid.NewString())
+		if shortUri, err = f.fc.Generator.Generate(originalUrl); err != nil {
+			return "", err
+		}
 		var exists bool
 		if exists, err = ifExistsFunc(shortUri); err != nil {
 			return "", err
@@ -215,24 +356,116 @@ func (f Factory) genUniqueShortUri(
 			return shortUri, nil
 		}
 	}
+	if collisionFree {
+		return "", errno.LinkAlreadyExists
+	}
 	return "", errno.LinkTooManyAttempts
 }
 
+// useAlias 校验自定义短链接并确认未被占用
+func (f Factory) useAlias(alias string, ifExistsFunc func(string) (bool, error)) (string, error) {
+	if err := f.verifyAlias(alias); err != nil {
+		return "", err
+	}
+	exists, err := ifExistsFunc(alias)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", errno.LinkAliasAlreadyExists
+	}
+	return alias, nil
+}
+
+// verifyAlias 校验自定义短链接的字符、长度以及是否为保留字
+func (f Factory) verifyAlias(alias string) error {
+	if len(alias) < f.fc.AliasMinLength || len(alias) > f.fc.AliasMaxLength {
+		return errno.LinkInvalidAlias
+	}
+	if !aliasPattern.MatchString(alias) {
+		return errno.LinkInvalidAlias
+	}
+	for _, words := range [][]string{builtinReservedWords, f.fc.ReservedWords} {
+		for _, v := range words {
+			if strings.EqualFold(alias, v) {
+				return errno.LinkReservedAlias
+			}
+		}
+	}
+	return nil
+}
+
+// verifyRoutingRules 校验路由规则，跳转目标需要满足白名单限制
+func (f Factory) verifyRoutingRules(rules RoutingRules) error {
+	if err := rules.validate(); err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if err := f.verifyWhiteList(r.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyVariants 校验分流配置，跳转目标需要满足白名单限制
+func (f Factory) verifyVariants(variants Variants) error {
+	if err := variants.validate(); err != nil {
+		return err
+	}
+	for _, v := range variants {
+		if err := f.verifyWhiteList(v.Url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyUpdate 校验修改后的跳转目标，与创建时相同需要满足白名单限制，参数为空表示不修改
+//
+// 跳转目标的格式由 Link.Update 校验，这里只校验需要工厂配置的部分
+func (f Factory) VerifyUpdate(
+	originalUrl string,
+	routingRules *RoutingRules,
+	variants *Variants,
+	fallbackUrl *string,
+) error {
+	if originalUrl != "" {
+		if err := f.verifyWhiteList(originalUrl); err != nil {
+			return err
+		}
+	}
+	if routingRules != nil {
+		if err := f.verifyRoutingRules(*routingRules); err != nil {
+			return err
+		}
+	}
+	if variants != nil {
+		if err := f.verifyVariants(*variants); err != nil {
+			return err
+		}
+	}
+	if fallbackUrl != nil && *fallbackUrl != "" {
+		if err := validateFallbackUrl(*fallbackUrl); err != nil {
+			return err
+		}
+		if err := f.verifyWhiteList(*fallbackUrl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f Factory) verifyWhiteList(originUrl string) error {
-	whitelist := f.fc.Whitelist
-	if whitelist == nil || len(whitelist) == 0 {
+	if Whitelist(f.fc.Whitelist).Allows(originUrl) {
 		return nil
 	}
-
-	domain := toolkit.ExtractDomain(originUrl)
-	if domain == "" {
+	if toolkit.ExtractDomain(originUrl) == "" {
 		return errno.LinkInvalidOriginalUrl
 	}
-	for _, v := range whitelist {
-		// 支持子域名
-		if domain == v || strings.HasSuffix(domain, "."+v) {
-			return nil
-		}
+	// 开启警告页面时不拒绝，由跳转时提示访问者
+	if f.fc.Interstitial {
+		return nil
 	}
 	return errno.LinkDisallowedDomain
 }
@@ -0,0 +1,119 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestFactory_verifyAlias(t *testing.T) {
+	f := Factory{fc: FactoryConfig{
+		AliasMinLength: 4,
+		AliasMaxLength: 8,
+		ReservedWords:  []string{"admin"},
+	}}
+
+	tests := []struct {
+		name  string
+		alias string
+		want  error
+	}{
+		{"valid", "my-link", nil},
+		{"underscore", "my_link", nil},
+		{"too short", "abc", errno.LinkInvalidAlias},
+		{"too long", "abcdefghi", errno.LinkInvalidAlias},
+		{"invalid char", "my/link", errno.LinkInvalidAlias},
+		{"builtin reserved", "page", errno.LinkReservedAlias},
+		{"config reserved", "Admin", errno.LinkReservedAlias},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := f.verifyAlias(tt.alias); !errors.Is(err, tt.want) {
+				t.Errorf("verifyAlias(%q) = %v, want %v", tt.alias, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactory_useAlias(t *testing.T) {
+	f := Factory{fc: FactoryConfig{AliasMinLength: 4, AliasMaxLength: 8}}
+
+	taken := func(string) (bool, error) { return true, nil }
+	if _, err := f.useAlias("my-link", taken); !errors.Is(err, errno.LinkAliasAlreadyExists) {
+		t.Errorf("useAlias with taken alias = %v, want %v", err, errno.LinkAliasAlreadyExists)
+	}
+
+	free := func(string) (bool, error) { return false, nil }
+	if got, err := f.useAlias("my-link", free); err != nil || got != "my-link" {
+		t.Errorf("useAlias with free alias = %q, %v", got, err)
+	}
+}
+
+func TestFactory_VerifyUpdate(t *testing.T) {
+	f := Factory{fc: FactoryConfig{Whitelist: []string{"github.com"}}}
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		originalUrl  string
+		routingRules *RoutingRules
+		variants     *Variants
+		fallbackUrl  *string
+		want         error
+	}{
+		{"nothing changed", "", nil, nil, nil, nil},
+		{"allowed original url", "https://github.com/weedien", nil, nil, nil, nil},
+		{"disallowed original url", "https://example.com", nil, nil, nil, errno.LinkDisallowedDomain},
+		{"allowed routing rule", "", &RoutingRules{{Device: "Mobile", Target: "https://gist.github.com"}}, nil, nil, nil},
+		{"disallowed routing rule", "", &RoutingRules{{Device: "Mobile", Target: "https://example.com"}}, nil, nil, errno.LinkDisallowedDomain},
+		{"invalid routing rule", "", &RoutingRules{{Target: "https://github.com"}}, nil, nil, errno.LinkInvalidRoutingRule},
+		{"clear routing rules", "", &RoutingRules{}, nil, nil, nil},
+		{"disallowed variant", "", nil, &Variants{
+			{Name: "a", Url: "https://github.com/a", Weight: 1},
+			{Name: "b", Url: "https://example.com/b", Weight: 1},
+		}, nil, errno.LinkDisallowedDomain},
+		{"invalid variant", "", nil, &Variants{{Name: "a", Url: "https://github.com/a", Weight: 0}}, nil, errno.LinkInvalidVariant},
+		{"disallowed fallback url", "", nil, nil, str("https://example.com"), errno.LinkDisallowedDomain},
+		{"invalid fallback url", "", nil, nil, str("not a url"), errno.LinkInvalidFallbackUrl},
+		{"clear fallback url", "", nil, nil, str(""), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := f.VerifyUpdate(tt.originalUrl, tt.routingRules, tt.variants, tt.fallbackUrl); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyUpdate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactory_NewAvailableLink_MaxVisits(t *testing.T) {
+	f := Factory{fc: FactoryConfig{
+		DefaultValidType: ValidTypePermanent,
+		AliasMinLength:   4,
+		AliasMaxLength:   8,
+	}}
+	free := func(string) (bool, error) { return false, nil }
+
+	tests := []struct {
+		name      string
+		maxVisits int
+		want      error
+	}{
+		{"unlimited", 0, nil},
+		{"single visit", 1, nil},
+		{"many visits", 1000, nil},
+		{"negative", -1, errno.LinkInvalidMaxVisits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lk, err := f.NewAvailableLink("https://github.com", "gid", nil, nil, nil, nil, "",
+				CreateOptions{Alias: "my-link", MaxVisits: tt.maxVisits}, free)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("NewAvailableLink() error = %v, want %v", err, tt.want)
+			}
+			if err == nil && lk.MaxVisits() != tt.maxVisits {
+				t.Errorf("MaxVisits() = %d, want %d", lk.MaxVisits(), tt.maxVisits)
+			}
+		})
+	}
+}
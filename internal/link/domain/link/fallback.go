@@ -0,0 +1,30 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/toolkit"
+)
+
+// fallbackReasons 可以跳转到备用链接的原因，被禁用或不存在的短链接不跳转
+var fallbackReasons = []error{errno.LinkExpired, errno.LinkDisabled, errno.LinkVisitsExhausted}
+
+// Fallback 短链接不可用时的备用跳转目标，reason 为不可用的原因
+func (c CacheValue) Fallback(reason error) (string, bool) {
+	if c.FallbackUrl == "" || reason == nil {
+		return "", false
+	}
+	for _, r := range fallbackReasons {
+		if errors.Is(reason, r) {
+			return c.FallbackUrl, true
+		}
+	}
+	return "", false
+}
+
+func validateFallbackUrl(fallbackUrl string) error {
+	if fallbackUrl != "" && !toolkit.IsValidUrl(fallbackUrl) {
+		return errno.LinkInvalidFallbackUrl
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package link
+
+// GroupSetting 分组级别的短链接默认配置
+//
+// 创建短链接时未单独指定的配置取自所在分组，之后修改分组配置不会影响已创建的短链接
+type GroupSetting struct {
+	gid string
+	// 默认的查询参数处理
+	urlParams *UrlParams
+	// 默认的备用链接
+	fallbackUrl string
+}
+
+func NewGroupSetting(gid string, urlParams *UrlParams, fallbackUrl string) (*GroupSetting, error) {
+	if err := urlParams.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateFallbackUrl(fallbackUrl); err != nil {
+		return nil, err
+	}
+	return &GroupSetting{gid: gid, urlParams: urlParams, fallbackUrl: fallbackUrl}, nil
+}
+
+func (s GroupSetting) Gid() string {
+	return s.gid
+}
+
+func (s GroupSetting) UrlParams() *UrlParams {
+	return s.urlParams
+}
+
+func (s GroupSetting) FallbackUrl() string {
+	return s.fallbackUrl
+}
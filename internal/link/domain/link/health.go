@@ -0,0 +1,46 @@
+package link
+
+import (
+	"net/http"
+	"time"
+)
+
+// HealthCheck 跳转目标的健康检查结果
+type HealthCheck struct {
+	// 响应状态码 0 表示无法访问
+	StatusCode int
+	// 响应耗时
+	Latency time.Duration
+	// 检查时间
+	CheckedAt time.Time
+}
+
+// Broken 跳转目标是否失效
+//
+// 无法访问、404、410 和服务端错误视为失效，401、403 等需要登录或被限流的页面仍然可以正常跳转
+func (h HealthCheck) Broken() bool {
+	return h.StatusCode == 0 ||
+		h.StatusCode == http.StatusNotFound ||
+		h.StatusCode == http.StatusGone ||
+		h.StatusCode >= http.StatusInternalServerError
+}
+
+// HealthTarget 需要检查跳转目标的短链接
+type HealthTarget struct {
+	Identifier
+	FullShortUrl string
+	OriginalUrl  string
+	// 上次检查结果 为 nil 表示从未检查
+	Last *HealthCheck
+}
+
+// BecameBroken 本次检查发现跳转目标失效，且上次检查时仍然正常，用于避免重复通知
+func (t HealthTarget) BecameBroken(check HealthCheck) bool {
+	return check.Broken() && (t.Last == nil || !t.Last.Broken())
+}
+
+// HealthReport 短链接及其本次检查结果
+type HealthReport struct {
+	HealthTarget
+	Check HealthCheck
+}
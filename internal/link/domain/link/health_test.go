@@ -0,0 +1,27 @@
+package link
+
+import "testing"
+
+func TestHealthTarget_BecameBroken(t *testing.T) {
+	ok := &HealthCheck{StatusCode: 200}
+	notFound := &HealthCheck{StatusCode: 404}
+	tests := []struct {
+		name  string
+		last  *HealthCheck
+		check HealthCheck
+		want  bool
+	}{
+		{"never checked", nil, HealthCheck{StatusCode: 0}, true},
+		{"healthy to broken", ok, HealthCheck{StatusCode: 503}, true},
+		{"still broken", notFound, HealthCheck{StatusCode: 404}, false},
+		{"forbidden is not broken", ok, HealthCheck{StatusCode: 403}, false},
+		{"recovered", notFound, HealthCheck{StatusCode: 200}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (HealthTarget{Last: tt.last}).BecameBroken(tt.check); got != tt.want {
+				t.Errorf("BecameBroken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
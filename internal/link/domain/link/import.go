@@ -0,0 +1,246 @@
+package link
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"shortlink/internal/base/errno"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportFormat 导入文件格式
+type ImportFormat string
+
+const (
+	// ImportCSV 第一行为表头，列名见 importColumns
+	ImportCSV ImportFormat = "csv"
+	// ImportNDJSON 每行一个 JSON 对象，字段名与 CSV 列名相同
+	ImportNDJSON ImportFormat = "ndjson"
+)
+
+// importColumns 支持的列名 url 和 original_url 等价
+var importColumns = []string{"url", "original_url", "desc", "gid", "valid_type", "start_date", "end_date"}
+
+// maxImportLineBytes NDJSON 单行的最大长度
+const maxImportLineBytes = 1 << 20
+
+// ImportRow 导入文件中的一行
+type ImportRow struct {
+	// 行号 从 1 开始
+	Line        int
+	OriginalUrl string
+	Desc        string
+	// 为空时使用导入时指定的分组
+	Gid       string
+	ValidType *ValidType
+	StartDate *time.Time
+	EndDate   *time.Time
+	// 解析失败的原因 不为 nil 时其他字段无意义
+	Err error
+}
+
+// ParseImportFormat 解析导入文件格式 json 视为 ndjson
+func ParseImportFormat(format string) (ImportFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "csv":
+		return ImportCSV, nil
+	case "ndjson", "jsonl", "json":
+		return ImportNDJSON, nil
+	}
+	return "", errno.LinkImportFormat
+}
+
+// ParseImportRows 解析导入文件
+//
+// 单行的格式错误记录在 ImportRow.Err 中，不影响其他行；文件为空或超过 maxRows 行时返回错误
+func ParseImportRows(format ImportFormat, r io.Reader, maxRows int) ([]ImportRow, error) {
+	var (
+		rows []ImportRow
+		err  error
+	)
+	switch format {
+	case ImportCSV:
+		rows, err = parseImportCSV(r, maxRows)
+	case ImportNDJSON:
+		rows, err = parseImportNDJSON(r, maxRows)
+	default:
+		return nil, errno.LinkImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errno.LinkImportEmpty
+	}
+	return rows, nil
+}
+
+func parseImportCSV(r io.Reader, maxRows int) ([]ImportRow, error) {
+	reader := csv.NewReader(skipBOM(r))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errno.LinkImportEmpty
+	}
+	if err != nil {
+		return nil, errno.NewRequestError(fmt.Sprintf("导入文件表头格式错误: %v", err))
+	}
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+	_, hasUrl := columns["url"]
+	_, hasOriginalUrl := columns["original_url"]
+	if !hasUrl && !hasOriginalUrl {
+		return nil, errno.NewRequestError("导入文件缺少 url 列")
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			// 引号不匹配等错误只影响当前行
+			rows = append(rows, ImportRow{Line: parseErr.StartLine, Err: errno.NewRequestError(parseErr.Err.Error())})
+		} else {
+			line, _ := reader.FieldPos(0)
+			fields := make(map[string]string, len(importColumns))
+			for _, name := range importColumns {
+				if idx, ok := columns[name]; ok && idx < len(record) {
+					fields[name] = strings.TrimSpace(record[idx])
+				}
+			}
+			rows = append(rows, newImportRow(line, fields))
+		}
+		if len(rows) > maxRows {
+			return nil, errno.LinkImportTooManyRows
+		}
+	}
+	return rows, nil
+}
+
+func parseImportNDJSON(r io.Reader, maxRows int) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(skipBOM(r))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineBytes)
+
+	var rows []ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal(text, &record); err != nil {
+			rows = append(rows, ImportRow{Line: line, Err: errno.NewRequestError("不合法的 JSON")})
+		} else {
+			fields := make(map[string]string, len(importColumns))
+			for _, name := range importColumns {
+				if v, ok := record[name]; ok && v != nil {
+					fields[name] = strings.TrimSpace(fmt.Sprint(v))
+				}
+			}
+			rows = append(rows, newImportRow(line, fields))
+		}
+		if len(rows) > maxRows {
+			return nil, errno.LinkImportTooManyRows
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, errno.NewRequestError(fmt.Sprintf("第 %d 行过长", line+1))
+		}
+		return nil, err
+	}
+	return rows, nil
+}
+
+// newImportRow 将一行的各列转为 ImportRow
+func newImportRow(line int, fields map[string]string) ImportRow {
+	row := ImportRow{
+		Line:        line,
+		OriginalUrl: fields["url"],
+		Desc:        fields["desc"],
+		Gid:         fields["gid"],
+	}
+	if row.OriginalUrl == "" {
+		row.OriginalUrl = fields["original_url"]
+	}
+	if row.OriginalUrl == "" {
+		row.Err = errno.LinkInvalidOriginalUrl
+		return row
+	}
+	if v := fields["valid_type"]; v != "" {
+		validType, err := parseImportValidType(v)
+		if err != nil {
+			row.Err = err
+			return row
+		}
+		row.ValidType = &validType
+	}
+	var err error
+	if row.StartDate, err = parseImportTime("start_date", fields["start_date"]); err != nil {
+		row.Err = err
+		return row
+	}
+	if row.EndDate, err = parseImportTime("end_date", fields["end_date"]); err != nil {
+		row.Err = err
+		return row
+	}
+	return row
+}
+
+// parseImportValidType 支持数字和英文名称
+func parseImportValidType(v string) (ValidType, error) {
+	switch strings.ToLower(v) {
+	case "permanent":
+		return ValidTypePermanent, nil
+	case "temporary":
+		return ValidTypeTemporary, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || (ValidType(n) != ValidTypePermanent && ValidType(n) != ValidTypeTemporary) {
+		return 0, errno.LinkInvalidValidType
+	}
+	return ValidType(n), nil
+}
+
+// parseImportTime 支持 2006-01-02 15:04:05、2006-01-02 和 RFC3339，未带时区时按本地时间解析，为空时返回 nil
+func parseImportTime(name, v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errno.NewRequestError(fmt.Sprintf("不合法的时间 %s: %s", name, v))
+}
+
+// skipBOM 去掉 Excel 导出的 CSV 文件开头的 UTF-8 BOM
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(3)
+	}
+	return br
+}
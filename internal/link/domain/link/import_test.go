@@ -0,0 +1,86 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"strings"
+	"testing"
+)
+
+func TestParseImportRows(t *testing.T) {
+	csvData := "\uFEFFURL,desc,gid,valid_type,start_date,end_date\n" +
+		"https://a.com,first,g1,,,\n" +
+		"https://b.com,\"with, comma\",,1,2024-01-01,2024-02-01 12:00:00\n" +
+		",missing url,g1,,,\n" +
+		"https://c.com,,,2,,\n" +
+		"https://d.com,,,,,tomorrow\n"
+	ndjsonData := `{"url":"https://a.com","gid":"g1","valid_type":1,"end_date":"2030-01-01"}` + "\n" +
+		"\n" +
+		`{"original_url":"https://b.com","desc":"second"}` + "\n" +
+		"not json\n"
+
+	tests := []struct {
+		name      string
+		format    ImportFormat
+		data      string
+		wantLines []int
+		// 解析失败的行号
+		wantErrLines []int
+	}{
+		{"csv", ImportCSV, csvData, []int{2, 3, 4, 5, 6}, []int{4, 5, 6}},
+		{"ndjson", ImportNDJSON, ndjsonData, []int{1, 3, 4}, []int{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseImportRows(tt.format, strings.NewReader(tt.data), 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != len(tt.wantLines) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.wantLines))
+			}
+			var errLines []int
+			for i, row := range rows {
+				if row.Line != tt.wantLines[i] {
+					t.Errorf("rows[%d].Line = %d, want %d", i, row.Line, tt.wantLines[i])
+				}
+				if row.Err != nil {
+					errLines = append(errLines, row.Line)
+				}
+			}
+			if len(errLines) != len(tt.wantErrLines) {
+				t.Errorf("error lines = %v, want %v", errLines, tt.wantErrLines)
+			}
+		})
+	}
+
+	rows, _ := ParseImportRows(ImportCSV, strings.NewReader(csvData), 10)
+	if b := rows[1]; b.Desc != "with, comma" || b.Gid != "" || b.ValidType == nil || *b.ValidType != ValidTypeTemporary ||
+		b.StartDate == nil || b.EndDate == nil || b.EndDate.Hour() != 12 {
+		t.Errorf("unexpected row %+v", b)
+	}
+}
+
+func TestParseImportRows_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  ImportFormat
+		data    string
+		wantErr error
+	}{
+		{"empty", ImportCSV, "", errno.LinkImportEmpty},
+		{"header only", ImportCSV, "url,desc\n", errno.LinkImportEmpty},
+		{"too many rows", ImportNDJSON, strings.Repeat(`{"url":"https://a.com"}`+"\n", 3), errno.LinkImportTooManyRows},
+		{"unknown format", ImportFormat("xlsx"), "url\nhttps://a.com\n", errno.LinkImportFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseImportRows(tt.format, strings.NewReader(tt.data), 2); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseImportRows() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := ParseImportRows(ImportCSV, strings.NewReader("desc,gid\nx,y\n"), 2); err == nil {
+		t.Error("expected error for missing url column")
+	}
+}
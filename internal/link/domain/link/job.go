@@ -0,0 +1,253 @@
+package link
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// JobResultWriter 任务结果文件，Close 之后才可以下载，写入失败时调用 Abort 丢弃已写入的内容
+type JobResultWriter interface {
+	io.WriteCloser
+	Abort() error
+}
+
+// JobType 后台任务类型
+type JobType string
+
+const (
+	// JobImport 批量导入短链接
+	JobImport JobType = "import"
+	// JobExport 导出分组下的短链接
+	JobExport JobType = "export"
+)
+
+// JobStatus 后台任务状态
+type JobStatus string
+
+const (
+	// JobPending 已创建，等待执行
+	JobPending JobStatus = "pending"
+	// JobRunning 执行中
+	JobRunning JobStatus = "running"
+	// JobSucceeded 执行完成 部分行失败也视为完成，失败的行记录在 RowErrors 和结果文件中
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed 执行中断
+	JobFailed JobStatus = "failed"
+)
+
+// maxJobRowErrors 任务中保存的错误行数上限，完整结果见结果文件
+const maxJobRowErrors = 100
+
+// RowError 导入时某一行的错误
+type RowError struct {
+	// 行号 从 1 开始，CSV 的表头为第 1 行
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// RowErrors 错误行
+type RowErrors []RowError
+
+// Scan implements the sql.Scanner interface
+func (re *RowErrors) Scan(value interface{}) error {
+	if value == nil {
+		*re = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("invalid data type for RowErrors")
+	}
+	return json.Unmarshal(bytes, re)
+}
+
+// Value implements the driver.Valuer interface
+func (re RowErrors) Value() (driver.Value, error) {
+	if len(re) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(re)
+}
+
+// Job 后台任务 记录批量导入等耗时操作的进度
+type Job struct {
+	id      string
+	jobType JobType
+	// 创建任务的用户
+	owner  string
+	status JobStatus
+	// 只校验不写入
+	dryRun bool
+	// 总行数
+	total     int
+	processed int
+	succeeded int
+	failed    int
+	rowErrors RowErrors
+	// 结果文件名 任务完成后生成
+	resultFile string
+	// 任务中断的原因
+	message    string
+	finishTime *time.Time
+}
+
+func NewJob(jobType JobType, owner string, dryRun bool, total int) (*Job, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &Job{
+		id:      hex.EncodeToString(id),
+		jobType: jobType,
+		owner:   owner,
+		status:  JobPending,
+		dryRun:  dryRun,
+		total:   total,
+	}, nil
+}
+
+func NewJobFromDB(
+	id string,
+	jobType JobType,
+	owner string,
+	status JobStatus,
+	dryRun bool,
+	total int,
+	processed int,
+	succeeded int,
+	failed int,
+	rowErrors RowErrors,
+	resultFile string,
+	message string,
+	finishTime *time.Time,
+) *Job {
+	return &Job{
+		id:         id,
+		jobType:    jobType,
+		owner:      owner,
+		status:     status,
+		dryRun:     dryRun,
+		total:      total,
+		processed:  processed,
+		succeeded:  succeeded,
+		failed:     failed,
+		rowErrors:  rowErrors,
+		resultFile: resultFile,
+		message:    message,
+		finishTime: finishTime,
+	}
+}
+
+func (j Job) Id() string {
+	return j.id
+}
+
+func (j Job) Type() JobType {
+	return j.jobType
+}
+
+func (j Job) Owner() string {
+	return j.owner
+}
+
+func (j Job) Status() JobStatus {
+	return j.status
+}
+
+func (j Job) DryRun() bool {
+	return j.dryRun
+}
+
+func (j Job) Total() int {
+	return j.total
+}
+
+func (j Job) Processed() int {
+	return j.processed
+}
+
+func (j Job) Succeeded() int {
+	return j.succeeded
+}
+
+func (j Job) Failed() int {
+	return j.failed
+}
+
+func (j Job) RowErrors() RowErrors {
+	return j.rowErrors
+}
+
+func (j Job) ResultFile() string {
+	return j.resultFile
+}
+
+func (j Job) Message() string {
+	return j.message
+}
+
+func (j Job) FinishTime() *time.Time {
+	return j.finishTime
+}
+
+// OwnedBy 任务是否由该用户创建
+func (j Job) OwnedBy(username string) bool {
+	return j.owner == username
+}
+
+// Finished 任务是否已经结束
+func (j Job) Finished() bool {
+	return j.status == JobSucceeded || j.status == JobFailed
+}
+
+// Start 开始执行
+func (j *Job) Start() {
+	j.status = JobRunning
+}
+
+// RowsSucceeded 记录多行处理成功
+func (j *Job) RowsSucceeded(n int) {
+	j.processed += n
+	j.succeeded += n
+}
+
+// RowSucceeded 记录一行处理成功
+func (j *Job) RowSucceeded() {
+	j.processed++
+	j.succeeded++
+}
+
+// RowFailed 记录一行处理失败
+func (j *Job) RowFailed(line int, err error) {
+	j.processed++
+	j.failed++
+	if len(j.rowErrors) < maxJobRowErrors {
+		j.rowErrors = append(j.rowErrors, RowError{Line: line, Error: err.Error()})
+	}
+}
+
+// Succeed 任务完成
+func (j *Job) Succeed(resultFile string) {
+	now := time.Now()
+	j.status = JobSucceeded
+	j.resultFile = resultFile
+	j.finishTime = &now
+}
+
+// Fail 任务中断，已处理的行不会回滚
+func (j *Job) Fail(err error) {
+	now := time.Now()
+	j.status = JobFailed
+	j.message = err.Error()
+	j.finishTime = &now
+}
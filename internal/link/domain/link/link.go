@@ -13,14 +13,24 @@ import (
 type Identifier struct {
 	// 分组ID
 	Gid string
+	// 自定义域名 为空表示默认域名
+	Domain string
 	// 完整短链接
 	ShortUri string
 }
 
+// StatusChange 短链接状态变化
+type StatusChange struct {
+	Identifier
+	From Status
+	To   Status
+}
+
 // Link 短链接
-// 由 shortUri 确定唯一短链接
+// 由 domain + shortUri 确定唯一短链接
 type Link struct {
-	id           uint
+	id uint
+	// 自定义域名 为空表示默认域名
 	domain       string
 	shortUri     string
 	fullShortUrl string
@@ -31,6 +41,22 @@ type Link struct {
 	desc         string
 	favicon      string
 	validDate    *ValidDate
+	// 访问密码（哈希） 为空表示不受保护
+	password string
+	// 最大访问次数 0 表示不限制
+	maxVisits int
+	// 条件路由规则
+	routingRules RoutingRules
+	// 按权重分流的跳转目标
+	variants Variants
+	// 跳转时对查询参数的处理 为 nil 时丢弃短链接上的查询参数
+	urlParams *UrlParams
+	// 跳转状态码 0 表示使用 302
+	redirectCode RedirectCode
+	// 备用链接 过期、停用或访问次数用完时跳转
+	fallbackUrl string
+	// 标签
+	tags Tags
 }
 
 func (lk Link) ID() uint {
@@ -45,6 +71,11 @@ func (lk Link) Gid() string {
 	return lk.gid
 }
 
+// Destinations 所有跳转目标
+func (lk Link) Destinations() []string {
+	return Destinations(lk.originalUrl, lk.routingRules, lk.variants, lk.fallbackUrl)
+}
+
 func (lk Link) Favicon() string {
 	return lk.favicon
 }
@@ -57,6 +88,15 @@ func (lk Link) ShortUri() string {
 	return lk.shortUri
 }
 
+func (lk Link) Domain() string {
+	return lk.domain
+}
+
+// Key 短链接在缓存和布隆过滤器中的唯一标识
+func (lk Link) Key() string {
+	return Key(lk.domain, lk.shortUri)
+}
+
 func (lk Link) Status() Status {
 	return lk.status
 }
@@ -80,14 +120,61 @@ func (lk Link) Desc() string {
 	return lk.desc
 }
 
+func (lk Link) Password() string {
+	return lk.password
+}
+
+func (lk Link) MaxVisits() int {
+	return lk.maxVisits
+}
+
+func (lk Link) RoutingRules() RoutingRules {
+	return lk.routingRules
+}
+
+func (lk Link) Variants() Variants {
+	return lk.variants
+}
+
+func (lk Link) UrlParams() *UrlParams {
+	return lk.urlParams
+}
+
+func (lk Link) RedirectCode() RedirectCode {
+	return lk.redirectCode
+}
+
+func (lk Link) FallbackUrl() string {
+	return lk.fallbackUrl
+}
+
+// Protected 是否设置了访问密码
+func (lk Link) Tags() Tags {
+	return lk.tags
+}
+
+func (lk Link) Protected() bool {
+	return lk.password != ""
+}
+
 // Update 更新短链接信息
-func (lk Link) Update(
+//
+// password、fallbackUrl 为 nil 时不修改，为空字符串时清空；tags 为 nil 时不修改，为空数组时清空
+func (lk *Link) Update(
 	gid string,
 	originalUrl string,
 	status Status,
 	validType *ValidType,
 	validEndDate *time.Time,
 	desc *string,
+	password *string,
+	maxVisits *int,
+	routingRules *RoutingRules,
+	variants *Variants,
+	urlParams *UrlParams,
+	redirectCode *RedirectCode,
+	fallbackUrl *string,
+	tags *Tags,
 ) error {
 	if gid != "" {
 		lk.gid = gid
@@ -113,6 +200,56 @@ func (lk Link) Update(
 	if desc != nil {
 		lk.desc = *desc
 	}
+	if password != nil {
+		hashed, err := hashPassword(*password)
+		if err != nil {
+			return err
+		}
+		lk.password = hashed
+	}
+	if maxVisits != nil {
+		if *maxVisits < 0 {
+			return errno.LinkInvalidMaxVisits
+		}
+		lk.maxVisits = *maxVisits
+	}
+	if routingRules != nil {
+		if err := routingRules.validate(); err != nil {
+			return err
+		}
+		lk.routingRules = *routingRules
+	}
+	if variants != nil {
+		if err := variants.validate(); err != nil {
+			return err
+		}
+		lk.variants = *variants
+	}
+	if urlParams != nil {
+		if err := urlParams.validate(); err != nil {
+			return err
+		}
+		lk.urlParams = urlParams
+	}
+	if redirectCode != nil {
+		if !redirectCode.valid() {
+			return errno.LinkInvalidRedirectCode
+		}
+		lk.redirectCode = *redirectCode
+	}
+	if fallbackUrl != nil {
+		if err := validateFallbackUrl(*fallbackUrl); err != nil {
+			return err
+		}
+		lk.fallbackUrl = *fallbackUrl
+	}
+	if tags != nil {
+		normalized, err := tags.Normalize()
+		if err != nil {
+			return err
+		}
+		lk.tags = normalized
+	}
 	return nil
 }
 
@@ -122,39 +259,88 @@ type CacheValue struct {
 	StartTime   *time.Time `json:"startTime"`
 	EndTime     *time.Time `json:"endTime"`
 	Status      Status     `json:"status"`
+	// 是否设置了访问密码
+	Protected bool `json:"protected"`
+	// 访问密码（哈希） 用于校验解锁请求，避免回源数据库
+	PasswordHash string `json:"passwordHash,omitempty"`
+	// 最大访问次数 0 表示不限制
+	MaxVisits int `json:"maxVisits,omitempty"`
+	// 条件路由规则
+	RoutingRules RoutingRules `json:"routingRules,omitempty"`
+	// 按权重分流的跳转目标
+	Variants Variants `json:"variants,omitempty"`
+	// 跳转时对查询参数的处理
+	UrlParams *UrlParams `json:"urlParams,omitempty"`
+	// 跳转状态码 0 表示使用 302
+	RedirectCode RedirectCode `json:"redirectCode,omitempty"`
+	// 备用链接
+	FallbackUrl string `json:"fallbackUrl,omitempty"`
 }
 
 func NewCacheValue(lk *Link) *CacheValue {
 	return &CacheValue{
-		OriginalUrl: lk.OriginalUrl(),
-		NeverExpire: lk.ValidDate().NeverExpire(),
-		StartTime:   lk.ValidDate().StartTime(),
-		EndTime:     lk.ValidDate().EndTime(),
-		Status:      lk.Status(),
+		OriginalUrl:  lk.OriginalUrl(),
+		NeverExpire:  lk.ValidDate().NeverExpire(),
+		StartTime:    lk.ValidDate().StartTime(),
+		EndTime:      lk.ValidDate().EndTime(),
+		Status:       lk.Status(),
+		Protected:    lk.Protected(),
+		PasswordHash: lk.Password(),
+		MaxVisits:    lk.MaxVisits(),
+		RoutingRules: lk.RoutingRules(),
+		Variants:     lk.Variants(),
+		UrlParams:    lk.UrlParams(),
+		RedirectCode: lk.RedirectCode(),
+		FallbackUrl:  lk.FallbackUrl(),
 	}
 }
 
+// VerifyPassword 校验访问密码，未设置密码时直接通过
+func (c CacheValue) VerifyPassword(password string) bool {
+	if !c.Protected {
+		return true
+	}
+	return verifyPassword(c.PasswordHash, password)
+}
+
+// Resolve 选出本次访问的跳转目标
+//
+// 优先匹配条件路由规则，未命中时按权重分流，都未配置时跳转到原始链接；
+// variant 为命中的分流名称，未参与分流时为空
+func (c CacheValue) Resolve(shortUri, visitorId string, visitor Visitor) (target string, variant string) {
+	if target, ok := c.RoutingRules.Match(visitor); ok {
+		return target, ""
+	}
+	if v, ok := c.Variants.Pick(shortUri, visitorId); ok {
+		return v.Url, v.Name
+	}
+	return c.OriginalUrl, ""
+}
+
+// Validate 校验短链接当前是否可以访问
+//
+// 状态由定时任务按有效期流转，存在一定延迟，因此生效和过期时间以这里的判断为准
 func (c CacheValue) Validate() (bool, error) {
-	if c.Status == StatusActive {
-		if c.NeverExpire {
-			return true, nil
+	switch c.Status {
+	case StatusActive, StatusPending:
+		now := time.Now()
+		if c.StartTime != nil && c.StartTime.After(now) {
+			return false, errno.LinkNotStarted
 		}
-		if c.StartTime.Before(time.Now()) && c.EndTime.After(time.Now()) {
-			return true, nil
+		if !c.NeverExpire && c.EndTime != nil && !c.EndTime.After(now) {
+			return false, errno.LinkExpired
 		}
-		return false, errno.LinkExpired
-	}
-	switch {
-	case c.Status == StatusReserved:
+		return true, nil
+	case StatusReserved:
 		return false, errno.LinkReserved
-	case c.Status == StatusForbidden:
+	case StatusForbidden:
 		return false, errno.LinkForbidden
-	case c.Status == StatusDisabled:
+	case StatusDisabled:
 		return false, errno.LinkDisabled
-	case c.Status == StatusExpired:
+	case StatusExpired:
 		return false, errno.LinkExpired
 	}
-	return false, nil
+	return false, errno.LinkNotExists
 }
 
 func (c CacheValue) Expiration() time.Duration {
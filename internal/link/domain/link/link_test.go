@@ -0,0 +1,92 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+	"time"
+)
+
+func TestCacheValue_Validate(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	tests := []struct {
+		name string
+		cv   CacheValue
+		want error
+	}{
+		{"permanent", CacheValue{Status: StatusActive, NeverExpire: true, StartTime: &past}, nil},
+		{"in window", CacheValue{Status: StatusActive, StartTime: &past, EndTime: &future}, nil},
+		{"not started", CacheValue{Status: StatusActive, StartTime: &future, EndTime: &future}, errno.LinkNotStarted},
+		{"permanent not started", CacheValue{Status: StatusActive, NeverExpire: true, StartTime: &future}, errno.LinkNotStarted},
+		{"overdue", CacheValue{Status: StatusActive, StartTime: &past, EndTime: &past}, errno.LinkExpired},
+		{"pending but started", CacheValue{Status: StatusPending, StartTime: &past, EndTime: &future}, nil},
+		{"expired", CacheValue{Status: StatusExpired, NeverExpire: true}, errno.LinkExpired},
+		{"disabled", CacheValue{Status: StatusDisabled, NeverExpire: true}, errno.LinkDisabled},
+		{"deleted", CacheValue{Status: StatusDeleted, NeverExpire: true}, errno.LinkNotExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := tt.cv.Validate()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+			if ok != (tt.want == nil) {
+				t.Errorf("Validate() = %v", ok)
+			}
+		})
+	}
+}
+
+func TestCacheValue_Fallback(t *testing.T) {
+	cv := CacheValue{FallbackUrl: "https://example.com/gone"}
+	tests := []struct {
+		name   string
+		reason error
+		want   bool
+	}{
+		{"expired", errno.LinkExpired, true},
+		{"disabled", errno.LinkDisabled, true},
+		{"visits exhausted", errno.LinkVisitsExhausted, true},
+		{"forbidden", errno.LinkForbidden, false},
+		{"not started", errno.LinkNotStarted, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := cv.Fallback(tt.reason); ok != tt.want {
+				t.Errorf("Fallback(%v) = %v, want %v", tt.reason, ok, tt.want)
+			}
+		})
+	}
+	if _, ok := (CacheValue{}).Fallback(errno.LinkExpired); ok {
+		t.Errorf("Fallback() without url should not fall back")
+	}
+}
+
+func TestLink_Update_MaxVisits(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name      string
+		maxVisits *int
+		wantValue int
+		want      error
+	}{
+		{"unchanged", nil, 5, nil},
+		{"raise limit", intPtr(10), 10, nil},
+		{"remove limit", intPtr(0), 0, nil},
+		{"negative", intPtr(-1), 5, errno.LinkInvalidMaxVisits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lk := &Link{maxVisits: 5}
+			err := lk.Update("", "", "", nil, nil, nil, nil, tt.maxVisits, nil, nil, nil, nil, nil, nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.want)
+			}
+			if lk.MaxVisits() != tt.wantValue {
+				t.Errorf("MaxVisits() = %d, want %d", lk.MaxVisits(), tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package link
+
+// Metadata 跳转目标页面的元信息，创建短链接后异步获取
+type Metadata struct {
+	// 页面标题 优先使用 og:title
+	Title string
+	// 页面描述 优先使用 og:description
+	Description string
+	// 分享图片 og:image
+	Image string
+	// 网站图标
+	Favicon string
+}
+
+// Summary 用户没有填写描述时作为短链接的描述，优先使用页面标题
+func (m Metadata) Summary() string {
+	if m.Title != "" {
+		return m.Title
+	}
+	return m.Description
+}
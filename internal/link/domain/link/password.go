@@ -0,0 +1,36 @@
+package link
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"shortlink/internal/base/errno"
+	"unicode/utf8"
+)
+
+// 访问密码长度限制 bcrypt 最多只处理 72 字节
+const (
+	passwordMinLength = 4
+	passwordMaxLength = 64
+)
+
+// hashPassword 对访问密码做哈希，空密码表示不设置保护
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if n := utf8.RuneCountInString(password); n < passwordMinLength || n > passwordMaxLength || len(password) > 72 {
+		return "", errno.LinkInvalidPassword
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// verifyPassword 校验访问密码是否与哈希匹配
+func verifyPassword(hashed, password string) bool {
+	if hashed == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil
+}
@@ -0,0 +1,37 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestCacheValue_VerifyPassword(t *testing.T) {
+	hashed, err := hashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashed == "s3cret" {
+		t.Fatal("password should be stored hashed")
+	}
+
+	c := CacheValue{Protected: true, PasswordHash: hashed}
+	if !c.VerifyPassword("s3cret") {
+		t.Errorf("correct password rejected")
+	}
+	if c.VerifyPassword("wrong") {
+		t.Errorf("wrong password accepted")
+	}
+	if !(CacheValue{}).VerifyPassword("") {
+		t.Errorf("unprotected link should always pass")
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	if hashed, err := hashPassword(""); err != nil || hashed != "" {
+		t.Errorf("hashPassword(\"\") = %q, %v", hashed, err)
+	}
+	if _, err := hashPassword("abc"); !errors.Is(err, errno.LinkInvalidPassword) {
+		t.Errorf("hashPassword(\"abc\") error = %v, want %v", err, errno.LinkInvalidPassword)
+	}
+}
@@ -0,0 +1,55 @@
+package link
+
+import (
+	"net/http"
+	"shortlink/internal/base/toolkit"
+	"strings"
+)
+
+// RedirectCode 跳转使用的 HTTP 状态码
+//
+// 301 和 308 会被浏览器缓存，之后的访问不再经过短链接服务，访问统计和有效期控制都会失效
+type RedirectCode int
+
+const (
+	RedirectMovedPermanently  RedirectCode = http.StatusMovedPermanently
+	RedirectFound             RedirectCode = http.StatusFound
+	RedirectTemporaryRedirect RedirectCode = http.StatusTemporaryRedirect
+	RedirectPermanentRedirect RedirectCode = http.StatusPermanentRedirect
+)
+
+// StatusCode 未设置时使用 302
+func (c RedirectCode) StatusCode() int {
+	if c == 0 {
+		return int(RedirectFound)
+	}
+	return int(c)
+}
+
+func (c RedirectCode) valid() bool {
+	switch c {
+	case 0, RedirectMovedPermanently, RedirectFound, RedirectTemporaryRedirect, RedirectPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// Whitelist 允许跳转的域名，根域名下的子域名同样允许，为空时不限制
+type Whitelist []string
+
+// Allows 跳转目标是否在白名单内
+func (w Whitelist) Allows(rawUrl string) bool {
+	if len(w) == 0 {
+		return true
+	}
+	domain := toolkit.ExtractDomain(rawUrl)
+	if domain == "" {
+		return false
+	}
+	for _, v := range w {
+		if domain == v || strings.HasSuffix(domain, "."+v) {
+			return true
+		}
+	}
+	return false
+}
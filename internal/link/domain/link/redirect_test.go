@@ -0,0 +1,42 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestRedirectCode_StatusCode(t *testing.T) {
+	if got := RedirectCode(0).StatusCode(); got != 302 {
+		t.Errorf("StatusCode() = %d, want 302", got)
+	}
+	if got := RedirectPermanentRedirect.StatusCode(); got != 308 {
+		t.Errorf("StatusCode() = %d, want 308", got)
+	}
+	if RedirectCode(303).valid() {
+		t.Errorf("303 should be invalid")
+	}
+}
+
+func TestFactory_verifyWhiteList(t *testing.T) {
+	tests := []struct {
+		name         string
+		interstitial bool
+		url          string
+		want         error
+	}{
+		{"allowed", false, "https://github.com/weedien", nil},
+		{"subdomain", false, "https://gist.github.com", nil},
+		{"disallowed", false, "https://example.com", errno.LinkDisallowedDomain},
+		{"disallowed with interstitial", true, "https://example.com", nil},
+		{"invalid url", true, "://", errno.LinkInvalidOriginalUrl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Factory{fc: FactoryConfig{Whitelist: []string{"github.com"}, Interstitial: tt.interstitial}}
+			if err := f.verifyWhiteList(tt.url); !errors.Is(err, tt.want) {
+				t.Errorf("verifyWhiteList() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package link
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/toolkit"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxRoutingRules 单个短链接最多允许的路由规则数量
+const maxRoutingRules = 20
+
+// RoutingRule 条件路由规则
+//
+// 所有非空条件同时满足时命中，多个取值之间为“或”的关系
+type RoutingRule struct {
+	// 设备类型 PC / Mobile
+	Device string `json:"device,omitempty"`
+	// 操作系统 如 iOS、Android、Windows，按包含关系匹配
+	OS string `json:"os,omitempty"`
+	// 浏览器 如 Chrome、Safari，按包含关系匹配
+	Browser string `json:"browser,omitempty"`
+	// 网络类型 WIFI / Mobile
+	Network string `json:"network,omitempty"`
+	// 语言 如 zh、en-US，zh 可以匹配 zh-CN
+	Languages []string `json:"languages,omitempty"`
+	// 国家 ISO 3166-1 两位代码，如 CN、US
+	Countries []string `json:"countries,omitempty"`
+	// 跳转目标
+	Target string `json:"target"`
+}
+
+// RoutingRules 按顺序匹配的路由规则，第一条命中的规则生效
+type RoutingRules []RoutingRule
+
+// Visitor 访问者信息，用于匹配路由规则
+type Visitor struct {
+	OS        string
+	Browser   string
+	Device    string
+	Network   string
+	Languages []string
+	Country   string
+}
+
+// Match 根据访问者信息选出跳转目标，ok 表示是否有规则命中
+func (rs RoutingRules) Match(v Visitor) (target string, ok bool) {
+	for _, r := range rs {
+		if r.match(v) {
+			return r.Target, true
+		}
+	}
+	return "", false
+}
+
+// NeedCountry 是否有规则依赖国家，查询 IP 归属地的开销较大，只在需要时查询
+func (rs RoutingRules) NeedCountry() bool {
+	for _, r := range rs {
+		if len(r.Countries) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (rs RoutingRules) validate() error {
+	if len(rs) > maxRoutingRules {
+		return errno.LinkInvalidRoutingRule
+	}
+	for _, r := range rs {
+		if r.Target == "" || !toolkit.IsValidUrl(r.Target) {
+			return errno.LinkInvalidRoutingRule
+		}
+		if r.Device == "" && r.OS == "" && r.Browser == "" && r.Network == "" &&
+			len(r.Languages) == 0 && len(r.Countries) == 0 {
+			return errno.LinkInvalidRoutingRule
+		}
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (rs *RoutingRules) Scan(value interface{}) error {
+	if value == nil {
+		*rs = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("invalid data type for RoutingRules")
+	}
+	return json.Unmarshal(bytes, rs)
+}
+
+// Value implements the driver.Valuer interface
+func (rs RoutingRules) Value() (driver.Value, error) {
+	if len(rs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(rs)
+}
+
+func (r RoutingRule) match(v Visitor) bool {
+	if r.Device != "" && !strings.EqualFold(r.Device, v.Device) {
+		return false
+	}
+	if r.OS != "" && !matchOS(r.OS, v.OS) {
+		return false
+	}
+	if r.Browser != "" && !containsFold(v.Browser, r.Browser) {
+		return false
+	}
+	if r.Network != "" && !strings.EqualFold(r.Network, v.Network) {
+		return false
+	}
+	if len(r.Languages) > 0 && !matchLanguages(r.Languages, v.Languages) {
+		return false
+	}
+	if len(r.Countries) > 0 && !matchCountry(r.Countries, v.Country) {
+		return false
+	}
+	return true
+}
+
+// matchOS user_agent 解析出的 iOS 系统名形如 "CPU iPhone OS 17_0 like Mac OS X"，需要特殊处理
+func matchOS(want, got string) bool {
+	if strings.EqualFold(want, "ios") {
+		return containsFold(got, "iphone") || containsFold(got, "ipad") || containsFold(got, "ios")
+	}
+	return containsFold(got, want)
+}
+
+func matchLanguages(want, got []string) bool {
+	for _, w := range want {
+		for _, g := range got {
+			// zh 匹配 zh-CN，zh-CN 只匹配 zh-CN
+			if strings.EqualFold(w, g) || (!strings.Contains(w, "-") && strings.EqualFold(w, strings.SplitN(g, "-", 2)[0])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchCountry(want []string, got string) bool {
+	for _, w := range want {
+		if strings.EqualFold(w, got) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ParseAcceptLanguage 按优先级解析 Accept-Language 请求头，如 "zh-CN,zh;q=0.9,en;q=0.8"
+func ParseAcceptLanguage(header string) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		langs = append(langs, lang{tag: tag, q: q})
+	}
+	// 稳定排序，权重相同时保持原有顺序
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	res := make([]string, 0, len(langs))
+	for _, l := range langs {
+		res = append(res, l.tag)
+	}
+	return res
+}
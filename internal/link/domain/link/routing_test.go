@@ -0,0 +1,79 @@
+package link
+
+import (
+	"errors"
+	"reflect"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestRoutingRules_Match(t *testing.T) {
+	rules := RoutingRules{
+		{OS: "iOS", Target: "https://apps.apple.com/app/id1"},
+		{OS: "Android", Target: "https://play.google.com/store/apps/details?id=app"},
+		{Languages: []string{"zh"}, Countries: []string{"CN"}, Target: "https://example.cn"},
+	}
+	const fallback = ""
+
+	tests := []struct {
+		name    string
+		visitor Visitor
+		want    string
+	}{
+		{"iphone", Visitor{OS: "CPU iPhone OS 17_0 like Mac OS X", Device: "Mobile"}, "https://apps.apple.com/app/id1"},
+		{"android", Visitor{OS: "Android 13", Device: "Mobile"}, "https://play.google.com/store/apps/details?id=app"},
+		{"chinese in china", Visitor{OS: "Windows 10", Languages: []string{"zh-CN"}, Country: "CN"}, "https://example.cn"},
+		{"chinese abroad", Visitor{OS: "Windows 10", Languages: []string{"zh-CN"}, Country: "US"}, fallback},
+		{"default", Visitor{OS: "Windows 10"}, fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rules.Match(tt.visitor)
+			if got != tt.want || ok != (tt.want != fallback) {
+				t.Errorf("Match() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	if !rules.NeedCountry() {
+		t.Errorf("NeedCountry() = false, want true")
+	}
+	if rules[:2].NeedCountry() {
+		t.Errorf("NeedCountry() = true, want false")
+	}
+}
+
+func TestRoutingRules_validate(t *testing.T) {
+	if err := (RoutingRules{{Target: "https://example.com"}}).validate(); !errors.Is(err, errno.LinkInvalidRoutingRule) {
+		t.Errorf("rule without condition: err = %v", err)
+	}
+	if err := (RoutingRules{{Device: "PC", Target: "not a url"}}).validate(); !errors.Is(err, errno.LinkInvalidRoutingRule) {
+		t.Errorf("rule with invalid target: err = %v", err)
+	}
+	if err := (RoutingRules{{Device: "PC", Target: "https://example.com"}}).validate(); err != nil {
+		t.Errorf("valid rule: err = %v", err)
+	}
+}
+
+func TestRoutingRules_ScanValue(t *testing.T) {
+	rules := RoutingRules{{Languages: []string{"en"}, Target: "https://example.com"}}
+	v, err := rules.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got RoutingRules
+	if err = got.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rules) {
+		t.Errorf("Scan(Value()) = %v, want %v", got, rules)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("en;q=0.8, zh-CN,zh;q=0.9, *;q=0.1")
+	want := []string{"zh-CN", "zh", "en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAcceptLanguage() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+package link
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/snowflake"
+	"github.com/google/uuid"
+	"hash/fnv"
+	"math/big"
+	"shortlink/internal/base/toolkit"
+	"sync"
+)
+
+// base62Alphabet 短链接默认字符集
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// CollisionBehavior 生成器的碰撞特性
+type CollisionBehavior int
+
+const (
+	// CollisionFree 生成结果在单个集群内天然唯一，只需校验一次是否被自定义短链接占用
+	CollisionFree CollisionBehavior = iota
+	// CollisionProbable 生成结果存在碰撞概率，需要结合 MaxAttempts 重试
+	CollisionProbable
+)
+
+func (c CollisionBehavior) String() string {
+	switch c {
+	case CollisionFree:
+		return "collision-free"
+	case CollisionProbable:
+		return "collision-probable"
+	default:
+		return "unknown"
+	}
+}
+
+// ShortCodeGenerator 短链接生成策略
+type ShortCodeGenerator interface {
+	// Generate 生成短链接，originalUrl 仅供基于哈希的策略使用
+	Generate(originalUrl string) (string, error)
+	// Collision 生成结果的碰撞特性
+	Collision() CollisionBehavior
+}
+
+// ShortCodeGeneratorType 短链接生成策略类型
+type ShortCodeGeneratorType string
+
+const (
+	ShortCodeGeneratorHash      ShortCodeGeneratorType = "hash"
+	ShortCodeGeneratorCounter   ShortCodeGeneratorType = "counter"
+	ShortCodeGeneratorSnowflake ShortCodeGeneratorType = "snowflake"
+	ShortCodeGeneratorRandom    ShortCodeGeneratorType = "random"
+)
+
+// HashGenerator 对 originalUrl+uuid 做 FNV-32 哈希后转为 base62
+//
+// 结果长度不固定（1~6 位），取值空间只有 2^32，随着数据量增长碰撞概率明显上升
+type HashGenerator struct{}
+
+func NewHashGenerator() HashGenerator {
+	return HashGenerator{}
+}
+
+func (g HashGenerator) Generate(originalUrl string) (string, error) {
+	return toolkit.HashToBase62(originalUrl + uuid.NewString()), nil
+}
+
+func (g HashGenerator) Collision() CollisionBehavior {
+	return CollisionProbable
+}
+
+// SegmentAllocator 号段分配器
+type SegmentAllocator interface {
+	// Allocate 申请一个长度为 step 的号段，返回号段的最大值（包含）
+	Allocate(step int64) (int64, error)
+}
+
+// CounterGenerator 基于号段的自增计数器，编码时使用打乱后的 base62 字符集
+//
+// 计数器全局递增，只要分配器不回退就不会碰撞；编码方式参考 Sqids，相邻的 ID 不会生成相邻的短链接
+type CounterGenerator struct {
+	allocator SegmentAllocator
+	step      int64
+	minLength int
+	alphabet  []byte
+
+	mu  sync.Mutex
+	cur int64
+	max int64
+}
+
+func NewCounterGenerator(allocator SegmentAllocator, step int64, minLength int, salt string) (*CounterGenerator, error) {
+	if allocator == nil {
+		return nil, errors.New("nil allocator")
+	}
+	if step < 1 {
+		return nil, errors.New("segment step should be at least 1, but is " + fmt.Sprint(step))
+	}
+	return &CounterGenerator{
+		allocator: allocator,
+		step:      step,
+		minLength: minLength,
+		alphabet:  shuffleAlphabet(base62Alphabet, salt),
+	}, nil
+}
+
+func (g *CounterGenerator) Generate(string) (string, error) {
+	id, err := g.next()
+	if err != nil {
+		return "", err
+	}
+	return encodeShuffled(uint64(id), g.alphabet, g.minLength), nil
+}
+
+func (g *CounterGenerator) Collision() CollisionBehavior {
+	return CollisionFree
+}
+
+// next 返回下一个 ID，当前号段用完时向分配器申请新号段
+func (g *CounterGenerator) next() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cur >= g.max {
+		max, err := g.allocator.Allocate(g.step)
+		if err != nil {
+			return 0, fmt.Errorf("分配号段失败: %w", err)
+		}
+		g.cur, g.max = max-g.step, max
+	}
+	g.cur++
+	return g.cur, nil
+}
+
+// SnowflakeGenerator 基于雪花算法生成 ID 后转为 base62
+//
+// 固定 11 位左右，不同节点需配置不同的 nodeId（0~1023），时钟回拨时可能碰撞
+type SnowflakeGenerator struct {
+	node *snowflake.Node
+}
+
+func NewSnowflakeGenerator(nodeId int64) (*SnowflakeGenerator, error) {
+	node, err := snowflake.NewNode(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	return &SnowflakeGenerator{node: node}, nil
+}
+
+func (g *SnowflakeGenerator) Generate(string) (string, error) {
+	return encodeShuffled(uint64(g.node.Generate().Int64()), []byte(base62Alphabet), 0), nil
+}
+
+func (g *SnowflakeGenerator) Collision() CollisionBehavior {
+	return CollisionFree
+}
+
+// RandomGenerator 固定长度的随机短链接
+//
+// 取值空间为 62^length，已有 n 条短链接时单次碰撞概率约为 n/62^length
+type RandomGenerator struct {
+	length int
+}
+
+func NewRandomGenerator(length int) (RandomGenerator, error) {
+	if length < 1 {
+		return RandomGenerator{}, errors.New("random short code length should be at least 1, but is " + fmt.Sprint(length))
+	}
+	return RandomGenerator{length: length}, nil
+}
+
+func (g RandomGenerator) Generate(string) (string, error) {
+	max := big.NewInt(int64(len(base62Alphabet)))
+	buf := make([]byte, g.length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = base62Alphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
+
+func (g RandomGenerator) Collision() CollisionBehavior {
+	return CollisionProbable
+}
+
+// shuffleAlphabet 根据 salt 确定性地打乱字符集，salt 为空时保持原顺序
+func shuffleAlphabet(alphabet, salt string) []byte {
+	chars := []byte(alphabet)
+	if salt == "" {
+		return chars
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(salt))
+	seed := h.Sum64()
+	for i := len(chars) - 1; i > 0; i-- {
+		// xorshift64 生成伪随机序列
+		seed ^= seed << 13
+		seed ^= seed >> 7
+		seed ^= seed << 17
+		j := int(seed % uint64(i+1))
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return chars
+}
+
+// encodeShuffled 将 id 编码为短链接
+//
+// 首位字符由 id 决定，同时作为后续字符集的旋转偏移，因此编码仍然可逆（即唯一），
+// 但连续的 id 会落在完全不同的字符序列上。不足 minLength 时在高位补零
+func encodeShuffled(id uint64, alphabet []byte, minLength int) string {
+	size := uint64(len(alphabet))
+	offset := id % size
+	rotated := append(append([]byte{}, alphabet[offset:]...), alphabet[:offset]...)
+
+	var digits []byte
+	for n := id; ; n /= size {
+		digits = append(digits, rotated[n%size])
+		if n < size {
+			break
+		}
+	}
+	for len(digits)+1 < minLength {
+		digits = append(digits, rotated[0])
+	}
+
+	buf := make([]byte, 0, len(digits)+1)
+	buf = append(buf, alphabet[offset])
+	for i := len(digits) - 1; i >= 0; i-- {
+		buf = append(buf, digits[i])
+	}
+	return string(buf)
+}
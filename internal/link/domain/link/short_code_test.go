@@ -0,0 +1,210 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"sync"
+	"testing"
+)
+
+// memorySegmentAllocator 内存号段分配器，用于替代 Redis
+type memorySegmentAllocator struct {
+	mu    sync.Mutex
+	max   int64
+	calls int
+	err   error
+}
+
+func (a *memorySegmentAllocator) Allocate(step int64) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return 0, a.err
+	}
+	a.calls++
+	a.max += step
+	return a.max, nil
+}
+
+func TestCounterGenerator(t *testing.T) {
+	allocator := &memorySegmentAllocator{}
+	g, err := NewCounterGenerator(allocator, 100, 6, "salt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Collision() != CollisionFree {
+		t.Errorf("Collision() = %v, want %v", g.Collision(), CollisionFree)
+	}
+
+	const n = 10000
+	seen := make(map[string]struct{}, n)
+	var prev string
+	for i := 0; i < n; i++ {
+		code, err := g.Generate("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) < 6 {
+			t.Fatalf("code %q shorter than min length", code)
+		}
+		if _, ok := seen[code]; ok {
+			t.Fatalf("duplicate code %q at %d", code, i)
+		}
+		if prev != "" && code[0] == prev[0] {
+			t.Errorf("adjacent ids share prefix: %q, %q", prev, code)
+		}
+		seen[code] = struct{}{}
+		prev = code
+	}
+	if allocator.calls != n/100 {
+		t.Errorf("allocator called %d times, want %d", allocator.calls, n/100)
+	}
+}
+
+func TestCounterGenerator_AllocateError(t *testing.T) {
+	allocator := &memorySegmentAllocator{err: errors.New("redis down")}
+	g, err := NewCounterGenerator(allocator, 10, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = g.Generate(""); !errors.Is(err, allocator.err) {
+		t.Errorf("Generate() error = %v, want %v", err, allocator.err)
+	}
+}
+
+func TestShuffleAlphabet(t *testing.T) {
+	a := string(shuffleAlphabet(base62Alphabet, "salt"))
+	b := string(shuffleAlphabet(base62Alphabet, "salt"))
+	if a != b {
+		t.Errorf("shuffle is not deterministic: %q != %q", a, b)
+	}
+	if a == base62Alphabet {
+		t.Errorf("alphabet not shuffled")
+	}
+	if string(shuffleAlphabet(base62Alphabet, "")) != base62Alphabet {
+		t.Errorf("empty salt should keep alphabet")
+	}
+	set := make(map[rune]struct{})
+	for _, r := range a {
+		set[r] = struct{}{}
+	}
+	if len(set) != len(base62Alphabet) {
+		t.Errorf("shuffled alphabet lost characters: %q", a)
+	}
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	g, err := NewSnowflakeGenerator(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]struct{})
+	for i := 0; i < 10000; i++ {
+		code, err := g.Generate("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := seen[code]; ok {
+			t.Fatalf("duplicate code %q at %d", code, i)
+		}
+		seen[code] = struct{}{}
+	}
+	if _, err = NewSnowflakeGenerator(1024); err == nil {
+		t.Errorf("expected error for node id out of range")
+	}
+}
+
+func TestRandomGenerator(t *testing.T) {
+	g, err := NewRandomGenerator(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Collision() != CollisionProbable {
+		t.Errorf("Collision() = %v, want %v", g.Collision(), CollisionProbable)
+	}
+	for i := 0; i < 100; i++ {
+		code, err := g.Generate("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != 8 || !aliasPattern.MatchString(code) {
+			t.Fatalf("unexpected code %q", code)
+		}
+	}
+	if _, err = NewRandomGenerator(0); err == nil {
+		t.Errorf("expected error for zero length")
+	}
+}
+
+func TestHashGenerator(t *testing.T) {
+	g := NewHashGenerator()
+	code, err := g.Generate("https://github.com")
+	if err != nil || code == "" {
+		t.Errorf("Generate() = %q, %v", code, err)
+	}
+	if g.Collision() != CollisionProbable {
+		t.Errorf("Collision() = %v, want %v", g.Collision(), CollisionProbable)
+	}
+}
+
+// stubGenerator 依次返回 codes 中的短链接
+type stubGenerator struct {
+	codes     []string
+	collision CollisionBehavior
+	calls     int
+}
+
+func (g *stubGenerator) Generate(string) (string, error) {
+	code := g.codes[g.calls%len(g.codes)]
+	g.calls++
+	return code, nil
+}
+
+func (g *stubGenerator) Collision() CollisionBehavior {
+	return g.collision
+}
+
+func TestFactory_genUniqueShortUri(t *testing.T) {
+	taken := map[string]bool{"taken1": true, "taken2": true}
+	exists := func(code string) (bool, error) { return taken[code], nil }
+
+	tests := []struct {
+		name      string
+		codes     []string
+		collision CollisionBehavior
+		want      string
+		wantErr   error
+		wantCalls int
+	}{
+		{"probable first free", []string{"free"}, CollisionProbable, "free", nil, 1},
+		{"probable retry", []string{"taken1", "taken2", "free"}, CollisionProbable, "free", nil, 3},
+		{"probable exhausted", []string{"taken1"}, CollisionProbable, "", errno.LinkTooManyAttempts, 3},
+		{"free first free", []string{"free"}, CollisionFree, "free", nil, 1},
+		{"free conflict fails fast", []string{"taken1", "free"}, CollisionFree, "", errno.LinkAlreadyExists, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &stubGenerator{codes: tt.codes, collision: tt.collision}
+			f := Factory{fc: FactoryConfig{Generator: g}}
+			got, err := f.genUniqueShortUri("https://github.com", 3, exists)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("genUniqueShortUri() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+			if g.calls != tt.wantCalls {
+				t.Errorf("Generate() calls = %d, want %d", g.calls, tt.wantCalls)
+			}
+		})
+	}
+
+	// 计数生成器天然唯一，第一次生成的短链接被自定义短链接占用时不再重试
+	g, _ := NewCounterGenerator(&memorySegmentAllocator{}, 10, 0, "")
+	f := Factory{fc: FactoryConfig{Generator: g}}
+	attempts := 0
+	shortUri, err := f.genUniqueShortUri("", 3, func(string) (bool, error) {
+		attempts++
+		return true, nil
+	})
+	if !errors.Is(err, errno.LinkAlreadyExists) || shortUri != "" || attempts != 1 {
+		t.Errorf("genUniqueShortUri() = %q, %v after %d attempts", shortUri, err, attempts)
+	}
+}
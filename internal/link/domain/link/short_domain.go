@@ -0,0 +1,177 @@
+package link
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/toolkit"
+	"strings"
+	"time"
+)
+
+// DomainStatus 自定义域名的验证状态
+type DomainStatus string
+
+const (
+	// DomainUnverified 已注册，尚未完成所有权验证
+	DomainUnverified DomainStatus = "unverified"
+	// DomainVerified 已通过所有权验证
+	DomainVerified DomainStatus = "verified"
+	// DomainFailed 曾经通过验证，之后连续多次验证失败
+	DomainFailed DomainStatus = "failed"
+)
+
+const (
+	// DomainVerifyRecordPrefix DNS 验证记录的前缀，TXT 记录 _shortlink-verify.<域名> 的值为验证令牌
+	DomainVerifyRecordPrefix = "_shortlink-verify."
+)
+
+// ShortDomain 用户注册的自定义短链接域名
+//
+// 不同域名下的短链接相互独立，a.brand1.io/x 和 b.brand2.io/x 可以同时存在
+type ShortDomain struct {
+	// 域名 可以带端口 统一转为小写
+	host string
+	// 注册域名的用户
+	owner  string
+	status DomainStatus
+	// 是否通过 https 访问
+	useTLS bool
+	// 所有权验证令牌 注册时生成
+	verifyToken string
+	// 最近一次验证的时间
+	checkedAt *time.Time
+	// 通过验证后连续验证失败的次数
+	failures int
+}
+
+func NewShortDomain(host, owner string, useTLS bool) (*ShortDomain, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if !toolkit.IsValidDomain(host) {
+		return nil, errno.LinkInvalidDomain
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	return &ShortDomain{
+		host:        host,
+		owner:       owner,
+		status:      DomainUnverified,
+		useTLS:      useTLS,
+		verifyToken: hex.EncodeToString(token),
+	}, nil
+}
+
+func NewShortDomainFromDB(
+	host string,
+	owner string,
+	status DomainStatus,
+	useTLS bool,
+	verifyToken string,
+	checkedAt *time.Time,
+	failures int,
+) *ShortDomain {
+	return &ShortDomain{
+		host:        host,
+		owner:       owner,
+		status:      status,
+		useTLS:      useTLS,
+		verifyToken: verifyToken,
+		checkedAt:   checkedAt,
+		failures:    failures,
+	}
+}
+
+func (d ShortDomain) Host() string {
+	return d.host
+}
+
+func (d ShortDomain) Owner() string {
+	return d.owner
+}
+
+func (d ShortDomain) Status() DomainStatus {
+	return d.status
+}
+
+func (d ShortDomain) UseTLS() bool {
+	return d.useTLS
+}
+
+func (d ShortDomain) VerifyToken() string {
+	return d.verifyToken
+}
+
+func (d ShortDomain) CheckedAt() *time.Time {
+	return d.checkedAt
+}
+
+func (d ShortDomain) Failures() int {
+	return d.failures
+}
+
+// Hostname 不带端口的域名
+func (d ShortDomain) Hostname() string {
+	if host, _, err := net.SplitHostPort(d.host); err == nil {
+		return host
+	}
+	return d.host
+}
+
+// VerifyRecord 用于验证所有权的 DNS TXT 记录名
+func (d ShortDomain) VerifyRecord() string {
+	return DomainVerifyRecordPrefix + d.Hostname()
+}
+
+// RecordVerification 记录一次验证结果，返回域名是否由验证通过变为验证失败
+//
+// 验证失败的域名不能再创建短链接，已有的短链接需要停用。为避免偶发的解析异常停用所有短链接，
+// 已验证的域名连续 maxFailures 次验证失败才会撤销；尚未通过验证的域名验证失败时保持原状态
+func (d *ShortDomain) RecordVerification(passed bool, now time.Time, maxFailures int) (revoked bool) {
+	d.checkedAt = &now
+	if passed {
+		d.status = DomainVerified
+		d.failures = 0
+		return false
+	}
+	if d.status != DomainVerified {
+		return false
+	}
+	d.failures++
+	if d.failures < maxFailures {
+		return false
+	}
+	d.status = DomainFailed
+	return true
+}
+
+// OwnedBy 是否为指定用户注册的域名
+func (d ShortDomain) OwnedBy(username string) bool {
+	return username != "" && d.owner == username
+}
+
+// Usable 是否可以在该域名下创建短链接，只有通过验证的域名可以使用
+func (d ShortDomain) Usable() bool {
+	return d.status == DomainVerified
+}
+
+// Key 短链接在缓存和布隆过滤器中的唯一标识
+//
+// 默认域名下的短链接直接使用 shortUri，与引入自定义域名之前保持一致
+func Key(domain, shortUri string) string {
+	if domain == "" {
+		return shortUri
+	}
+	return domain + "/" + shortUri
+}
+
+// shortUrl 拼接完整短链接
+func shortUrl(host string, useTLS bool, shortUri string) string {
+	if useTLS {
+		return fmt.Sprintf("https://%s/%s", host, shortUri)
+	}
+	return fmt.Sprintf("http://%s/%s", host, shortUri)
+}
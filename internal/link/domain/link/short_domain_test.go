@@ -0,0 +1,81 @@
+package link
+
+import (
+	"errors"
+	"shortlink/internal/base/errno"
+	"testing"
+	"time"
+)
+
+func TestNewShortDomain(t *testing.T) {
+	d, err := NewShortDomain(" A.Brand1.io ", "alice", true)
+	if err != nil {
+		t.Fatalf("NewShortDomain() error = %v", err)
+	}
+	if d.Host() != "a.brand1.io" || d.Status() != DomainUnverified || d.VerifyToken() == "" {
+		t.Errorf("NewShortDomain() = %+v", *d)
+	}
+	if d.Usable() {
+		t.Errorf("unverified domain should not be usable")
+	}
+	if !d.OwnedBy("alice") || d.OwnedBy("bob") || d.OwnedBy("") {
+		t.Errorf("OwnedBy() mismatch for owner %q", d.Owner())
+	}
+
+	if _, err = NewShortDomain("https://a.brand1.io", "alice", true); !errors.Is(err, errno.LinkInvalidDomain) {
+		t.Errorf("NewShortDomain() with scheme = %v, want %v", err, errno.LinkInvalidDomain)
+	}
+
+}
+
+func TestShortDomain_RecordVerification(t *testing.T) {
+	now := time.Now()
+	d := NewShortDomainFromDB("a.brand1.io:8443", "alice", DomainUnverified, true, "token", nil, 0)
+
+	steps := []struct {
+		name        string
+		passed      bool
+		wantRevoked bool
+		wantStatus  DomainStatus
+		wantFails   int
+	}{
+		{"unverified stays unverified", false, false, DomainUnverified, 0},
+		{"unverified -> verified", true, false, DomainVerified, 0},
+		{"first failure within grace", false, false, DomainVerified, 1},
+		{"second failure within grace", false, false, DomainVerified, 2},
+		{"success resets failures", true, false, DomainVerified, 0},
+		{"failure 1", false, false, DomainVerified, 1},
+		{"failure 2", false, false, DomainVerified, 2},
+		{"failure 3 revokes", false, true, DomainFailed, 3},
+		{"failed stays failed", false, false, DomainFailed, 3},
+		{"failed -> verified", true, false, DomainVerified, 0},
+	}
+	for _, s := range steps {
+		revoked := d.RecordVerification(s.passed, now, 3)
+		if revoked != s.wantRevoked || d.Status() != s.wantStatus || d.Failures() != s.wantFails {
+			t.Fatalf("%s: revoked = %v, status = %s, failures = %d, want %v, %s, %d",
+				s.name, revoked, d.Status(), d.Failures(), s.wantRevoked, s.wantStatus, s.wantFails)
+		}
+	}
+	if d.CheckedAt() == nil || !d.CheckedAt().Equal(now) {
+		t.Errorf("CheckedAt() = %v, want %v", d.CheckedAt(), now)
+	}
+
+	// maxFailures 不大于 1 时第一次失败即撤销
+	if revoked := d.RecordVerification(false, now, 0); !revoked || d.Usable() {
+		t.Errorf("verified -> failed without grace: revoked = %v, status = %s", revoked, d.Status())
+	}
+
+	if got := d.VerifyRecord(); got != "_shortlink-verify.a.brand1.io" {
+		t.Errorf("VerifyRecord() = %q", got)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key("", "x"); got != "x" {
+		t.Errorf("Key() on default domain = %q, want %q", got, "x")
+	}
+	if Key("a.brand1.io", "x") == Key("b.brand2.io", "x") {
+		t.Errorf("Key() should differ across domains")
+	}
+}
@@ -0,0 +1,72 @@
+package link
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"shortlink/internal/base/errno"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTags 单个短链接最多允许的标签数量
+	maxTags = 10
+	// maxTagLength 单个标签的最大长度
+	maxTagLength = 32
+)
+
+// Tags 短链接标签，用于分类和筛选
+type Tags []string
+
+// Normalize 去除首尾空白、统一为小写并去重，保持原有顺序
+func (ts Tags) Normalize() (Tags, error) {
+	if len(ts) == 0 {
+		return nil, nil
+	}
+	res := make(Tags, 0, len(ts))
+	seen := make(map[string]struct{}, len(ts))
+	for _, t := range ts {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || utf8.RuneCountInString(t) > maxTagLength || strings.ContainsAny(t, ",\"") {
+			return nil, errno.LinkInvalidTags
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		res = append(res, t)
+	}
+	if len(res) > maxTags {
+		return nil, errno.LinkInvalidTags
+	}
+	return res, nil
+}
+
+// Scan implements the sql.Scanner interface
+func (ts *Tags) Scan(value interface{}) error {
+	if value == nil {
+		*ts = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("invalid data type for Tags")
+	}
+	return json.Unmarshal(bytes, ts)
+}
+
+// Value implements the driver.Valuer interface
+//
+// 没有标签时保存为空数组而不是 NULL，便于使用 @> 筛选
+func (ts Tags) Value() (driver.Value, error) {
+	if len(ts) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(ts)
+}
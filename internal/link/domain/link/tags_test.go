@@ -0,0 +1,54 @@
+package link
+
+import (
+	"errors"
+	"reflect"
+	"shortlink/internal/base/errno"
+	"strings"
+	"testing"
+)
+
+func TestTags_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    Tags
+		want    Tags
+		wantErr bool
+	}{
+		{"empty", nil, nil, false},
+		{"trim lower and dedupe", Tags{" Go ", "go", "营销"}, Tags{"go", "营销"}, false},
+		{"blank tag", Tags{"go", " "}, nil, true},
+		{"too long", Tags{strings.Repeat("a", maxTagLength+1)}, nil, true},
+		{"comma", Tags{"a,b"}, nil, true},
+		{"too many", Tags{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tags.Normalize()
+			if tt.wantErr {
+				if !errors.Is(err, errno.LinkInvalidTags) {
+					t.Errorf("Normalize() error = %v, want LinkInvalidTags", err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTags_ScanValue(t *testing.T) {
+	tags := Tags{"go", "营销"}
+	v, err := tags.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Tags
+	if err = got.Scan(v); err != nil || !reflect.DeepEqual(got, tags) {
+		t.Errorf("Scan(Value()) = %v, %v, want %v", got, err, tags)
+	}
+	if v, _ = Tags(nil).Value(); v != "[]" {
+		t.Errorf("Value() = %v, want []", v)
+	}
+}
@@ -0,0 +1,150 @@
+package link
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"shortlink/internal/base/errno"
+)
+
+// QueryAction 短链接上携带的查询参数如何合并到跳转目标
+type QueryAction string
+
+const (
+	// QueryDrop 丢弃，默认行为
+	QueryDrop QueryAction = "drop"
+	// QueryForward 转发，跳转目标中已存在同名参数时保留目标的值
+	QueryForward QueryAction = "forward"
+	// QueryOverride 转发并覆盖跳转目标中的同名参数
+	QueryOverride QueryAction = "override"
+)
+
+func (a QueryAction) valid() bool {
+	return a == "" || a == QueryDrop || a == QueryForward || a == QueryOverride
+}
+
+// Utm 追加到跳转目标上的 UTM 参数，跳转目标中已存在的同名参数不会被替换
+type Utm struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+func (u Utm) values() [][2]string {
+	return [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	}
+}
+
+// QueryPolicy 查询参数透传策略
+type QueryPolicy struct {
+	// 未单独配置的参数的处理方式 为空时丢弃
+	Default QueryAction `json:"default,omitempty"`
+	// 按参数名单独配置的处理方式
+	Keys map[string]QueryAction `json:"keys,omitempty"`
+}
+
+func (p QueryPolicy) action(key string) QueryAction {
+	if a, ok := p.Keys[key]; ok && a != "" {
+		return a
+	}
+	if p.Default == "" {
+		return QueryDrop
+	}
+	return p.Default
+}
+
+// UrlParams 跳转时对目标链接查询参数的处理
+type UrlParams struct {
+	Utm   Utm         `json:"utm"`
+	Query QueryPolicy `json:"query"`
+}
+
+// Apply 将 UTM 参数和短链接上携带的查询参数合并到跳转目标
+//
+// 先追加 UTM 参数，再按透传策略处理 incoming，因此 override 的参数可以覆盖 UTM
+func (p *UrlParams) Apply(target string, incoming url.Values) (string, error) {
+	if p == nil {
+		return target, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	changed := false
+
+	for _, kv := range p.Utm.values() {
+		if kv[1] != "" && !query.Has(kv[0]) {
+			query.Set(kv[0], kv[1])
+			changed = true
+		}
+	}
+
+	for key, values := range incoming {
+		switch p.Query.action(key) {
+		case QueryForward:
+			if !query.Has(key) {
+				query[key] = values
+				changed = true
+			}
+		case QueryOverride:
+			query[key] = values
+			changed = true
+		}
+	}
+
+	if !changed {
+		return target, nil
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (p *UrlParams) validate() error {
+	if p == nil {
+		return nil
+	}
+	if !p.Query.Default.valid() {
+		return errno.LinkInvalidUrlParams
+	}
+	for key, a := range p.Query.Keys {
+		if key == "" || !a.valid() {
+			return errno.LinkInvalidUrlParams
+		}
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (p *UrlParams) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("invalid data type for UrlParams")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value implements the driver.Valuer interface
+func (p *UrlParams) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
@@ -0,0 +1,120 @@
+package link
+
+import (
+	"errors"
+	"net/url"
+	"shortlink/internal/base/errno"
+	"testing"
+)
+
+func TestUrlParams_Apply(t *testing.T) {
+	utm := Utm{Source: "newsletter", Medium: "email"}
+	tests := []struct {
+		name     string
+		params   *UrlParams
+		target   string
+		incoming url.Values
+		want     string
+	}{
+		{
+			name:     "nil params keep target",
+			params:   nil,
+			target:   "https://example.com/a?x=1",
+			incoming: url.Values{"ref": {"twitter"}},
+			want:     "https://example.com/a?x=1",
+		},
+		{
+			name:     "drop by default",
+			params:   &UrlParams{},
+			target:   "https://example.com/a?x=1",
+			incoming: url.Values{"ref": {"twitter"}},
+			want:     "https://example.com/a?x=1",
+		},
+		{
+			name:   "append utm",
+			params: &UrlParams{Utm: utm},
+			target: "https://example.com/a",
+			want:   "https://example.com/a?utm_medium=email&utm_source=newsletter",
+		},
+		{
+			name:   "existing utm wins",
+			params: &UrlParams{Utm: utm},
+			target: "https://example.com/a?utm_source=site",
+			want:   "https://example.com/a?utm_medium=email&utm_source=site",
+		},
+		{
+			name:     "forward all",
+			params:   &UrlParams{Query: QueryPolicy{Default: QueryForward}},
+			target:   "https://example.com/a?x=1",
+			incoming: url.Values{"ref": {"twitter"}, "x": {"2"}},
+			want:     "https://example.com/a?ref=twitter&x=1",
+		},
+		{
+			name:     "override single key",
+			params:   &UrlParams{Query: QueryPolicy{Default: QueryForward, Keys: map[string]QueryAction{"x": QueryOverride}}},
+			target:   "https://example.com/a?x=1",
+			incoming: url.Values{"x": {"2"}},
+			want:     "https://example.com/a?x=2",
+		},
+		{
+			name:     "drop single key",
+			params:   &UrlParams{Query: QueryPolicy{Default: QueryForward, Keys: map[string]QueryAction{"token": QueryDrop}}},
+			target:   "https://example.com/a",
+			incoming: url.Values{"ref": {"twitter"}, "token": {"secret"}},
+			want:     "https://example.com/a?ref=twitter",
+		},
+		{
+			name:     "forward single key",
+			params:   &UrlParams{Query: QueryPolicy{Keys: map[string]QueryAction{"ref": QueryForward}}},
+			target:   "https://example.com/a",
+			incoming: url.Values{"ref": {"twitter"}, "other": {"1"}},
+			want:     "https://example.com/a?ref=twitter",
+		},
+		{
+			name:     "override utm",
+			params:   &UrlParams{Utm: utm, Query: QueryPolicy{Keys: map[string]QueryAction{"utm_source": QueryOverride}}},
+			target:   "https://example.com/a",
+			incoming: url.Values{"utm_source": {"twitter"}},
+			want:     "https://example.com/a?utm_medium=email&utm_source=twitter",
+		},
+		{
+			name:     "keep fragment",
+			params:   &UrlParams{Query: QueryPolicy{Default: QueryForward}},
+			target:   "https://example.com/a#top",
+			incoming: url.Values{"ref": {"twitter"}},
+			want:     "https://example.com/a?ref=twitter#top",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.Apply(tt.target, tt.incoming)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUrlParams_validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *UrlParams
+		want   error
+	}{
+		{"nil", nil, nil},
+		{"valid", &UrlParams{Query: QueryPolicy{Default: QueryForward, Keys: map[string]QueryAction{"x": QueryDrop}}}, nil},
+		{"invalid default", &UrlParams{Query: QueryPolicy{Default: "keep"}}, errno.LinkInvalidUrlParams},
+		{"invalid key action", &UrlParams{Query: QueryPolicy{Keys: map[string]QueryAction{"x": "keep"}}}, errno.LinkInvalidUrlParams},
+		{"empty key", &UrlParams{Query: QueryPolicy{Keys: map[string]QueryAction{"": QueryForward}}}, errno.LinkInvalidUrlParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.params.validate(); !errors.Is(err, tt.want) {
+				t.Errorf("validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
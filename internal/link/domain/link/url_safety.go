@@ -0,0 +1,143 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"shortlink/internal/base/errno"
+	"slices"
+	"strings"
+	"time"
+)
+
+// unsafeErrors 跳转目标被标记为不安全时返回的错误，命中的已有短链接会被禁用
+var unsafeErrors = []error{errno.LinkUnsafeScheme, errno.LinkUnsafeUrl, errno.LinkRedirectLoop}
+
+// unsafeSchemes 可以在浏览器中执行代码或读取本地内容的协议
+var unsafeSchemes = []string{"javascript", "data", "vbscript", "file"}
+
+// UrlSafetyChecker 检查跳转目标是否安全
+//
+// 被标记为不安全时返回 errno.LinkUnsafeUrl 等错误，可以用 IsUnsafe 判断，其他错误表示暂时无法完成检查
+type UrlSafetyChecker interface {
+	Check(ctx context.Context, rawUrl string) error
+}
+
+// IsUnsafe 错误是否表示跳转目标被标记为不安全
+func IsUnsafe(err error) bool {
+	for _, e := range unsafeErrors {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// UrlSafetyChain 按顺序执行检查，任意一个不通过即返回，本地检查应放在需要请求外部服务的检查之前
+type UrlSafetyChain []UrlSafetyChecker
+
+func (c UrlSafetyChain) Check(ctx context.Context, rawUrl string) error {
+	for _, checker := range c {
+		if err := checker.Check(ctx, rawUrl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// destinationsCheckTimeout 检查单个短链接所有跳转目标的总超时时间
+//
+// 路由规则和分流目标较多时，逐个请求外部服务可能长时间阻塞创建和修改请求
+const destinationsCheckTimeout = 15 * time.Second
+
+// CheckDestinations 检查短链接的所有跳转目标，相同的跳转目标只检查一次
+func CheckDestinations(ctx context.Context, checker UrlSafetyChecker, urls []string) error {
+	ctx, cancel := context.WithTimeout(ctx, destinationsCheckTimeout)
+	defer cancel()
+
+	checked := make(map[string]struct{}, len(urls))
+	for _, u := range urls {
+		if _, ok := checked[u]; ok {
+			continue
+		}
+		checked[u] = struct{}{}
+		if err := checker.Check(ctx, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DestinationsChanged 跳转目标是否有变化
+func DestinationsChanged(before, after []string) bool {
+	return !slices.Equal(before, after)
+}
+
+// Destinations 短链接的所有跳转目标，包括原始链接、路由规则、分流目标和备用链接
+func Destinations(originalUrl string, rules RoutingRules, variants Variants, fallbackUrl string) []string {
+	urls := []string{originalUrl}
+	for _, r := range rules {
+		urls = append(urls, r.Target)
+	}
+	for _, v := range variants {
+		urls = append(urls, v.Url)
+	}
+	if fallbackUrl != "" {
+		urls = append(urls, fallbackUrl)
+	}
+	return urls
+}
+
+// LinkTargets 短链接及其跳转目标，用于批量检查已有短链接
+type LinkTargets struct {
+	Identifier
+	Urls []string
+}
+
+// SchemeChecker 拒绝 javascript:、data: 等协议的跳转目标
+type SchemeChecker struct{}
+
+func (SchemeChecker) Check(_ context.Context, rawUrl string) error {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return errno.LinkInvalidOriginalUrl
+	}
+	// url.Parse 会将协议转为小写
+	for _, s := range unsafeSchemes {
+		if u.Scheme == s {
+			return errno.LinkUnsafeScheme
+		}
+	}
+	return nil
+}
+
+// Blocklist 禁止跳转的域名或链接前缀
+//
+// 不带路径的条目同时禁止其子域名，带路径的条目如 example.com/phishing 按路径前缀匹配
+type Blocklist []string
+
+// Blocks 跳转目标是否在黑名单内
+func (b Blocklist) Blocks(rawUrl string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for _, v := range b {
+		h, p, hasPath := strings.Cut(v, "/")
+		h = strings.TrimPrefix(strings.ToLower(h), "www.")
+		if h == "" {
+			continue
+		}
+		if !hasPath {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				return true
+			}
+			continue
+		}
+		if host == h && strings.HasPrefix(u.EscapedPath(), "/"+p) {
+			return true
+		}
+	}
+	return false
+}
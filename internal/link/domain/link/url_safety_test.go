@@ -0,0 +1,78 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"shortlink/internal/base/errno"
+	"slices"
+	"testing"
+)
+
+func TestSchemeChecker_Check(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://example.com", nil},
+		{"javascript:alert(1)", errno.LinkUnsafeScheme},
+		{" JavaScript:alert(1)", errno.LinkUnsafeScheme},
+		{"data:text/html;base64,PHNjcmlwdD4=", errno.LinkUnsafeScheme},
+		{"java\tscript:alert(1)", errno.LinkInvalidOriginalUrl},
+	}
+	for _, tt := range tests {
+		if err := (SchemeChecker{}).Check(context.Background(), tt.url); !errors.Is(err, tt.want) {
+			t.Errorf("Check(%q) = %v, want %v", tt.url, err, tt.want)
+		}
+	}
+}
+
+func TestBlocklist_Blocks(t *testing.T) {
+	b := Blocklist{"evil.com", "example.com/phishing"}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://evil.com/a", true},
+		{"https://www.login.EVIL.com", true},
+		{"https://notevil.com", false},
+		{"https://example.com/phishing/login", true},
+		{"https://example.com/about", false},
+		{"https://sub.example.com/phishing", false},
+		{"mailto:someone@evil.com", false},
+	}
+	for _, tt := range tests {
+		if got := b.Blocks(tt.url); got != tt.want {
+			t.Errorf("Blocks(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+// countingChecker 记录每个跳转目标的检查次数
+type countingChecker map[string]int
+
+func (c countingChecker) Check(ctx context.Context, rawUrl string) error {
+	c[rawUrl]++
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("check should have a deadline")
+	}
+	return nil
+}
+
+func TestCheckDestinations(t *testing.T) {
+	c := countingChecker{}
+	urls := Destinations("https://a.example", RoutingRules{{Device: "Mobile", Target: "https://a.example"}},
+		Variants{{Name: "x", Url: "https://b.example", Weight: 1}}, "https://a.example")
+	if err := CheckDestinations(context.Background(), c, urls); err != nil {
+		t.Fatal(err)
+	}
+	if c["https://a.example"] != 1 || c["https://b.example"] != 1 {
+		t.Errorf("each destination should be checked once, got %v", c)
+	}
+
+	if DestinationsChanged(urls, slices.Clone(urls)) {
+		t.Error("same destinations should not be changed")
+	}
+	if !DestinationsChanged(urls, urls[:1]) {
+		t.Error("removed destination should be changed")
+	}
+}
@@ -0,0 +1,96 @@
+package link
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/toolkit"
+)
+
+// maxVariants 单个短链接最多允许的跳转目标数量
+const maxVariants = 10
+
+// Variant A/B 测试中的一个跳转目标
+type Variant struct {
+	// 名称 用于统计区分，同一短链接内唯一
+	Name string `json:"name"`
+	// 跳转目标
+	Url string `json:"url"`
+	// 权重 按权重比例分配流量
+	Weight int `json:"weight"`
+}
+
+// Variants 按权重分流的多个跳转目标
+type Variants []Variant
+
+// Pick 根据访客标识选出跳转目标
+//
+// 同一访客在权重不变的情况下总会落在同一个目标上，从而保证实验分组的稳定
+func (vs Variants) Pick(shortUri, visitorId string) (Variant, bool) {
+	total := 0
+	for _, v := range vs {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return Variant{}, false
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(shortUri + ":" + visitorId))
+	point := int(h.Sum64() % uint64(total))
+	for _, v := range vs {
+		if point < v.Weight {
+			return v, true
+		}
+		point -= v.Weight
+	}
+	return Variant{}, false
+}
+
+func (vs Variants) validate() error {
+	if len(vs) == 0 {
+		return nil
+	}
+	if len(vs) < 2 || len(vs) > maxVariants {
+		return errno.LinkInvalidVariant
+	}
+	names := make(map[string]struct{}, len(vs))
+	for _, v := range vs {
+		if v.Name == "" || v.Weight <= 0 || !toolkit.IsValidUrl(v.Url) {
+			return errno.LinkInvalidVariant
+		}
+		if _, ok := names[v.Name]; ok {
+			return errno.LinkInvalidVariant
+		}
+		names[v.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Scan implements the sql.Scanner interface
+func (vs *Variants) Scan(value interface{}) error {
+	if value == nil {
+		*vs = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("invalid data type for Variants")
+	}
+	return json.Unmarshal(bytes, vs)
+}
+
+// Value implements the driver.Valuer interface
+func (vs Variants) Value() (driver.Value, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(vs)
+}
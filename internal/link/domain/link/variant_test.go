@@ -0,0 +1,81 @@
+package link
+
+import (
+	"errors"
+	"math"
+	"shortlink/internal/base/errno"
+	"strconv"
+	"testing"
+)
+
+func TestVariants_Pick(t *testing.T) {
+	vs := Variants{
+		{Name: "a", Url: "https://example.com/a", Weight: 3},
+		{Name: "b", Url: "https://example.com/b", Weight: 1},
+	}
+
+	// 同一访客总是命中同一个目标
+	first, ok := vs.Pick("abc", "visitor")
+	if !ok {
+		t.Fatal("Pick() returned no variant")
+	}
+	for i := 0; i < 10; i++ {
+		if v, _ := vs.Pick("abc", "visitor"); v != first {
+			t.Fatalf("Pick() is not sticky: %v != %v", v, first)
+		}
+	}
+
+	// 流量大致按权重分配
+	const n = 20000
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		v, _ := vs.Pick("abc", strconv.Itoa(i))
+		counts[v.Name]++
+	}
+	if ratio := float64(counts["a"]) / n; math.Abs(ratio-0.75) > 0.03 {
+		t.Errorf("variant a ratio = %.3f, want about 0.75", ratio)
+	}
+
+	if _, ok = (Variants{}).Pick("abc", "visitor"); ok {
+		t.Errorf("empty variants should not pick")
+	}
+}
+
+func TestVariants_validate(t *testing.T) {
+	tests := []struct {
+		name string
+		vs   Variants
+		want error
+	}{
+		{"empty", nil, nil},
+		{"single", Variants{{Name: "a", Url: "https://a.com", Weight: 1}}, errno.LinkInvalidVariant},
+		{"zero weight", Variants{{Name: "a", Url: "https://a.com", Weight: 1}, {Name: "b", Url: "https://b.com"}}, errno.LinkInvalidVariant},
+		{"duplicate name", Variants{{Name: "a", Url: "https://a.com", Weight: 1}, {Name: "a", Url: "https://b.com", Weight: 1}}, errno.LinkInvalidVariant},
+		{"valid", Variants{{Name: "a", Url: "https://a.com", Weight: 1}, {Name: "b", Url: "https://b.com", Weight: 1}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.vs.validate(); !errors.Is(err, tt.want) {
+				t.Errorf("validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheValue_Resolve(t *testing.T) {
+	c := CacheValue{
+		OriginalUrl:  "https://example.com",
+		RoutingRules: RoutingRules{{Device: "Mobile", Target: "https://m.example.com"}},
+		Variants: Variants{
+			{Name: "a", Url: "https://example.com/a", Weight: 1},
+			{Name: "b", Url: "https://example.com/b", Weight: 1},
+		},
+	}
+
+	if target, variant := c.Resolve("abc", "visitor", Visitor{Device: "Mobile"}); target != "https://m.example.com" || variant != "" {
+		t.Errorf("routing rule should take precedence, got %q, %q", target, variant)
+	}
+	if _, variant := c.Resolve("abc", "visitor", Visitor{Device: "PC"}); variant == "" {
+		t.Errorf("expected a variant for unmatched visitor")
+	}
+}
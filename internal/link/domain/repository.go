@@ -3,15 +3,40 @@ package domain
 import (
 	"context"
 	"shortlink/internal/link/domain/link"
+	"time"
 )
 
 type Repository interface {
-	// ShortUriExists 短链接是否存在
-	ShortUriExists(ctx context.Context, shortUrl string) (bool, error)
+	// ShortUriExists 短链接是否存在 key 由 link.Key 生成
+	ShortUriExists(ctx context.Context, key string) (bool, error)
 
 	// CountLinksByGid 获取分组下的短链接数量
 	CountLinksByGid(ctx context.Context, gid string) (int, error)
 
+	// GetGroupSetting 获取分组默认配置，未配置时返回空配置
+	GetGroupSetting(ctx context.Context, gid string) (*link.GroupSetting, error)
+
+	// SaveGroupSetting 保存分组默认配置
+	SaveGroupSetting(ctx context.Context, setting *link.GroupSetting) error
+
+	// GetShortDomain 获取自定义域名
+	GetShortDomain(ctx context.Context, host string) (*link.ShortDomain, error)
+
+	// CreateShortDomain 注册自定义域名
+	CreateShortDomain(ctx context.Context, domain *link.ShortDomain) error
+
+	// RemoveShortDomain 删除自定义域名，域名下仍有短链接时不允许删除
+	RemoveShortDomain(ctx context.Context, host string) error
+
+	// ListAllShortDomains 获取所有自定义域名，用于定期验证
+	ListAllShortDomains(ctx context.Context) ([]*link.ShortDomain, error)
+
+	// SaveShortDomainVerification 保存域名的验证结果
+	SaveShortDomainVerification(ctx context.Context, domain *link.ShortDomain) error
+
+	// DisableDomainLinks 停用域名下的短链接，返回状态发生变化的短链接
+	DisableDomainLinks(ctx context.Context, host string) ([]link.StatusChange, error)
+
 	// CreateLink 创建短链接
 	CreateLink(ctx context.Context, lk *link.Link) error
 
@@ -21,6 +46,7 @@ type Repository interface {
 	// UpdateLink 更新短链接
 	UpdateLink(
 		ctx context.Context,
+		domain string,
 		shortUri string,
 		updateFn func(ctx context.Context, link *link.Link) (*link.Link, error),
 	) error
@@ -42,4 +68,36 @@ type Repository interface {
 		ctx context.Context,
 		id link.Identifier,
 	) error
+
+	// BulkUpdateLinks 对多个短链接执行同一操作，ids 中的 Gid 可以为空，由仓库查询所在分组
+	//
+	// 按分组分别在事务中执行，返回与 ids 一一对应的结果；返回错误时数据库已经提交，只是缓存更新失败
+	BulkUpdateLinks(ctx context.Context, ids []link.Identifier, action link.BulkAction) ([]link.BulkItemResult, error)
+
+	// ActivatePendingLinks 激活已到开始时间的短链接，返回状态发生变化的短链接
+	ActivatePendingLinks(ctx context.Context, now time.Time) ([]link.StatusChange, error)
+
+	// ExpireOverdueLinks 将超过结束时间的短链接置为过期，返回状态发生变化的短链接
+	ExpireOverdueLinks(ctx context.Context, now time.Time) ([]link.StatusChange, error)
+
+	// ScanAvailableLinks 分批遍历可以访问和未到开始时间的短链接及其跳转目标
+	ScanAvailableLinks(ctx context.Context, fn func([]link.LinkTargets) error) error
+
+	// ForbidLinks 禁用跳转目标不安全的短链接，返回状态发生变化的短链接
+	ForbidLinks(ctx context.Context, ids []link.Identifier) ([]link.StatusChange, error)
+
+	// SampleActiveLinks 从每个分组中选取最久没有检查的可访问短链接
+	SampleActiveLinks(ctx context.Context, perGroup int) ([]link.HealthTarget, error)
+
+	// SaveLinkMetadata 保存跳转目标页面的元信息，用户填写了描述时不覆盖
+	SaveLinkMetadata(ctx context.Context, id link.Identifier, metadata link.Metadata) error
+
+	// SaveHealthCheck 保存跳转目标的健康检查结果
+	SaveHealthCheck(ctx context.Context, id link.Identifier, check link.HealthCheck) error
+
+	// GetJob 获取后台任务
+	GetJob(ctx context.Context, id string) (*link.Job, error)
+
+	// SaveJob 保存后台任务及其进度
+	SaveJob(ctx context.Context, job *link.Job) error
 }
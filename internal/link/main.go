@@ -13,11 +13,17 @@ import (
 	"shortlink/internal/base/logging"
 	"shortlink/internal/base/mq"
 	"shortlink/internal/base/server"
+	"shortlink/internal/base/server/middleware/auth"
 	"shortlink/internal/base/shutdown"
+	linklistener "shortlink/internal/link/app/listener"
 	"shortlink/internal/link/common/config"
+	"shortlink/internal/link/common/constant"
+	"shortlink/internal/link/domain/event"
 	linkservice "shortlink/internal/link/service"
 	linktrigger "shortlink/internal/link/trigger/http"
+	linkschedule "shortlink/internal/link/trigger/schedule"
 	"syscall"
+	"time"
 )
 
 func main() {
@@ -34,23 +40,53 @@ func main() {
 	rmqclient.ResetLogger()
 
 	// 初始化外部依赖
-	db := database.ConnectToDatabase()                                             // Postgresql
-	rdb := cache.ConnectToRedis()                                                  // Redis
-	locker := lock.NewRedisLock(rdb)                                               // DistributedLock - Redis
-	eventBus := mq.NewRocketMqBasedEventBus(context.Background(), mq.ProducerMode) // EventBus
+	db := database.ConnectToDatabase()                                        // Postgresql
+	rdb := cache.ConnectToRedis()                                             // Redis
+	locker := lock.NewRedisLock(rdb)                                          // DistributedLock - Redis
+	eventBus := mq.NewRocketMqBasedEventBus(context.Background(), mq.MixMode) // EventBus
 
 	// 创建应用服务
 	shortLinkApp := linkservice.NewLinkApplication(db, rdb, locker, eventBus)
 
+	// 事件订阅
+	createdTag := event.LinkCreatedTag
+	if err := eventBus.Subscribe(constant.AppShortLinkTopic, &createdTag,
+		linklistener.NewFetchLinkMetadataListener(shortLinkApp)); err != nil {
+		panic("failed to subscribe link created event: " + err.Error())
+	}
+	// 所有监听器注册后再开始接收消息
+	if err := eventBus.Start(context.Background()); err != nil {
+		panic("failed to start event bus: " + err.Error())
+	}
+
+	// 不需要鉴权的接口 短链接跳转、解锁和预览
+	excludes := []string{"/:shortUri"}
+
 	shutdownServer := server.RunHttpServer(func(router fiber.Router) {
+		router.Use(auth.New(rdb, excludes, linktrigger.ApiKeyRoutes()...)) // 鉴权中间件
 		server.NewUriTitleApi(router)
 		linktrigger.NewLinkApi(shortLinkApp, router)
 		linktrigger.NewLinkRecycleBinApi(shortLinkApp, router)
 	})
 
+	// 定时任务
+	shutdownScheduler := linkschedule.RunScheduler(locker,
+		linkschedule.NewLinkStatusJob(shortLinkApp,
+			time.Duration(config.Get().AppLink.Schedule.LinkStatusInterval)*time.Second),
+		linkschedule.NewDomainVerifyJob(shortLinkApp,
+			time.Duration(config.Get().AppLink.Schedule.DomainVerifyInterval)*time.Second),
+		linkschedule.NewLinkSafetyJob(shortLinkApp,
+			time.Duration(config.Get().AppLink.Schedule.SafetyScanInterval)*time.Second),
+		linkschedule.NewLinkHealthJob(shortLinkApp,
+			time.Duration(config.Get().AppLink.Schedule.HealthCheckInterval)*time.Second,
+			config.Get().AppLink.HealthCheck.SamplePerGroup, config.Get().AppLink.HealthCheck.Concurrency),
+	)
+
 	shutdown.NewHook().WithSignals(syscall.SIGINT, syscall.SIGTERM).Close(
 		// shutdown server
 		shutdownServer,
+		// shutdown scheduler
+		shutdownScheduler,
 		// shutdown database
 		func() {
 			if sqlDB, err := db.DB(); err != nil {
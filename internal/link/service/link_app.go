@@ -1,13 +1,18 @@
 package service
 
 import (
+	"errors"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 	"log/slog"
+	"net"
+	"net/http"
 	"shortlink/internal/base/base_event"
 	"shortlink/internal/base/cache"
 	"shortlink/internal/base/lock"
+	"shortlink/internal/base/mail"
 	"shortlink/internal/base/metrics"
+	"shortlink/internal/base/types"
 	"shortlink/internal/link/adapter"
 	"shortlink/internal/link/adapter/read"
 	"shortlink/internal/link/app"
@@ -15,6 +20,8 @@ import (
 	"shortlink/internal/link/app/query"
 	"shortlink/internal/link/common/config"
 	"shortlink/internal/link/domain/link"
+	"strings"
+	"time"
 )
 
 func NewLinkApplication(
@@ -29,10 +36,22 @@ func NewLinkApplication(
 
 	c := config.Get().AppLink
 
+	if err := checkCookieSecret(c.Protect.CookieSecret); err != nil {
+		panic("invalid protect config: " + err.Error())
+	}
+
+	generator, err := newShortCodeGenerator(rdb)
+	if err != nil {
+		panic("failed to create short code generator: " + err.Error())
+	}
+	logger.Info("short code generator initialized",
+		"type", c.ShortCode.Generator, "collision", generator.Collision().String())
+
 	factoryConfig := link.FactoryConfig{
 		Domain:            c.Domain,
 		UseSSL:            config.Get().Server.UseSsl,
 		Whitelist:         c.Whitelist,
+		Interstitial:      c.Redirect.Interstitial,
 		MaxAttempts:       c.MaxAttempts,
 		MaxLinksPerGroup:  c.MaxLinksPerGroup,
 		DefaultFavicon:    c.DefaultFaviconUrl,
@@ -40,6 +59,10 @@ func NewLinkApplication(
 		DefaultExpiration: 15,
 		DefaultCreateType: link.CreateByApi,
 		DefaultValidType:  link.ValidTypeTemporary,
+		AliasMinLength:    c.Alias.MinLength,
+		AliasMaxLength:    c.Alias.MaxLength,
+		ReservedWords:     c.Alias.ReservedWords,
+		Generator:         generator,
 	}
 	linkFactory, err := link.NewFactory(factoryConfig)
 	if err != nil {
@@ -49,12 +72,69 @@ func NewLinkApplication(
 	distributedCache := cache.NewRedisDistributedCache(rdb, locker)
 	repository := adapter.NewLinkRepository(linkFactory, db, distributedCache)
 	readModel := read.NewLinkQuery(db, linkFactory, distributedCache)
+	cursorCodec := types.NewCursorCodec(config.Get().Pagination.CursorSecret)
+	unlockLimiter := adapter.NewRedisUnlockAttemptLimiter(
+		rdb, c.Protect.MaxAttempts, time.Duration(c.Protect.AttemptWindow)*time.Minute)
+
+	urlChecker, err := newUrlSafetyChecker(repository)
+	if err != nil {
+		panic("failed to create url safety checker: " + err.Error())
+	}
+
+	// 未开启通知时 notifier 为 nil
+	var brokenLinkNotifier command.BrokenLinkNotifier
+	if c.HealthCheck.Notify {
+		brokenLinkNotifier = adapter.NewMailBrokenLinkNotifier(db, mail.NewSMTPMailer(), config.Get().Email.Username)
+	}
+
+	// 未开启时 accountChecker 为 nil，不检查邮箱是否已验证
+	var accountChecker command.AccountChecker
+	if config.Get().Account.RequireVerifiedMail {
+		accountChecker = adapter.NewDBAccountChecker(db)
+	}
+
+	jobResultStore, err := adapter.NewFileJobResultStore(c.Import.ResultDir)
+	if err != nil {
+		panic("failed to create job result store: " + err.Error())
+	}
+
+	domainVerifier := adapter.NewDomainOwnershipVerifier(
+		net.DefaultResolver, time.Duration(c.ShortDomain.VerifyTimeout)*time.Second)
+	countryResolver := adapter.NewIpApiCountryResolver(
+		time.Duration(c.GeoIp.Timeout)*time.Millisecond, c.GeoIp.CacheSize)
+
+	// 只有开启警告页面时白名单外的短链接才能被创建，跳转时才需要检查
+	var whitelist link.Whitelist
+	if c.Redirect.Interstitial {
+		whitelist = c.Whitelist
+	}
 
 	a = app.Application{
 		Commands: app.Commands{
-			CreateLink:      command.NewCreateLinkHandler(linkFactory, repository, locker, logger, metricsClient),
-			CreateLinkBatch: command.NewCreateLinkBatchHandler(linkFactory, repository, logger, metricsClient),
-			UpdateLink:      command.NewUpdateLinkHandler(repository, logger, metricsClient),
+			CreateLink:      command.NewCreateLinkHandler(linkFactory, repository, locker, urlChecker, eventBus, accountChecker, logger, metricsClient),
+			CreateLinkBatch: command.NewCreateLinkBatchHandler(linkFactory, repository, urlChecker, eventBus, accountChecker, logger, metricsClient),
+			UpdateLink:      command.NewUpdateLinkHandler(linkFactory, repository, urlChecker, logger, metricsClient),
+			UnlockLink:      command.NewUnlockLinkHandler(readModel, unlockLimiter, logger, metricsClient),
+			ImportLinks:     command.NewImportLinksHandler(linkFactory, repository, urlChecker, eventBus, jobResultStore, accountChecker, logger, metricsClient),
+			ExportLinks:     command.NewExportLinksHandler(repository, readModel, jobResultStore, c.Export.SyncMaxLinks, logger, metricsClient),
+			BulkLinks:       command.NewBulkLinksHandler(repository, linkFactory, adapter.NewDBGroupChecker(db), logger, metricsClient),
+
+			FetchLinkMetadata: command.NewFetchLinkMetadataHandler(repository,
+				adapter.NewHtmlMetadataFetcher(time.Duration(c.Metadata.Timeout)*time.Second,
+					c.Metadata.MaxBytes, time.Duration(c.Metadata.CacheTTL)*time.Second),
+				logger, metricsClient),
+
+			UpdateGroupSetting: command.NewUpdateGroupSettingHandler(repository, logger, metricsClient),
+			RefreshLinkStatus:  command.NewRefreshLinkStatusHandler(repository, eventBus, logger, metricsClient),
+			ScanLinkSafety:     command.NewScanLinkSafetyHandler(repository, urlChecker, eventBus, logger, metricsClient),
+			CheckLinkHealth: command.NewCheckLinkHealthHandler(repository,
+				adapter.NewHttpDestinationProber(time.Duration(c.HealthCheck.Timeout)*time.Second),
+				brokenLinkNotifier, logger, metricsClient),
+
+			RegisterShortDomain:  command.NewRegisterShortDomainHandler(repository, logger, metricsClient),
+			RemoveShortDomain:    command.NewRemoveShortDomainHandler(repository, logger, metricsClient),
+			VerifyShortDomain:    command.NewVerifyShortDomainHandler(repository, domainVerifier, eventBus, c.ShortDomain.RevokeAfterFailures, logger, metricsClient),
+			ReverifyShortDomains: command.NewReverifyShortDomainsHandler(repository, domainVerifier, eventBus, c.ShortDomain.RevokeAfterFailures, logger, metricsClient),
 
 			SaveToRecycleBin:      command.NewSaveToRecycleBinHandler(repository, logger, metricsClient),
 			RemoveFromRecycleBin:  command.NewRemoveFromRecycleBinHandler(repository, logger, metricsClient),
@@ -62,12 +142,77 @@ func NewLinkApplication(
 		},
 		Queries: app.Queries{
 			PageLink:       query.NewPageLinkHandler(readModel, logger, metricsClient),
+			CursorPageLink: query.NewCursorPageLinkHandler(readModel, cursorCodec, logger, metricsClient),
+			PageBrokenLink: query.NewPageBrokenLinkHandler(readModel, logger, metricsClient),
 			ListGroupCount: query.NewListGroupCountHandler(readModel, logger, metricsClient),
-			GetOriginalUrl: query.NewGetOriginalUrlHandler(readModel, eventBus, distributedCache, logger, metricsClient),
+			GetOriginalUrl: query.NewGetOriginalUrlHandler(readModel, repository, countryResolver, eventBus, distributedCache, whitelist, logger, metricsClient),
+			GetLinkPreview: query.NewGetLinkPreviewHandler(readModel, logger, metricsClient),
+			ListLinkIds:    query.NewListLinkIdsHandler(readModel, logger, metricsClient),
+
+			GetGroupSetting: query.NewGetGroupSettingHandler(readModel, logger, metricsClient),
+
+			GetJob:       query.NewGetJobHandler(readModel, logger, metricsClient),
+			GetJobResult: query.NewGetJobResultHandler(readModel, jobResultStore, logger, metricsClient),
 
-			PageRecycleBin: query.NewPageRecycleBinHandler(readModel, logger, metricsClient),
+			ListShortDomains: query.NewListShortDomainsHandler(readModel, logger, metricsClient),
+
+			PageRecycleBin:       query.NewPageRecycleBinHandler(readModel, logger, metricsClient),
+			CursorPageRecycleBin: query.NewCursorPageRecycleBinHandler(readModel, cursorCodec, logger, metricsClient),
 		},
 	}
 
 	return
 }
+
+// newUrlSafetyChecker 根据配置组装跳转目标安全检查，本地检查在前，命中后不再请求外部服务
+func newUrlSafetyChecker(domains adapter.ShortDomainGetter) (link.UrlSafetyChecker, error) {
+	c := config.Get().AppLink
+	timeout := time.Duration(c.Safety.CheckTimeout) * time.Second
+
+	chain := link.UrlSafetyChain{link.SchemeChecker{}}
+	if c.Safety.BlocklistFile != "" {
+		blocklist, err := adapter.NewFileBlocklistChecker(
+			c.Safety.BlocklistFile, time.Duration(c.Safety.BlocklistReloadInterval)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, blocklist)
+	}
+	chain = append(chain, adapter.NewRedirectLoopChecker(c.Domain, domains, c.Safety.MaxRedirectHops, timeout))
+
+	var lookup adapter.SafeBrowsingLookup = adapter.NewStubSafeBrowsing(c.Safety.SafeBrowsing.Stub)
+	if c.Safety.SafeBrowsing.ApiKey != "" {
+		lookup = adapter.NewSafeBrowsingApi(
+			c.Safety.SafeBrowsing.Endpoint, c.Safety.SafeBrowsing.ApiKey, &http.Client{Timeout: timeout})
+	}
+	chain = append(chain, adapter.NewSafeBrowsingChecker(lookup))
+
+	return chain, nil
+}
+
+// newShortCodeGenerator 根据配置选择短链接生成策略
+func newShortCodeGenerator(rdb *redis.Client) (link.ShortCodeGenerator, error) {
+	c := config.Get().AppLink.ShortCode
+
+	switch link.ShortCodeGeneratorType(c.Generator) {
+	case "", link.ShortCodeGeneratorHash:
+		return link.NewHashGenerator(), nil
+	case link.ShortCodeGeneratorCounter:
+		return link.NewCounterGenerator(adapter.NewRedisSegmentAllocator(rdb), c.SegmentStep, c.Length, c.Salt)
+	case link.ShortCodeGeneratorSnowflake:
+		return link.NewSnowflakeGenerator(c.NodeId)
+	case link.ShortCodeGeneratorRandom:
+		return link.NewRandomGenerator(c.Length)
+	default:
+		return nil, errors.New("unknown short code generator: " + c.Generator)
+	}
+}
+
+// checkCookieSecret 解锁 Cookie 签名密钥为空或使用配置文件中的示例值时，任何人都可以伪造 Cookie 跳过密码保护
+func checkCookieSecret(secret string) error {
+	secret = strings.TrimSpace(secret)
+	if secret == "" || strings.EqualFold(secret, "change-me") {
+		return errors.New("cookie_secret must be set to a random value")
+	}
+	return nil
+}
@@ -21,6 +21,26 @@ type LinkCreateReq struct {
 	EndDate *types.JsonTime `json:"end_date,omitempty" format:"2006-01-02 15:04:05"`
 	// 描述
 	Desc string `json:"desc,omitempty"`
+	// 自定义短链接 可选
+	Alias string `json:"alias,omitempty"`
+	// 访问密码 可选
+	Password string `json:"password,omitempty"`
+	// 最大访问次数 可选 0 表示不限制
+	MaxVisits int `json:"max_visits,omitempty"`
+	// 条件路由规则 可选 按顺序匹配，都未命中时跳转到原始链接
+	RoutingRules link.RoutingRules `json:"routing_rules,omitempty"`
+	// 按权重分流的跳转目标 可选 至少两个
+	Variants link.Variants `json:"variants,omitempty"`
+	// 跳转时对查询参数的处理 可选 未指定时使用分组默认配置
+	UrlParams *link.UrlParams `json:"url_params,omitempty"`
+	// 跳转状态码 可选 301/302/307/308，默认 302
+	RedirectCode link.RedirectCode `json:"redirect_code,omitempty"`
+	// 备用链接 可选 过期、停用或访问次数用完时跳转，未指定时使用分组默认配置
+	FallbackUrl string `json:"fallback_url,omitempty"`
+	// 自定义域名 可选 需要是自己注册的域名，未指定时使用默认域名
+	Domain string `json:"domain,omitempty"`
+	// 标签 可选 最多10个
+	Tags link.Tags `json:"tags,omitempty"`
 }
 
 // LinkBatchCreateReq 批量创建短链接请求
@@ -39,10 +59,14 @@ type LinkBatchCreateReq struct {
 	StartDate *types.JsonTime `json:"start_date,omitempty" format:"2006-01-02 15:04:05"`
 	// 有效期 - 结束时间
 	EndDate *types.JsonTime `json:"end_date,omitempty" format:"2006-01-02 15:04:05"`
+	// 标签 可选 所有短链接使用相同的标签
+	Tags link.Tags `json:"tags,omitempty"`
 }
 
 // LinkUpdateReq 更新短链接请求
 type LinkUpdateReq struct {
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
 	// 短链接
 	ShortUri string `json:"short_uri,omitempty" validate:"required"`
 	// 原始分组标识
@@ -61,18 +85,107 @@ type LinkUpdateReq struct {
 	EndDate *types.JsonTime `json:"end_date,omitempty" format:"2006-01-02 15:04:05"`
 	// 描述
 	Desc *string `json:"desc,omitempty"`
+	// 访问密码 传空字符串取消密码保护
+	Password *string `json:"password,omitempty"`
+	// 最大访问次数 0 表示不限制
+	MaxVisits *int `json:"max_visits,omitempty"`
+	// 条件路由规则 传空数组时清空
+	RoutingRules *link.RoutingRules `json:"routing_rules,omitempty"`
+	// 按权重分流的跳转目标 传空数组时清空
+	Variants *link.Variants `json:"variants,omitempty"`
+	// 跳转时对查询参数的处理
+	UrlParams *link.UrlParams `json:"url_params,omitempty"`
+	// 跳转状态码 301/302/307/308
+	RedirectCode *link.RedirectCode `json:"redirect_code,omitempty"`
+	// 备用链接 传空字符串时清空
+	FallbackUrl *string `json:"fallback_url,omitempty"`
+	// 标签 传空数组时清空
+	Tags *link.Tags `json:"tags,omitempty"`
+}
+
+// LinkBulkReq 批量操作短链接请求 通过短链接列表或过滤条件选择短链接
+type LinkBulkReq struct {
+	// 操作 move:移动分组 tag:追加标签 set_expiry:修改有效期 set_status:启用或停用 recycle:移入回收站
+	Op link.BulkOp `json:"op,omitempty" validate:"required"`
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
+	// 短链接 指定后忽略过滤条件
+	ShortUris []string `json:"short_uris,omitempty"`
+	// 过滤条件 必须包含分组
+	Filter *LinkFilterReq `json:"filter,omitempty"`
+	// 目标分组 移动时必填
+	Gid string `json:"gid,omitempty"`
+	// 追加的标签 打标签时必填
+	Tags link.Tags `json:"tags,omitempty"`
+	// 有效期类型 0:永久有效 1:自定义有效期 修改有效期时必填
+	ValidType *link.ValidType `json:"valid_date_type,omitempty"`
+	// 有效期 - 结束时间 自定义有效期时必填
+	EndDate *types.JsonTime `json:"end_date,omitempty" format:"2006-01-02 15:04:05"`
+	// 目标状态 active 或 disabled
+	Status link.Status `json:"status,omitempty"`
+}
+
+// GroupSettingUpdateReq 更新分组默认配置请求
+type GroupSettingUpdateReq struct {
+	// 分组ID
+	Gid string `json:"gid,omitempty" validate:"required"`
+	// 默认的查询参数处理 不传时清空
+	UrlParams *link.UrlParams `json:"url_params,omitempty"`
+	// 默认的备用链接 不传时清空
+	FallbackUrl string `json:"fallback_url,omitempty"`
+}
+
+// ShortDomainRegisterReq 注册自定义域名请求
+type ShortDomainRegisterReq struct {
+	// 域名 可以带端口
+	Host string `json:"host,omitempty" validate:"required"`
+	// 是否通过 https 访问
+	UseTLS bool `json:"use_tls,omitempty"`
 }
 
 // LinkPageReq 分页查询短链接请求
 type LinkPageReq struct {
 	// 分页参数
 	types.PageReq `json:",inline"`
-	// 分组ID
-	Gid *string `json:"gid,omitempty"`
+	// 过滤条件
+	LinkFilterReq `json:",inline"`
 	// 排序标识
 	OrderTag *string `json:"order_tag,omitempty"`
 }
 
+// LinkCursorPageReq 游标分页查询短链接请求 按创建时间倒序
+type LinkCursorPageReq struct {
+	// 游标分页参数
+	types.CursorReq `json:",inline"`
+	// 过滤条件
+	LinkFilterReq `json:",inline"`
+}
+
+// LinkFilterReq 查询短链接的过滤条件
+type LinkFilterReq struct {
+	// 分组ID
+	Gid *string `json:"gid,omitempty"`
+	// 标签 多个标签时重复传参，需要同时包含
+	Tags []string `json:"tags,omitempty" query:"tags"`
+	// 状态
+	Status *link.Status `json:"status,omitempty" query:"status"`
+	// 创建类型 0:接口创建 1:控制台创建
+	CreateType *link.CreateType `json:"create_type,omitempty" query:"create_type"`
+	// 创建时间范围 格式为 2006-01-02 或 2006-01-02 15:04:05
+	StartTime string `json:"start_time,omitempty" query:"start_time"`
+	EndTime   string `json:"end_time,omitempty" query:"end_time"`
+	// 关键词 匹配描述、原始链接和短链接
+	Keyword string `json:"keyword,omitempty" query:"keyword"`
+}
+
+// BrokenLinkPageReq 分页查询跳转目标失效的短链接请求
+type BrokenLinkPageReq struct {
+	// 分页参数
+	types.PageReq `json:",inline"`
+	// 分组ID
+	Gid *string `json:"gid,omitempty"`
+}
+
 // LinkGroupStatReq 分组短链接监控请求
 //type LinkGroupStatReq struct {
 //	// 分组ID
@@ -124,3 +237,15 @@ type LinkPageReq struct {
 //	// 结束时间
 //	EndTime types.JsonTime `json:"end_time" validate:"required" format:"2006-01-02 15:04:05"`
 //}
+
+// LinkImportReq 批量导入短链接请求 导入文件通过 multipart 的 file 字段或请求体上传
+type LinkImportReq struct {
+	// 文件格式 csv | ndjson 为空时根据文件扩展名和 Content-Type 判断
+	Format string `json:"format,omitempty" query:"format"`
+	// 分组ID 行内未指定分组时使用
+	Gid string `json:"gid,omitempty" query:"gid"`
+	// 创建类型 0:接口创建 1:控制台创建
+	CreateType *link.CreateType `json:"create_type,omitempty" query:"create_type"`
+	// 只校验不写入
+	DryRun bool `json:"dry_run,omitempty" query:"dry_run"`
+}
@@ -8,6 +8,8 @@ import (
 type RecycleBinSaveReq struct {
 	// 分组标识
 	Gid string `json:"gid"`
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
 	// 短链接
 	ShortUri string `json:"short_uri" validate:"required"`
 }
@@ -16,6 +18,8 @@ type RecycleBinSaveReq struct {
 type RecycleBinRecoverReq struct {
 	// 分组标识
 	Gid string `json:"gid" binding:"required"`
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
 	// 短链接
 	ShortUri string `json:"short_uri" validate:"required"`
 }
@@ -24,6 +28,8 @@ type RecycleBinRecoverReq struct {
 type RecycleBinDeleteReq struct {
 	// 分组标识
 	Gid string `json:"gid" binding:"required"`
+	// 自定义域名 为空表示默认域名
+	Domain string `json:"domain,omitempty"`
 	// 短链接
 	ShortUri string `json:"short_uri" validate:"required"`
 }
@@ -35,3 +41,11 @@ type RecycleBinPageReq struct {
 	// 分组标识
 	Gids []string `json:"gids,omitempty"`
 }
+
+// RecycleBinCursorPageReq 游标分页查询回收站请求 按移入回收站的时间倒序
+type RecycleBinCursorPageReq struct {
+	// 游标分页参数
+	types.CursorReq
+	// 分组标识
+	Gids []string `json:"gids,omitempty"`
+}
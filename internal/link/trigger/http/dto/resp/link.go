@@ -22,6 +22,18 @@ type LinkBatchCreateResp struct {
 	LinkInfos []LinkBaseInfoDTO `json:"linkInfos"`
 }
 
+// LinkImportResp 批量导入短链接响应
+type LinkImportResp struct {
+	// 任务ID 用于查询进度和下载结果文件
+	JobId string `json:"job_id"`
+}
+
+// LinkExportResp 后台导出短链接响应
+type LinkExportResp struct {
+	// 任务ID 任务完成后下载结果文件
+	JobId string `json:"job_id"`
+}
+
 // LinkGroupCountQueryResp 短链接分组数量查询响应
 type LinkGroupCountQueryResp []GroupCountDTO
 
@@ -81,3 +93,27 @@ type LinkBaseInfoDTO struct {
 	// 短链接
 	FullShortUrl string `json:"fullShortUrl"`
 }
+
+// LinkBulkResp 批量操作短链接响应
+type LinkBulkResp struct {
+	// 成功数量
+	Succeeded int `json:"succeeded"`
+	// 失败数量
+	Failed int `json:"failed"`
+	// 每个短链接的结果
+	Items []LinkBulkItemDTO `json:"items"`
+}
+
+// LinkBulkItemDTO 批量操作中单个短链接的结果
+type LinkBulkItemDTO struct {
+	// 自定义域名
+	Domain string `json:"domain,omitempty"`
+	// 短链接
+	ShortUri string `json:"short_uri"`
+	// 操作前所在的分组
+	Gid string `json:"gid,omitempty"`
+	// 是否成功
+	Success bool `json:"success"`
+	// 失败原因
+	Error string `json:"error,omitempty"`
+}
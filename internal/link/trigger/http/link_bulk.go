@@ -0,0 +1,82 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/server/validator"
+	"shortlink/internal/link/app/command"
+	"shortlink/internal/link/app/query"
+	"shortlink/internal/link/domain/link"
+	"shortlink/internal/link/trigger/http/dto/req"
+	"shortlink/internal/link/trigger/http/dto/resp"
+)
+
+// BulkLinks 批量操作短链接，返回每个短链接的结果
+func (h LinkApi) BulkLinks(c *fiber.Ctx) error {
+
+	reqParam := req.LinkBulkReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+
+	if err := validator.Get().Validate(reqParam); err != nil {
+		return err
+	}
+
+	var ids []link.Identifier
+	switch {
+	case len(reqParam.ShortUris) > 0:
+		ids = make([]link.Identifier, 0, len(reqParam.ShortUris))
+		for _, shortUri := range reqParam.ShortUris {
+			ids = append(ids, link.Identifier{Domain: reqParam.Domain, ShortUri: shortUri})
+		}
+	case reqParam.Filter != nil:
+		filter, err := linkFilter(*reqParam.Filter)
+		if err != nil {
+			return err
+		}
+		if ids, err = h.app.Queries.ListLinkIds.Handle(c.Context(), query.ListLinkIds{
+			LinkFilter: filter,
+			Limit:      command.MaxBulkLinks,
+		}); err != nil {
+			return err
+		}
+	default:
+		return errno.LinkBulkEmpty
+	}
+
+	cmd := command.BulkLinks{
+		Ids: ids,
+		Action: link.BulkAction{
+			Op:        reqParam.Op,
+			Gid:       reqParam.Gid,
+			Tags:      reqParam.Tags,
+			ValidType: reqParam.ValidType,
+			EndDate:   reqParam.EndDate.ToTime(),
+			Status:    reqParam.Status,
+		},
+	}
+	if err := h.app.Commands.BulkLinks.Handle(c.Context(), &cmd); err != nil {
+		return err
+	}
+
+	res := cmd.ExecutionResult()
+	response := resp.LinkBulkResp{
+		Succeeded: res.Succeeded,
+		Failed:    res.Failed,
+		Items:     make([]resp.LinkBulkItemDTO, 0, len(res.Items)),
+	}
+	for _, item := range res.Items {
+		dto := resp.LinkBulkItemDTO{
+			Domain:   item.Domain,
+			ShortUri: item.ShortUri,
+			Gid:      item.Gid,
+			Success:  item.Err == nil,
+		}
+		if item.Err != nil {
+			dto.Error = item.Err.Error()
+		}
+		response.Items = append(response.Items, dto)
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"shortlink/internal/link/app/command"
+	"shortlink/internal/link/domain/link"
+	"shortlink/internal/link/trigger/http/dto/resp"
+)
+
+// ExportLinks 导出分组下的短链接及访问统计，分组较大时在后台导出
+func (h LinkApi) ExportLinks(c *fiber.Ctx) error {
+
+	gid := c.Query("gid")
+	if gid == "" {
+		return errors.New("gid is required")
+	}
+	format, err := link.ParseExportFormat(c.Query("format"))
+	if err != nil {
+		return err
+	}
+
+	cmd := command.ExportLinks{Gid: gid, Format: format}
+	if err = h.app.Commands.ExportLinks.Handle(c.Context(), &cmd); err != nil {
+		return err
+	}
+
+	res := cmd.ExecutionResult()
+	if res.JobId != "" {
+		return c.Status(fiber.StatusAccepted).JSON(resp.LinkExportResp{JobId: res.JobId})
+	}
+	c.Attachment(res.Name)
+	return c.Send(res.Data)
+}
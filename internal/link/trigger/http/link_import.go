@@ -0,0 +1,114 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"path/filepath"
+	"shortlink/internal/link/app/command"
+	"shortlink/internal/link/app/query"
+	"shortlink/internal/link/common/config"
+	"shortlink/internal/link/domain/link"
+	"shortlink/internal/link/trigger/http/dto/req"
+	"shortlink/internal/link/trigger/http/dto/resp"
+	"strings"
+)
+
+// ImportLinks 从 CSV 或 NDJSON 文件批量导入短链接，导入在后台执行
+func (h LinkApi) ImportLinks(c *fiber.Ctx) error {
+
+	reqParam := req.LinkImportReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+
+	var (
+		body     io.Reader
+		filename string
+	)
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		body, filename = file, fileHeader.Filename
+	} else {
+		body = bytes.NewReader(c.Body())
+	}
+
+	format := reqParam.Format
+	if format == "" {
+		format = importFormatOf(filename, string(c.Request().Header.ContentType()))
+	}
+	importFormat, err := link.ParseImportFormat(format)
+	if err != nil {
+		return err
+	}
+
+	rows, err := link.ParseImportRows(importFormat, body, config.Get().AppLink.Import.MaxRows)
+	if err != nil {
+		return err
+	}
+
+	cmd := command.ImportLinks{
+		Rows:       rows,
+		Gid:        reqParam.Gid,
+		CreateType: reqParam.CreateType,
+		DryRun:     reqParam.DryRun,
+	}
+	if err = h.app.Commands.ImportLinks.Handle(c.Context(), &cmd); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(resp.LinkImportResp{JobId: cmd.ExecutionResult().JobId})
+}
+
+// importFormatOf 根据文件扩展名和 Content-Type 判断导入文件格式
+func importFormatOf(filename, contentType string) string {
+	if ext := strings.TrimPrefix(filepath.Ext(filename), "."); ext != "" {
+		return ext
+	}
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return string(link.ImportCSV)
+	case strings.Contains(contentType, "json"):
+		return string(link.ImportNDJSON)
+	}
+	return ""
+}
+
+// GetJob 查询后台任务进度
+func (h LinkApi) GetJob(c *fiber.Ctx) error {
+
+	id := c.Query("id")
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	res, err := h.app.Queries.GetJob.Handle(c.Context(), query.GetJob{Id: id})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
+// DownloadJobResult 下载后台任务的结果文件
+func (h LinkApi) DownloadJobResult(c *fiber.Ctx) error {
+
+	id := c.Query("id")
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	res, err := h.app.Queries.GetJobResult.Handle(c.Context(), query.GetJobResult{Id: id})
+	if err != nil {
+		return err
+	}
+
+	// 响应发送完成后由 fasthttp 关闭文件
+	c.Attachment(res.Name)
+	return c.SendStream(res.Body)
+}
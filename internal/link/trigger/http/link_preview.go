@@ -0,0 +1,46 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"html/template"
+	"shortlink/internal/link/app/query"
+)
+
+// previewSuffix 短链接后追加该后缀时展示预览页面
+const previewSuffix = "+"
+
+const (
+	// previewPageFile 预览页面
+	previewPageFile = "../../templates/preview.html"
+	// interstitialPageFile 白名单外跳转目标的警告页面
+	interstitialPageFile = "../../templates/interstitial.html"
+)
+
+// Preview 展示短链接的跳转目标、标题和图标，不进行跳转
+func (h LinkApi) Preview(c *fiber.Ctx, shortUri string) error {
+
+	res, err := h.app.Queries.GetLinkPreview.Handle(c.Context(), query.GetLinkPreview{
+		Domain:   requestDomain(c),
+		ShortUri: shortUri,
+	})
+	if err != nil {
+		return err
+	}
+
+	return renderPage(c, previewPageFile, fiber.StatusOK, res)
+}
+
+// renderInterstitialPage 跳转目标不在白名单内时提示访问者，由访问者决定是否继续
+func renderInterstitialPage(c *fiber.Ctx, target string) error {
+	return renderPage(c, interstitialPageFile, fiber.StatusOK, struct{ Url string }{Url: target})
+}
+
+// renderPage 渲染 templates 目录下的页面
+func renderPage(c *fiber.Ctx, file string, status int, data any) error {
+	page, err := template.ParseFiles(file)
+	if err != nil {
+		return err
+	}
+	c.Status(status).Type("html", "utf-8")
+	return page.Execute(c.Response().BodyWriter(), data)
+}
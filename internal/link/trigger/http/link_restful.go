@@ -5,7 +5,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/jinzhu/copier"
+	"net/url"
+	"shortlink/internal/base/apikey"
 	"shortlink/internal/base/errno"
+	"shortlink/internal/base/server/middleware/auth"
 	"shortlink/internal/base/server/validator"
 	"shortlink/internal/base/toolkit"
 	"shortlink/internal/link/app"
@@ -13,6 +16,7 @@ import (
 	"shortlink/internal/link/app/query"
 	"shortlink/internal/link/common/config"
 	"shortlink/internal/link/domain/event"
+	"shortlink/internal/link/domain/link"
 	"shortlink/internal/link/trigger/http/dto/req"
 	"shortlink/internal/link/trigger/http/dto/resp"
 	"strings"
@@ -36,18 +40,59 @@ func NewLinkApi(app app.Application, router fiber.Router) {
 	})
 	// 短链接跳转到原始链接
 	router.Get("/:shortUri", api.Redirect)
+	// 输入访问密码解锁短链接
+	router.Post("/:shortUri", api.Unlock)
 	// 创建短链接
 	router.Post(prefix+"/create", api.CreateLink)
 	// 通过分布式锁创建短链接
 	router.Post(prefix+"/create/with-lock", api.CreateLinkWithLock)
 	// 批量创建短链接
 	router.Post(prefix+"/create-batch", api.BatchCreateLink)
+	// 从文件批量导入短链接
+	router.Post(prefix+"/import", api.ImportLinks)
+	// 导出分组下的短链接
+	router.Get(prefix+"/export", api.ExportLinks)
+	// 查询后台任务进度
+	router.Get(prefix+"/job", api.GetJob)
+	// 下载后台任务的结果文件
+	router.Get(prefix+"/job/result", api.DownloadJobResult)
 	// 更新短链接
 	router.Put(prefix+"/update", api.UpdateLink)
+	// 批量移动分组、打标签、修改有效期、启停用或移入回收站
+	router.Post(prefix+"/bulk", api.BulkLinks)
 	// 分页查询短链接
 	router.Get(prefix+"/page", api.PageQueryLink)
+	// 游标分页查询短链接
+	router.Get(prefix+"/page/cursor", api.CursorPageQueryLink)
+	// 分页查询跳转目标失效的短链接
+	router.Get(prefix+"/page/broken", api.PageBrokenLink)
 	// 查询短链接分组内数量
 	router.Get(prefix+"/group-links-count", api.ListGroupLinkCount)
+	// 查询分组默认配置
+	router.Get(prefix+"/group-setting", api.GetGroupSetting)
+	// 更新分组默认配置
+	router.Put(prefix+"/group-setting", api.UpdateGroupSetting)
+	// 查询自己注册的自定义域名
+	router.Get(prefix+"/domain", api.ListShortDomains)
+	// 注册自定义域名
+	router.Post(prefix+"/domain", api.RegisterShortDomain)
+	// 删除自定义域名
+	router.Delete(prefix+"/domain", api.RemoveShortDomain)
+	// 验证自定义域名所有权
+	router.Post(prefix+"/domain/verify", api.VerifyShortDomain)
+}
+
+// ApiKeyRoutes 允许使用 API Key 访问的接口，分组限制在应用层校验
+func ApiKeyRoutes() []auth.ApiKeyRoute {
+	prefix := config.Get().AppLink.BaseRoutePrefix
+	return []auth.ApiKeyRoute{
+		{Method: fiber.MethodPost, Path: prefix + "/create", Scope: apikey.ScopeLinkCreate},
+		{Method: fiber.MethodPost, Path: prefix + "/create/with-lock", Scope: apikey.ScopeLinkCreate},
+		{Method: fiber.MethodPost, Path: prefix + "/create-batch", Scope: apikey.ScopeLinkCreate},
+		{Method: fiber.MethodPut, Path: prefix + "/update", Scope: apikey.ScopeLinkUpdate},
+		{Method: fiber.MethodGet, Path: prefix + "/page", Scope: apikey.ScopeLinkRead},
+		{Method: fiber.MethodGet, Path: prefix + "/page/cursor", Scope: apikey.ScopeLinkRead},
+	}
 }
 
 // Redirect 短链接跳转到原始链接
@@ -58,6 +103,28 @@ func (h LinkApi) Redirect(c *fiber.Ctx) error {
 		return errno.LinkNotExists
 	}
 
+	// 预览模式 不跳转也不计入访问统计
+	if config.Get().AppLink.Redirect.Preview && strings.HasSuffix(shortUri, previewSuffix) {
+		return h.Preview(c, strings.TrimSuffix(shortUri, previewSuffix))
+	}
+
+	unlocked := verifyUnlockToken(shortUri, c.Cookies(unlockCookieName))
+
+	return h.redirect(c, shortUri, unlocked)
+}
+
+// requestDomain 根据请求的 Host 确定短链接所属的域名，默认域名返回空字符串
+func requestDomain(c *fiber.Ctx) string {
+	host := strings.ToLower(c.Hostname())
+	if host == strings.ToLower(config.Get().AppLink.Domain) {
+		return ""
+	}
+	return host
+}
+
+// redirect 记录访问信息并跳转到原始链接，受保护且未解锁时展示密码输入页面
+func (h LinkApi) redirect(c *fiber.Ctx, shortUri string, unlocked bool) error {
+
 	os, browser, device, network := toolkit.GetRequestInfo(c)
 
 	uv := c.Cookies("uv")
@@ -70,7 +137,10 @@ func (h LinkApi) Redirect(c *fiber.Ctx) error {
 		})
 	}
 
+	domain := requestDomain(c)
+
 	userVisitInfo := event.UserVisitInfo{
+		Domain:      domain,
 		ShortUri:    shortUri,
 		RemoteAddr:  c.IP(),
 		OS:          os,
@@ -82,21 +152,45 @@ func (h LinkApi) Redirect(c *fiber.Ctx) error {
 	}
 
 	q := query.GetOriginalUrl{
+		Domain:        domain,
 		ShortUri:      shortUri,
 		UserVisitInfo: userVisitInfo,
+		Unlocked:      unlocked,
+		Languages:     link.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage)),
+		Query:         queryValues(c),
 	}
 
 	// 获取原始链接
-	originalUrl, err := h.app.Queries.GetOriginalUrl.Handle(c.Context(), q)
+	res, err := h.app.Queries.GetOriginalUrl.Handle(c.Context(), q)
 	if err != nil {
+		if errors.Is(err, errno.LinkPasswordRequired) {
+			return renderUnlockPage(c, fiber.StatusOK, "")
+		}
 		return err
 	}
 
-	if originalUrl == "" {
+	if res.Url == "" {
 		return errno.LinkNotExists
 	}
 
-	return c.Redirect(originalUrl)
+	if res.Interstitial {
+		return renderInterstitialPage(c, res.Url)
+	}
+
+	return c.Redirect(res.Url, res.StatusCode)
+}
+
+// queryValues 短链接上携带的查询参数
+func queryValues(c *fiber.Ctx) url.Values {
+	args := c.Context().QueryArgs()
+	if args.Len() == 0 {
+		return nil
+	}
+	values := make(url.Values, args.Len())
+	args.VisitAll(func(key, value []byte) {
+		values.Add(string(key), string(value))
+	})
+	return values
 }
 
 // CreateLink 创建短链接
@@ -112,14 +206,24 @@ func (h LinkApi) CreateLink(c *fiber.Ctx) (err error) {
 	}
 
 	cmd := &command.CreateLink{
-		OriginalUrl: reqParam.OriginalUrl,
-		Gid:         reqParam.Gid,
-		CreateType:  reqParam.CreateType,
-		ValidType:   reqParam.ValidType,
-		StartDate:   reqParam.StartDate.ToTime(),
-		EndDate:     reqParam.EndDate.ToTime(),
-		Desc:        reqParam.Desc,
-		WithLock:    false,
+		OriginalUrl:  reqParam.OriginalUrl,
+		Gid:          reqParam.Gid,
+		CreateType:   reqParam.CreateType,
+		ValidType:    reqParam.ValidType,
+		StartDate:    reqParam.StartDate.ToTime(),
+		EndDate:      reqParam.EndDate.ToTime(),
+		Desc:         reqParam.Desc,
+		Alias:        reqParam.Alias,
+		Password:     reqParam.Password,
+		MaxVisits:    reqParam.MaxVisits,
+		RoutingRules: reqParam.RoutingRules,
+		Variants:     reqParam.Variants,
+		UrlParams:    reqParam.UrlParams,
+		RedirectCode: reqParam.RedirectCode,
+		FallbackUrl:  reqParam.FallbackUrl,
+		Domain:       reqParam.Domain,
+		Tags:         reqParam.Tags,
+		WithLock:     false,
 	}
 
 	if err = h.app.Commands.CreateLink.Handle(c.Context(), cmd); err != nil {
@@ -150,14 +254,24 @@ func (h LinkApi) CreateLinkWithLock(c *fiber.Ctx) (err error) {
 	}
 
 	cmd := &command.CreateLink{
-		OriginalUrl: reqParam.OriginalUrl,
-		Gid:         reqParam.Gid,
-		CreateType:  reqParam.CreateType,
-		ValidType:   reqParam.ValidType,
-		StartDate:   reqParam.StartDate.ToTime(),
-		EndDate:     reqParam.EndDate.ToTime(),
-		Desc:        reqParam.Desc,
-		WithLock:    true,
+		OriginalUrl:  reqParam.OriginalUrl,
+		Gid:          reqParam.Gid,
+		CreateType:   reqParam.CreateType,
+		ValidType:    reqParam.ValidType,
+		StartDate:    reqParam.StartDate.ToTime(),
+		EndDate:      reqParam.EndDate.ToTime(),
+		Desc:         reqParam.Desc,
+		Alias:        reqParam.Alias,
+		Password:     reqParam.Password,
+		MaxVisits:    reqParam.MaxVisits,
+		RoutingRules: reqParam.RoutingRules,
+		Variants:     reqParam.Variants,
+		UrlParams:    reqParam.UrlParams,
+		RedirectCode: reqParam.RedirectCode,
+		FallbackUrl:  reqParam.FallbackUrl,
+		Domain:       reqParam.Domain,
+		Tags:         reqParam.Tags,
+		WithLock:     true,
 	}
 
 	if err = h.app.Commands.CreateLink.Handle(c.Context(), cmd); err != nil {
@@ -195,6 +309,7 @@ func (h LinkApi) BatchCreateLink(c *fiber.Ctx) error {
 		ValidType:    reqParam.ValidType,
 		StartDate:    reqParam.StartDate.ToTime(),
 		EndDate:      reqParam.EndDate.ToTime(),
+		Tags:         reqParam.Tags,
 	}
 
 	if err := h.app.Commands.CreateLinkBatch.Handle(c.Context(), &cmd); err != nil {
@@ -221,6 +336,7 @@ func (h LinkApi) UpdateLink(c *fiber.Ctx) error {
 	}
 
 	err := h.app.Commands.UpdateLink.Handle(c.Context(), command.UpdateLink{
+		Domain:         reqParam.Domain,
 		ShortUri:       reqParam.ShortUri,
 		OriginalUrl:    reqParam.OriginalUrl,
 		Gid:            reqParam.Gid,
@@ -229,6 +345,14 @@ func (h LinkApi) UpdateLink(c *fiber.Ctx) error {
 		ValidStartDate: reqParam.StartDate.ToTime(),
 		ValidEndDate:   reqParam.EndDate.ToTime(),
 		Desc:           reqParam.Desc,
+		Password:       reqParam.Password,
+		MaxVisits:      reqParam.MaxVisits,
+		RoutingRules:   reqParam.RoutingRules,
+		Variants:       reqParam.Variants,
+		UrlParams:      reqParam.UrlParams,
+		RedirectCode:   reqParam.RedirectCode,
+		FallbackUrl:    reqParam.FallbackUrl,
+		Tags:           reqParam.Tags,
 	})
 	if err != nil {
 		return err
@@ -247,10 +371,15 @@ func (h LinkApi) PageQueryLink(c *fiber.Ctx) error {
 		return err
 	}
 
+	filter, err := linkFilter(reqParam.LinkFilterReq)
+	if err != nil {
+		return err
+	}
+
 	res, err := h.app.Queries.PageLink.Handle(c.Context(), query.PageLink{
-		PageReq:  reqParam.PageReq,
-		Gid:      reqParam.Gid,
-		OrderTag: reqParam.OrderTag,
+		PageReq:    reqParam.PageReq,
+		LinkFilter: filter,
+		OrderTag:   reqParam.OrderTag,
 	})
 	if err != nil {
 		return err
@@ -264,6 +393,83 @@ func (h LinkApi) PageQueryLink(c *fiber.Ctx) error {
 	return c.JSON(res)
 }
 
+// CursorPageQueryLink 游标分页查询短链接
+func (h LinkApi) CursorPageQueryLink(c *fiber.Ctx) error {
+
+	reqParam := req.LinkCursorPageReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+
+	filter, err := linkFilter(reqParam.LinkFilterReq)
+	if err != nil {
+		return err
+	}
+
+	res, err := h.app.Queries.CursorPageLink.Handle(c.Context(), query.CursorPageLink{
+		CursorReq:  reqParam.CursorReq,
+		LinkFilter: filter,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
+// linkFilter 将请求中的过滤条件转换为查询条件
+func linkFilter(r req.LinkFilterReq) (query.LinkFilter, error) {
+	startTime, err := parseQueryTime(r.StartTime)
+	if err != nil {
+		return query.LinkFilter{}, err
+	}
+	endTime, err := parseQueryTime(r.EndTime)
+	if err != nil {
+		return query.LinkFilter{}, err
+	}
+	return query.LinkFilter{
+		Gid:        r.Gid,
+		Tags:       r.Tags,
+		Status:     r.Status,
+		CreateType: r.CreateType,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Keyword:    r.Keyword,
+	}, nil
+}
+
+// parseQueryTime 解析查询参数中的时间，只有日期时表示当天零点，为空时返回 nil
+func parseQueryTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errno.NewRequestError("不合法的时间格式: " + value)
+}
+
+// PageBrokenLink 分页查询跳转目标失效的短链接
+func (h LinkApi) PageBrokenLink(c *fiber.Ctx) error {
+
+	reqParam := req.BrokenLinkPageReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+
+	res, err := h.app.Queries.PageBrokenLink.Handle(c.Context(), query.PageBrokenLink{
+		PageReq: reqParam.PageReq,
+		Gid:     reqParam.Gid,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
 // ListGroupLinkCount 查询短链接分组内数量
 func (h LinkApi) ListGroupLinkCount(c *fiber.Ctx) error {
 
@@ -293,3 +499,45 @@ func (h LinkApi) ListGroupLinkCount(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+// GetGroupSetting 查询分组默认配置
+func (h LinkApi) GetGroupSetting(c *fiber.Ctx) error {
+
+	gid := c.Query("gid")
+	if gid == "" {
+		return errors.New("gid is required")
+	}
+
+	res, err := h.app.Queries.GetGroupSetting.Handle(c.Context(), query.GetGroupSetting{Gid: gid})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
+// UpdateGroupSetting 更新分组默认配置
+func (h LinkApi) UpdateGroupSetting(c *fiber.Ctx) error {
+
+	reqParam := req.GroupSettingUpdateReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+
+	if err := validator.Get().Validate(reqParam); err != nil {
+		return err
+	}
+
+	err := h.app.Commands.UpdateGroupSetting.Handle(c.Context(), command.UpdateGroupSetting{
+		Gid:         reqParam.Gid,
+		UrlParams:   reqParam.UrlParams,
+		FallbackUrl: reqParam.FallbackUrl,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusNoContent)
+
+	return nil
+}
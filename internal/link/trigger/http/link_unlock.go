@@ -0,0 +1,97 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/link/app/command"
+	"shortlink/internal/link/common/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unlockCookieName 解锁 Cookie 名称，Path 限定为短链接自身
+const unlockCookieName = "unlock"
+
+// unlockPageFile 密码输入页面
+const unlockPageFile = "../../templates/unlock.html"
+
+type unlockPageData struct {
+	Action string
+	Error  string
+}
+
+// Unlock 校验访问密码，成功后写入解锁 Cookie 并跳转
+func (h LinkApi) Unlock(c *fiber.Ctx) error {
+
+	shortUri := c.Params("shortUri")
+	if shortUri == "" {
+		return errno.LinkNotExists
+	}
+
+	err := h.app.Commands.UnlockLink.Handle(c.Context(), command.UnlockLink{
+		Domain:     requestDomain(c),
+		ShortUri:   shortUri,
+		Password:   c.FormValue("password"),
+		RemoteAddr: c.IP(),
+	})
+	switch {
+	case errors.Is(err, errno.LinkPasswordIncorrect):
+		return renderUnlockPage(c, fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, errno.LinkUnlockTooManyAttempts):
+		return renderUnlockPage(c, fiber.StatusTooManyRequests, err.Error())
+	case err != nil:
+		return err
+	}
+
+	ttl := time.Duration(config.Get().AppLink.Protect.CookieTTL) * time.Minute
+	expires := time.Now().Add(ttl)
+	c.Cookie(&fiber.Cookie{
+		Name:     unlockCookieName,
+		Value:    signUnlockToken(shortUri, expires),
+		Path:     c.Path(),
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   config.Get().Server.UseSsl,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	return h.redirect(c, shortUri, true)
+}
+
+// renderUnlockPage 渲染密码输入页面
+func renderUnlockPage(c *fiber.Ctx, status int, msg string) error {
+	return renderPage(c, unlockPageFile, status, unlockPageData{
+		Action: c.Path(),
+		Error:  msg,
+	})
+}
+
+// signUnlockToken 生成解锁凭证 格式为 过期时间戳.签名
+func signUnlockToken(shortUri string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return exp + "." + unlockSignature(shortUri, exp)
+}
+
+// verifyUnlockToken 校验解锁凭证的签名和有效期
+func verifyUnlockToken(shortUri, token string) bool {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(unlockSignature(shortUri, exp)))
+}
+
+func unlockSignature(shortUri, exp string) string {
+	mac := hmac.New(sha256.New, []byte(config.Get().AppLink.Protect.CookieSecret))
+	mac.Write([]byte(shortUri + "|" + exp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
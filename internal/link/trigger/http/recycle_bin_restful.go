@@ -26,6 +26,8 @@ func NewLinkRecycleBinApi(app app.Application, router fiber.Router) {
 	recycleBin.Post("/save", api.SaveToRecycleBin)
 	// 分页查询回收站短链接
 	recycleBin.Get("/page", api.PageQueryRecycleBin)
+	// 游标分页查询回收站短链接
+	recycleBin.Get("/page/cursor", api.CursorPageQueryRecycleBin)
 	// 恢复短链接
 	recycleBin.Post("/recover", api.RecoverLink)
 	// 从回收站移除短链接
@@ -41,6 +43,7 @@ func (h RecycleBinApi) SaveToRecycleBin(c *fiber.Ctx) error {
 
 	err := h.app.Commands.SaveToRecycleBin.Handle(c.Context(), link.Identifier{
 		Gid:      reqParam.Gid,
+		Domain:   reqParam.Domain,
 		ShortUri: reqParam.ShortUri,
 	})
 	if err != nil {
@@ -84,6 +87,24 @@ func (h RecycleBinApi) PageQueryRecycleBin(c *fiber.Ctx) (err error) {
 	return c.JSON(res)
 }
 
+// CursorPageQueryRecycleBin 游标分页查询回收站短链接
+func (h RecycleBinApi) CursorPageQueryRecycleBin(c *fiber.Ctx) error {
+	reqParam := req.RecycleBinCursorPageReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+
+	res, err := h.app.Queries.CursorPageRecycleBin.Handle(c.Context(), query.CursorPageRecycleBin{
+		CursorReq: reqParam.CursorReq,
+		Gids:      reqParam.Gids,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
 // RecoverLink 恢复短链接
 func (h RecycleBinApi) RecoverLink(c *fiber.Ctx) error {
 	reqParam := req.RecycleBinRecoverReq{}
@@ -93,6 +114,7 @@ func (h RecycleBinApi) RecoverLink(c *fiber.Ctx) error {
 
 	err := h.app.Commands.RecoverFromRecycleBin.Handle(c.Context(), link.Identifier{
 		Gid:      reqParam.Gid,
+		Domain:   reqParam.Domain,
 		ShortUri: reqParam.ShortUri,
 	})
 	if err != nil {
@@ -114,6 +136,7 @@ func (h RecycleBinApi) RemoveFromRecycleBin(c *fiber.Ctx) error {
 
 	err := h.app.Commands.RemoveFromRecycleBin.Handle(c.Context(), link.Identifier{
 		Gid:      reqParam.Gid,
+		Domain:   reqParam.Domain,
 		ShortUri: reqParam.ShortUri,
 	})
 	if err != nil {
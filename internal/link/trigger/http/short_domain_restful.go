@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"shortlink/internal/base/server/validator"
+	"shortlink/internal/link/app/command"
+	"shortlink/internal/link/app/query"
+	"shortlink/internal/link/trigger/http/dto/req"
+)
+
+// ListShortDomains 查询自己注册的自定义域名
+func (h LinkApi) ListShortDomains(c *fiber.Ctx) error {
+
+	res, err := h.app.Queries.ListShortDomains.Handle(c.Context(), query.ListShortDomains{})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
+// RegisterShortDomain 注册自定义域名
+func (h LinkApi) RegisterShortDomain(c *fiber.Ctx) error {
+
+	reqParam := req.ShortDomainRegisterReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+
+	if err := validator.Get().Validate(reqParam); err != nil {
+		return err
+	}
+
+	err := h.app.Commands.RegisterShortDomain.Handle(c.Context(), command.RegisterShortDomain{
+		Host:   reqParam.Host,
+		UseTLS: reqParam.UseTLS,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusNoContent)
+
+	return nil
+}
+
+// RemoveShortDomain 删除自定义域名
+func (h LinkApi) RemoveShortDomain(c *fiber.Ctx) error {
+
+	host := c.Query("host")
+	if host == "" {
+		return errors.New("host is required")
+	}
+
+	err := h.app.Commands.RemoveShortDomain.Handle(c.Context(), command.RemoveShortDomain{Host: host})
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusNoContent)
+
+	return nil
+}
+
+// VerifyShortDomain 验证自定义域名所有权
+func (h LinkApi) VerifyShortDomain(c *fiber.Ctx) error {
+
+	host := c.Query("host")
+	if host == "" {
+		return errors.New("host is required")
+	}
+
+	err := h.app.Commands.VerifyShortDomain.Handle(c.Context(), command.VerifyShortDomain{Host: host})
+	if err != nil {
+		return err
+	}
+
+	c.Status(fiber.StatusNoContent)
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package schedule
+
+import (
+	"context"
+	"shortlink/internal/link/app"
+	"shortlink/internal/link/app/command"
+	"time"
+)
+
+// NewLinkHealthJob 抽样检查短链接的跳转目标是否可以访问
+func NewLinkHealthJob(app app.Application, interval time.Duration, samplePerGroup, concurrency int) Job {
+	return Job{
+		Name:     "link-health",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return app.Commands.CheckLinkHealth.Handle(ctx, command.CheckLinkHealth{
+				SamplePerGroup: samplePerGroup,
+				Concurrency:    concurrency,
+			})
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"context"
+	"shortlink/internal/link/app"
+	"shortlink/internal/link/app/command"
+	"time"
+)
+
+// NewLinkSafetyJob 定期重新检查已有短链接的跳转目标，禁用命中黑名单或威胁列表的短链接
+func NewLinkSafetyJob(app app.Application, interval time.Duration) Job {
+	return Job{
+		Name:     "link-safety",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return app.Commands.ScanLinkSafety.Handle(ctx, command.ScanLinkSafety{})
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"context"
+	"shortlink/internal/link/app"
+	"shortlink/internal/link/app/command"
+	"time"
+)
+
+// NewLinkStatusJob 按有效期激活和过期短链接
+func NewLinkStatusJob(app app.Application, interval time.Duration) Job {
+	return Job{
+		Name:     "link-status",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return app.Commands.RefreshLinkStatus.Handle(ctx, command.RefreshLinkStatus{Now: time.Now()})
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/lock"
+	"shortlink/internal/link/common/constant"
+	"sync"
+	"time"
+)
+
+// Job 定时任务
+type Job struct {
+	// 任务名称 同时作为分布式锁的 Key
+	Name string
+	// 执行间隔
+	Interval time.Duration
+	// 任务逻辑
+	Run func(ctx context.Context) error
+}
+
+// RunScheduler 启动定时任务，返回用于停止的函数
+//
+// 任务在启动时立即执行一次，之后按间隔执行；执行间隔未配置的任务不会启动。
+// 多实例部署时通过分布式锁保证同一时刻只有一个实例执行同一个任务，获取锁失败时跳过本轮
+func RunScheduler(locker lock.DistributedLock, jobs ...Job) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	for _, job := range jobs {
+		// time.NewTicker 在间隔不为正数时 panic
+		if job.Interval <= 0 {
+			slog.Warn("schedule job skipped, interval must be positive", "job", job.Name, "interval", job.Interval)
+			continue
+		}
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			slog.Info("schedule job started", "job", job.Name, "interval", job.Interval)
+			runOnce(ctx, locker, job)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runOnce(ctx, locker, job)
+				}
+			}
+		}(job)
+	}
+
+	return func() {
+		slog.Info("Scheduler is shutting down")
+		cancel()
+		wg.Wait()
+	}
+}
+
+func runOnce(ctx context.Context, locker lock.DistributedLock, job Job) {
+	lockKey := constant.LockScheduleJobKey + job.Name
+	acquired, err := locker.Acquire(ctx, lockKey, job.Interval)
+	if err != nil {
+		slog.Error("schedule job acquire lock failed", "job", job.Name, "error", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	// 不主动释放锁，锁在一个执行间隔后自动过期，避免其他实例在同一周期内重复执行
+
+	start := time.Now()
+	if err = job.Run(ctx); err != nil {
+		slog.Error("schedule job failed", "job", job.Name, "error", err)
+		return
+	}
+	slog.Debug("schedule job finished", "job", job.Name, "cost", time.Since(start))
+}
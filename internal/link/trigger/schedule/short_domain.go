@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"context"
+	"shortlink/internal/link/app"
+	"shortlink/internal/link/app/command"
+	"time"
+)
+
+// NewDomainVerifyJob 定期重新验证自定义域名，验证失败的域名下的短链接会被停用
+func NewDomainVerifyJob(app app.Application, interval time.Duration) Job {
+	return Job{
+		Name:     "domain-verify",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return app.Commands.ReverifyShortDomains.Handle(ctx, command.ReverifyShortDomains{Now: time.Now()})
+		},
+	}
+}
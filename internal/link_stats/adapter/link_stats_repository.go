@@ -9,6 +9,7 @@ import (
 	"shortlink/internal/base/toolkit"
 	"shortlink/internal/link/constant"
 	"shortlink/internal/link/domain/event"
+	"shortlink/internal/link/domain/link"
 	"shortlink/internal/link_stats/adapter/po"
 )
 
@@ -22,8 +23,12 @@ func NewLinkStatsRepository(db *gorm.DB, rdb *redis.Client) LinkStatsRepository
 	return LinkStatsRepository{db: db, rdb: rdb}
 }
 
+// SaveLinkStats 保存访问统计
+//
+// 不同域名下可以存在相同的短链接，统计数据都以 domain + shortUri 区分
 func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.UserVisitInfo) error {
-	lockKey := constant.LockGidUpdateKey + statsInfo.ShortUri
+	key := link.Key(statsInfo.Domain, statsInfo.ShortUri)
+	lockKey := constant.LockGidUpdateKey + key
 	if _, err := r.locker.Acquire(ctx, lockKey, -1); err != nil {
 		return err
 	}
@@ -33,7 +38,7 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 		}
 	}()
 
-	shortUri := statsInfo.ShortUri
+	domain, shortUri := statsInfo.Domain, statsInfo.ShortUri
 	currentDate := statsInfo.CurrentDate
 	hour := currentDate.Hour() + 1
 	weekDay := int(currentDate.Weekday())
@@ -41,14 +46,14 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 	// 访问统计
 	// 确定两个值的信息，uvFirstFlag 和 uipFirstFlag
 	uv, uip := 0, 0
-	uvAdded, err := r.rdb.SAdd(ctx, constant.LinkStatsUvKey+shortUri, statsInfo.UV).Result()
+	uvAdded, err := r.rdb.SAdd(ctx, constant.LinkStatsUvKey+key, statsInfo.UV).Result()
 	if err != nil {
 		return err
 	}
 	if uvAdded > 0 {
 		uv = 1
 	}
-	uipAdded, err := r.rdb.SAdd(ctx, constant.LinkStatsUipKey+shortUri, statsInfo.RemoteAddr).Result()
+	uipAdded, err := r.rdb.SAdd(ctx, constant.LinkStatsUipKey+key, statsInfo.RemoteAddr).Result()
 	if err != nil {
 		return err
 	}
@@ -61,11 +66,12 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 		Uip:      uip,
 		Hour:     hour,
 		Week:     weekDay,
+		Domain:   domain,
 		ShortUri: shortUri,
 		Date:     currentDate,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "short_uri"}, {Name: "date"}, {Name: "hour"}},
+		Columns: []clause.Column{{Name: "domain"}, {Name: "short_uri"}, {Name: "date"}, {Name: "hour"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"pv":  gorm.Expr("pv + ?", 1),
 			"uv":  gorm.Expr("uv + ?", uv),
@@ -80,10 +86,11 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 		TodayUv:  uv,
 		TodayUip: uip,
 		Date:     currentDate,
+		Domain:   domain,
 		ShortUri: shortUri,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "short_uri"}, {Name: "date"}},
+		Columns: []clause.Column{{Name: "domain"}, {Name: "short_uri"}, {Name: "date"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"today_pv":  gorm.Expr("today_pv + ?", 1),
 			"today_uv":  gorm.Expr("today_uv + ?", uv),
@@ -95,6 +102,7 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 	// 地区信息
 	location := toolkit.GetLocationByIP(statsInfo.RemoteAddr)
 	linkLocaleStatPo := po.LinkLocaleStat{
+		Domain:   domain,
 		ShortUri: shortUri,
 		Date:     currentDate,
 		Cnt:      1,
@@ -103,7 +111,7 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 		Country:  location.Country,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "short_uri"}, {Name: "date"}, {Name: "province"}},
+		Columns:   []clause.Column{{Name: "domain"}, {Name: "short_uri"}, {Name: "date"}, {Name: "province"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{"cnt": gorm.Expr("cnt + ?", 1)}),
 	}).Create(&linkLocaleStatPo).Error; err != nil {
 		return err
@@ -112,11 +120,12 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 	linkOsStatPo := po.LinkOsStat{
 		Os:       statsInfo.OS,
 		Cnt:      1,
+		Domain:   domain,
 		ShortUri: shortUri,
 		Date:     currentDate,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "os"}, {Name: "short_uri"}, {Name: "date"}},
+		Columns:   []clause.Column{{Name: "os"}, {Name: "domain"}, {Name: "short_uri"}, {Name: "date"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{"cnt": gorm.Expr("cnt + ?", 1)}),
 	}).Create(&linkOsStatPo).Error; err != nil {
 		return err
@@ -125,11 +134,12 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 	linkBrowserStatPo := po.LinkBrowserStat{
 		Browser:  statsInfo.Browser,
 		Cnt:      1,
+		Domain:   domain,
 		ShortUri: shortUri,
 		Date:     currentDate,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "browser"}, {Name: "short_uri"}, {Name: "date"}},
+		Columns:   []clause.Column{{Name: "browser"}, {Name: "domain"}, {Name: "short_uri"}, {Name: "date"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{"cnt": gorm.Expr("cnt + ?", 1)}),
 	}).Create(&linkBrowserStatPo).Error; err != nil {
 		return err
@@ -138,11 +148,12 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 	linkDeviceStatPo := po.LinkDeviceStat{
 		Device:   statsInfo.Device,
 		Cnt:      1,
+		Domain:   domain,
 		ShortUri: shortUri,
 		Date:     currentDate,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "device"}, {Name: "short_uri"}, {Name: "date"}},
+		Columns:   []clause.Column{{Name: "device"}, {Name: "domain"}, {Name: "short_uri"}, {Name: "date"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{"cnt": gorm.Expr("cnt + ?", 1)}),
 	}).Create(&linkDeviceStatPo).Error; err != nil {
 		return err
@@ -151,17 +162,47 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 	linkNetworkStatPo := po.LinkNetworkStat{
 		Network:  statsInfo.Network,
 		Cnt:      1,
+		Domain:   domain,
 		ShortUri: shortUri,
 		Date:     currentDate,
 	}
 	if err := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "network"}, {Name: "short_uri"}, {Name: "date"}},
+		Columns:   []clause.Column{{Name: "network"}, {Name: "domain"}, {Name: "short_uri"}, {Name: "date"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{"cnt": gorm.Expr("cnt + ?", 1)}),
 	}).Create(&linkNetworkStatPo).Error; err != nil {
 		return err
 	}
+	// 分流信息
+	if statsInfo.Variant != "" {
+		variantUv := 0
+		variantUvAdded, err := r.rdb.SAdd(ctx, constant.LinkStatsVariantUvKey+key+":"+statsInfo.Variant, statsInfo.UV).Result()
+		if err != nil {
+			return err
+		}
+		if variantUvAdded > 0 {
+			variantUv = 1
+		}
+		linkVariantStatPo := po.LinkVariantStat{
+			Domain:   domain,
+			ShortUri: shortUri,
+			Date:     currentDate,
+			Variant:  statsInfo.Variant,
+			Pv:       1,
+			Uv:       variantUv,
+		}
+		if err := r.db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "domain"}, {Name: "short_uri"}, {Name: "date"}, {Name: "variant"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"pv": gorm.Expr("pv + ?", 1),
+				"uv": gorm.Expr("uv + ?", variantUv),
+			}),
+		}).Create(&linkVariantStatPo).Error; err != nil {
+			return err
+		}
+	}
 	// 访问日志
 	linkAccessLogPo := po.LinkAccessLog{
+		Domain:   domain,
 		ShortUri: shortUri,
 		User:     statsInfo.UV,
 		IP:       statsInfo.RemoteAddr,
@@ -175,12 +216,12 @@ func (r LinkStatsRepository) SaveLinkStats(ctx context.Context, statsInfo event.
 		return err
 	}
 	// 更新shortLink表中的状态pv, uv, uip
-	linkGotoPo := po.LinkGoto{ShortUri: shortUri}
-	if err := r.db.First(&linkGotoPo).Error; err != nil {
+	var linkGotoPo po.LinkGoto
+	if err := r.db.Where("domain = ? and short_uri = ?", domain, shortUri).First(&linkGotoPo).Error; err != nil {
 		return err
 	}
 	r.db.Model(&po.Link{}).
-		Where("gid = ? and short_uri = ?", linkGotoPo.Gid, shortUri).
+		Where("gid = ? and domain = ? and short_uri = ?", linkGotoPo.Gid, domain, shortUri).
 		Updates(map[string]interface{}{
 			"total_pv":  gorm.Expr("total_pv + ?", 1),
 			"total_uv":  gorm.Expr("total_uv + ?", uv),
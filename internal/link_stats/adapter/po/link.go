@@ -12,6 +12,7 @@ const TableNameLink = "link"
 type Link struct {
 	ID          int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`
 	Gid         string         `gorm:"column:gid;not null;comment:分组标识" json:"gid"`
+	Domain      string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`
 	ShortUri    string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`
 	OriginalUrl string         `gorm:"column:origin_url;not null;comment:原始链接" json:"origin_url"`
 	Favicon     string         `gorm:"column:favicon;comment:网站图标" json:"favicon"`
@@ -10,6 +10,7 @@ const TableNameLinkAccessLog = "link_access_logs"
 // LinkAccessLog mapped from table <link_access_logs>
 type LinkAccessLog struct {
 	ID         int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`                 // ID
+	Domain     string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`     // 自定义域名
 	ShortUri   string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`                       // 完整短链接
 	User       string         `gorm:"column:user;comment:用户信息" json:"user"`                                         // 用户信息
 	IP         string         `gorm:"column:ip;comment:IP" json:"ip"`                                               // IP
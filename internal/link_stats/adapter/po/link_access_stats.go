@@ -10,6 +10,7 @@ const TableNameLinkAccessStat = "link_access_stats"
 // LinkAccessStat mapped from table <link_access_stats>
 type LinkAccessStat struct {
 	ID         int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`                 // ID
+	Domain     string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`     // 自定义域名
 	ShortUri   string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`                       // 完整短链接
 	Date       time.Time      `gorm:"column:date;default:CURRENT_DATE;comment:日期" json:"date"`                      // 日期
 	Pv         int            `gorm:"column:pv;comment:访问量" json:"pv"`                                              // 访问量
@@ -10,6 +10,7 @@ const TableNameLinkBrowserStat = "link_browser_stats"
 // LinkBrowserStat mapped from table <link_browser_stats>
 type LinkBrowserStat struct {
 	ID             int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`                 // ID
+	Domain         string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`     // 自定义域名
 	ShortUri       string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`                       // 完整短链接
 	Date           time.Time      `gorm:"column:date;default:CURRENT_DATE;comment:日期" json:"date"`                      // 日期
 	Cnt            int            `gorm:"column:cnt;comment:访问量" json:"cnt"`                                            // 访问量
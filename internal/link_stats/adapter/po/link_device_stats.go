@@ -10,6 +10,7 @@ const TableNameLinkDeviceStat = "link_device_stats"
 // LinkDeviceStat mapped from table <link_device_stats>
 type LinkDeviceStat struct {
 	ID         int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`                 // ID
+	Domain     string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`     // 自定义域名
 	ShortUri   string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`                       // 完整短链接
 	Date       time.Time      `gorm:"column:date;default:CURRENT_DATE;comment:日期" json:"date"`                      // 日期
 	Cnt        int            `gorm:"column:cnt;comment:访问量" json:"cnt"`                                            // 访问量
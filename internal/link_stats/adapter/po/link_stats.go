@@ -11,6 +11,7 @@ const TableNameLinkStats = "link_stats"
 type LinkStats struct {
 	ID          int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`
 	Gid         string         `gorm:"column:gid;not null;comment:分组标识" json:"gid"`
+	Domain      string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`
 	ShortUri    string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`
 	ClickNum    int            `gorm:"column:click_num;comment:点击量" json:"click_num"`
 	TotalPv     int            `gorm:"column:total_pv;comment:历史PV" json:"total_pv"`
@@ -10,6 +10,7 @@ const TableNameLinkStatsToday = "link_stats_today"
 // LinkStatsToday mapped from table <link_stats_today>
 type LinkStatsToday struct {
 	ID         int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`                 // ID
+	Domain     string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`     // 自定义域名
 	ShortUri   string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`                       // 短链接
 	Date       time.Time      `gorm:"column:date;not null;default:CURRENT_DATE;comment:日期" json:"date"`             // 日期
 	TodayPv    int            `gorm:"column:today_pv;comment:今日PV" json:"today_pv"`                                 // 今日PV
@@ -0,0 +1,27 @@
+package po
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const TableNameLinkVariantStat = "link_variant_stats"
+
+// LinkVariantStat mapped from table <link_variant_stats>
+type LinkVariantStat struct {
+	ID         int            `gorm:"column:id;primaryKey;autoIncrement:true;comment:ID" json:"id"`                 // ID
+	Domain     string         `gorm:"column:domain;not null;default:'';comment:自定义域名 空字符串表示默认域名" json:"domain"`     // 自定义域名
+	ShortUri   string         `gorm:"column:short_uri;not null;comment:短链接" json:"short_uri"`                       // 短链接
+	Date       time.Time      `gorm:"column:date;not null;default:CURRENT_DATE;comment:日期" json:"date"`             // 日期
+	Variant    string         `gorm:"column:variant;not null;comment:分流名称" json:"variant"`                          // 分流名称
+	Pv         int            `gorm:"column:pv;comment:访问量" json:"pv"`                                              // 访问量
+	Uv         int            `gorm:"column:uv;comment:独立访客数" json:"uv"`                                            // 独立访客数
+	CreateTime time.Time      `gorm:"column:create_time;default:CURRENT_TIMESTAMP;comment:创建时间" json:"create_time"` // 创建时间
+	UpdateTime time.Time      `gorm:"column:update_time;default:CURRENT_TIMESTAMP;comment:修改时间" json:"update_time"` // 修改时间
+	DeleteTime gorm.DeletedAt `gorm:"column:delete_time;comment:删除时间" json:"delete_time"`                           // 删除时间
+}
+
+// TableName LinkVariantStat's table name
+func (*LinkVariantStat) TableName() string {
+	return TableNameLinkVariantStat
+}
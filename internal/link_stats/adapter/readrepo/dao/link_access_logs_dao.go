@@ -5,6 +5,7 @@ import (
 	"gorm.io/gorm"
 	"shortlink/internal/base/types"
 	"shortlink/internal/link_stats/adapter/po"
+	"time"
 )
 
 type LinkAccessLogsDao struct {
@@ -300,3 +301,134 @@ LIMIT ? OFFSET ?;
 		Records: result,
 	}, nil
 }
+
+// PageByCursor 按 (create_time, id) 倒序获取游标之后的访问日志
+func (d LinkAccessLogsDao) PageByCursor(
+	ctx context.Context,
+	param LinkQueryParam,
+	after *types.Cursor,
+	limit int,
+) ([]po.LinkAccessLog, error) {
+	rawSql := `
+SELECT
+	tlal.*
+FROM
+	t_link tl INNER JOIN
+	t_link_access_logs tlal ON tl.full_short_url = tlal.full_short_url
+WHERE
+	tlal.full_short_url = ?
+	AND tl.gid = ?
+	AND tl.del_flag = '0'
+	AND tl.enable_status = ?
+	AND tlal.create_time BETWEEN ? and ?
+`
+	args := []interface{}{param.FullShortUrl, param.Gid, param.Status, param.StartDate, param.EndDate}
+	rawSql, args = withAccessLogCursor(rawSql, args, after, limit)
+
+	var result []po.LinkAccessLog
+	err := d.db.WithContext(ctx).Raw(rawSql, args...).Scan(&result).Error
+	return result, err
+}
+
+// PageGroupByCursor 按 (create_time, id) 倒序获取游标之后的分组访问日志
+func (d LinkAccessLogsDao) PageGroupByCursor(
+	ctx context.Context,
+	param LinkGroupQueryParam,
+	after *types.Cursor,
+	limit int,
+) ([]po.LinkAccessLog, error) {
+	rawSql := `
+SELECT 
+    tlal.* 
+FROM 
+    t_link tl 
+    INNER JOIN t_link_access_logs tlal ON tl.full_short_url = tlal.full_short_url 
+WHERE 
+    tl.gid = ? 
+    AND tl.enable_status = ? 
+    AND tlal.create_time BETWEEN ? and ? 
+`
+	args := []interface{}{param.Gid, param.Status, param.StartDate, param.EndDate}
+	rawSql, args = withAccessLogCursor(rawSql, args, after, limit)
+
+	var result []po.LinkAccessLog
+	err := d.db.WithContext(ctx).Raw(rawSql, args...).Scan(&result).Error
+	return result, err
+}
+
+// withAccessLogCursor 追加游标条件、排序和数量限制
+func withAccessLogCursor(rawSql string, args []interface{}, after *types.Cursor, limit int) (string, []interface{}) {
+	if after != nil {
+		rawSql += "    AND (tlal.create_time, tlal.id) < (?, ?)\n"
+		args = append(args, after.Time, after.Id)
+	}
+	rawSql += "ORDER BY\n    tlal.create_time DESC, tlal.id DESC\nLIMIT ?;\n"
+	return rawSql, append(args, limit)
+}
+
+// AccessLogDTO 导出的访问日志
+type AccessLogDTO struct {
+	Id         int       `json:"id"`
+	ShortUri   string    `json:"short_uri"`
+	Ip         string    `json:"ip"`
+	Browser    string    `json:"browser"`
+	Os         string    `json:"os"`
+	Network    string    `json:"network"`
+	Device     string    `json:"device"`
+	Locale     string    `json:"locale"`
+	User       string    `json:"user"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// ScanAccessLogs 按 (create_time, id) 正序分批遍历指定时间内的访问日志，FullShortUrl 为空时遍历整个分组
+//
+// 每批使用键集分页重新查询，不会长时间占用数据库连接
+func (d LinkAccessLogsDao) ScanAccessLogs(
+	ctx context.Context,
+	param LinkQueryParam,
+	batchSize int,
+	fn func([]AccessLogDTO) error,
+) error {
+	rawSql := `
+SELECT
+    tlal.id, tl.short_uri, tlal.ip, tlal.browser, tlal.os, tlal.network, tlal.device, tlal.locale, tlal."user", tlal.create_time
+FROM
+    t_link tl INNER JOIN
+    t_link_access_logs tlal ON tl.full_short_url = tlal.full_short_url
+WHERE
+    tl.gid = ?
+    AND tlal.create_time BETWEEN ? and ?
+`
+	args := []interface{}{param.Gid, param.StartDate, param.EndDate}
+	if param.FullShortUrl != "" {
+		rawSql += "    AND tlal.full_short_url = ?\n"
+		args = append(args, param.FullShortUrl)
+	}
+
+	var last *AccessLogDTO
+	for {
+		batchSql := rawSql
+		batchArgs := append([]interface{}{}, args...)
+		if last != nil {
+			batchSql += "    AND (tlal.create_time, tlal.id) > (?, ?)\n"
+			batchArgs = append(batchArgs, last.CreateTime, last.Id)
+		}
+		batchSql += "ORDER BY\n    tlal.create_time, tlal.id\nLIMIT ?;\n"
+		batchArgs = append(batchArgs, batchSize)
+
+		var result []AccessLogDTO
+		if err := d.db.WithContext(ctx).Raw(batchSql, batchArgs...).Scan(&result).Error; err != nil {
+			return err
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+		if len(result) < batchSize {
+			return nil
+		}
+		last = &result[len(result)-1]
+	}
+}
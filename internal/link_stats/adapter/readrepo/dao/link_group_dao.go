@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// LinkGroupDao 分组归属于用户服务，两个服务共用数据库，这里只查询分组的所有者
+type LinkGroupDao struct {
+	db *gorm.DB
+}
+
+func NewLinkGroupDao(db *gorm.DB) LinkGroupDao {
+	return LinkGroupDao{db: db}
+}
+
+// OwnedBy 分组是否属于指定用户，分组不存在时返回 false
+func (d *LinkGroupDao) OwnedBy(ctx context.Context, gid, username string) (bool, error) {
+	rawSql := `
+SELECT
+    COUNT(1)
+FROM
+    t_group tg
+WHERE
+    tg.gid = ?
+    AND tg.username = ?
+    AND tg.delete_time IS NULL;
+`
+	var count int64
+	err := d.db.WithContext(ctx).Raw(rawSql, gid, username).Scan(&count).Error
+	return count > 0, err
+}
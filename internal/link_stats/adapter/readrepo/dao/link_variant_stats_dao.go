@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"shortlink/internal/link_stats/adapter/po"
+)
+
+type LinkVariantStatDao struct {
+	db *gorm.DB
+}
+
+func NewLinkVariantStatDao(db *gorm.DB) LinkVariantStatDao {
+	return LinkVariantStatDao{db: db}
+}
+
+// ListVariantStatByLink 根据短链接获取指定日期内各分流的访问数据
+//
+// 分流统计以 domain + short_uri 记录，通过 t_link 从完整短链接换算
+func (d *LinkVariantStatDao) ListVariantStatByLink(ctx context.Context, param LinkQueryParam) ([]po.LinkVariantStat, error) {
+	rawSql := `
+SELECT
+    tlvs.variant,
+    SUM(tlvs.pv) AS pv,
+    SUM(tlvs.uv) AS uv
+FROM
+    t_link tl INNER JOIN
+    t_link_variant_stats tlvs ON tl.domain = tlvs.domain AND tl.short_uri = tlvs.short_uri
+WHERE
+    tl.full_short_url = ?
+    AND tl.gid = ?
+    AND tl.delete_time IS NULL
+    AND tlvs.delete_time IS NULL
+    AND tlvs.date BETWEEN ? and ?
+GROUP BY
+    tlvs.variant
+ORDER BY
+    tlvs.variant;
+`
+	var result []po.LinkVariantStat
+	err := d.db.WithContext(ctx).
+		Raw(rawSql, param.FullShortUrl, param.Gid, param.StartDate, param.EndDate).Scan(&result).Error
+	return result, err
+}
@@ -19,6 +19,8 @@ type LinkStatsQuery struct {
 	linkOsStatDao      dao.LinkOsStatDao
 	linkDeviceStatDao  dao.LinkDeviceStatDao
 	linkNetworkStatDao dao.LinkNetworkStatDao
+	linkVariantStatDao dao.LinkVariantStatDao
+	linkGroupDao       dao.LinkGroupDao
 }
 
 func NewLinkStatsQuery(db *gorm.DB) LinkStatsQuery {
@@ -30,6 +32,8 @@ func NewLinkStatsQuery(db *gorm.DB) LinkStatsQuery {
 		linkOsStatDao:      dao.NewLinkOsStatDao(db),
 		linkDeviceStatDao:  dao.NewLinkDeviceStatDao(db),
 		linkNetworkStatDao: dao.NewLinkNetworkStatDao(db),
+		linkVariantStatDao: dao.NewLinkVariantStatDao(db),
+		linkGroupDao:       dao.NewLinkGroupDao(db),
 	}
 }
 
@@ -241,6 +245,27 @@ func (q LinkStatsQuery) GetLinkStats(ctx context.Context, param query.GetLinkSta
 		}
 		networks = append(networks, network)
 	}
+	// 分流访问详情
+	var variants []query.LinkStatsVariant
+	variantStat, err := q.linkVariantStatDao.ListVariantStatByLink(ctx, queryParam)
+	if err != nil {
+		return nil, err
+	}
+	var variantTotal int
+	for _, item := range variantStat {
+		variantTotal += item.Pv
+	}
+	for _, item := range variantStat {
+		ratio := float64(item.Pv) / float64(variantTotal)
+		actualRatio := math.Round(ratio*100.0) / 100.0
+		variant := query.LinkStatsVariant{
+			Variant: item.Variant,
+			Pv:      item.Pv,
+			Uv:      item.Uv,
+			Ratio:   actualRatio,
+		}
+		variants = append(variants, variant)
+	}
 	// 组装返回数据
 	res = &query.LinkStats{
 		Pv:              pvUvUidStat.Pv,
@@ -256,6 +281,7 @@ func (q LinkStatsQuery) GetLinkStats(ctx context.Context, param query.GetLinkSta
 		VisitorTypeStat: uvTypes,
 		DeviceStat:      devices,
 		NetworkStat:     networks,
+		VariantStat:     variants,
 	}
 	return
 }
@@ -516,6 +542,69 @@ func (q LinkStatsQuery) GroupLinkStatsAccessRecord(
 	})
 }
 
+// CursorLinkStatsAccessRecord 按游标获取单个短链接指定时间内访问记录
+func (q LinkStatsQuery) CursorLinkStatsAccessRecord(
+	ctx context.Context,
+	param query.CursorLinkStatsAccessRecord,
+	after *types.Cursor,
+	limit int,
+) ([]query.LinkStatsAccessRecord, error) {
+
+	queryParam := dao.LinkQueryParam{
+		FullShortUrl: param.FullShortUrl,
+		Gid:          param.Gid,
+		Status:       string(link.StatusActive),
+		StartDate:    param.StartDate,
+		EndDate:      param.EndDate,
+	}
+
+	logPos, err := q.linkAccessLogsDao.PageByCursor(ctx, queryParam, after, limit)
+	if err != nil || len(logPos) == 0 {
+		return nil, err
+	}
+
+	res, err := q.buildStatAccessRecordResult(
+		&types.PageResp[po.LinkAccessLog]{Total: int64(len(logPos)), Records: logPos},
+		func(users []string) (userTypes []dao.UserType, err error) {
+			return q.linkAccessLogsDao.SelectUvTypeByUsers(ctx, queryParam, users)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return res.Records, nil
+}
+
+// CursorGroupLinkStatsAccessRecord 按游标获取分组指定时间内访问记录
+func (q LinkStatsQuery) CursorGroupLinkStatsAccessRecord(
+	ctx context.Context,
+	param query.CursorGroupLinkStatsAccessRecord,
+	after *types.Cursor,
+	limit int,
+) ([]query.LinkStatsAccessRecord, error) {
+
+	queryParam := dao.LinkGroupQueryParam{
+		Gid:       param.Gid,
+		Status:    string(link.StatusActive),
+		StartDate: param.StartDate,
+		EndDate:   param.EndDate,
+	}
+
+	logPos, err := q.linkAccessLogsDao.PageGroupByCursor(ctx, queryParam, after, limit)
+	if err != nil || len(logPos) == 0 {
+		return nil, err
+	}
+
+	res, err := q.buildStatAccessRecordResult(
+		&types.PageResp[po.LinkAccessLog]{Total: int64(len(logPos)), Records: logPos},
+		func(users []string) (userTypes []dao.UserType, err error) {
+			return q.linkAccessLogsDao.SelectGroupUvTypeByUsers(ctx, queryParam, users)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return res.Records, nil
+}
+
 func (q LinkStatsQuery) buildStatAccessRecordResult(
 	logPoPage *types.PageResp[po.LinkAccessLog],
 	getUserTypeFn func(users []string) (userTypes []dao.UserType, err error),
@@ -550,6 +639,7 @@ func (q LinkStatsQuery) buildStatAccessRecordResult(
 			Locale:     logPo.Locale,
 			User:       logPo.User,
 			AccessTime: logPo.CreateTime,
+			Id:         logPo.ID,
 		}
 		// 加上用户类型信息
 		if userType, found := userTypeMap[logPo.User]; found {
@@ -597,3 +687,43 @@ func (q LinkStatsQuery) buildStatAccessRecordResult(
 //	}
 //	return
 //}
+
+// exportAccessLogBatchSize 导出访问日志时每批查询的数量
+const exportAccessLogBatchSize = 1000
+
+// GroupOwnedBy 分组是否属于指定用户
+func (q LinkStatsQuery) GroupOwnedBy(ctx context.Context, gid, username string) (bool, error) {
+	return q.linkGroupDao.OwnedBy(ctx, gid, username)
+}
+
+// ScanAccessLogs 分批遍历指定时间内的访问日志
+func (q LinkStatsQuery) ScanAccessLogs(
+	ctx context.Context,
+	param query.ExportAccessLogs,
+	fn func([]query.AccessLog) error,
+) error {
+	queryParam := dao.LinkQueryParam{
+		FullShortUrl: param.FullShortUrl,
+		Gid:          param.Gid,
+		StartDate:    param.StartDate,
+		EndDate:      param.EndDate,
+	}
+	return q.linkAccessLogsDao.ScanAccessLogs(ctx, queryParam, exportAccessLogBatchSize, func(logs []dao.AccessLogDTO) error {
+		records := make([]query.AccessLog, 0, len(logs))
+		for _, l := range logs {
+			records = append(records, query.AccessLog{
+				Id:         l.Id,
+				ShortUri:   l.ShortUri,
+				Ip:         l.Ip,
+				Browser:    l.Browser,
+				Os:         l.Os,
+				Network:    l.Network,
+				Device:     l.Device,
+				Locale:     l.Locale,
+				User:       l.User,
+				AccessTime: l.CreateTime,
+			})
+		}
+		return fn(records)
+	})
+}
@@ -11,4 +11,9 @@ type Queries struct {
 	GroupLinkStats             query.GroupLinkStatsHandler
 	GetLinkStatsAccessRecord   query.GetLinkStatsAccessRecordHandler
 	GroupLinkStatsAccessRecord query.GroupLinkStatsAccessRecordHandler
+
+	CursorLinkStatsAccessRecord      query.CursorLinkStatsAccessRecordHandler
+	CursorGroupLinkStatsAccessRecord query.CursorGroupLinkStatsAccessRecordHandler
+
+	ExportAccessLogs query.ExportAccessLogsHandler
 }
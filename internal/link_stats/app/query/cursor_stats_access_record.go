@@ -0,0 +1,131 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/metrics"
+	"shortlink/internal/base/types"
+	"time"
+)
+
+const (
+	// accessRecordCursorKind 单个短链接访问记录的游标标识
+	accessRecordCursorKind = "access_record"
+	// groupAccessRecordCursorKind 分组访问记录的游标标识
+	groupAccessRecordCursorKind = "group_access_record"
+)
+
+// accessRecordKey 访问记录按访问时间倒序，时间相同时按日志ID区分
+func accessRecordKey(r LinkStatsAccessRecord) (time.Time, int64) {
+	return r.AccessTime, int64(r.Id)
+}
+
+type cursorLinkStatsAccessRecordHandler struct {
+	readModel CursorLinkStatsAccessRecordReadModel
+	codec     types.CursorCodec
+}
+
+type CursorLinkStatsAccessRecordHandler decorator.QueryHandler[CursorLinkStatsAccessRecord, *types.CursorResp[LinkStatsAccessRecord]]
+
+func NewCursorLinkStatsAccessRecordHandler(
+	readModel CursorLinkStatsAccessRecordReadModel,
+	codec types.CursorCodec,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) CursorLinkStatsAccessRecordHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[CursorLinkStatsAccessRecord, *types.CursorResp[LinkStatsAccessRecord]](
+		cursorLinkStatsAccessRecordHandler{readModel: readModel, codec: codec},
+		logger,
+		metricsClient,
+	)
+}
+
+// CursorLinkStatsAccessRecord 按游标获取单个短链接指定时间内访问记录
+type CursorLinkStatsAccessRecord struct {
+	types.CursorReq
+	// 完整短链接
+	FullShortUrl string
+	// 分组ID
+	Gid string
+	// 开始日期
+	StartDate time.Time
+	// 结束日期
+	EndDate time.Time
+}
+
+type CursorLinkStatsAccessRecordReadModel interface {
+	// CursorLinkStatsAccessRecord 查询排在 after 之后的 limit 条访问记录，after 为 nil 时从头开始
+	CursorLinkStatsAccessRecord(ctx context.Context, param CursorLinkStatsAccessRecord, after *types.Cursor, limit int) ([]LinkStatsAccessRecord, error)
+}
+
+func (h cursorLinkStatsAccessRecordHandler) Handle(ctx context.Context, q CursorLinkStatsAccessRecord) (*types.CursorResp[LinkStatsAccessRecord], error) {
+	after, err := h.codec.Decode(accessRecordCursorKind, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 多查询一条用于判断是否还有下一页
+	records, err := h.readModel.CursorLinkStatsAccessRecord(ctx, q, after, q.Limit()+1)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewCursorResp(h.codec, accessRecordCursorKind, records, q.Limit(), accessRecordKey)
+}
+
+type cursorGroupLinkStatsAccessRecordHandler struct {
+	readModel CursorGroupLinkStatsAccessRecordReadModel
+	codec     types.CursorCodec
+}
+
+type CursorGroupLinkStatsAccessRecordHandler decorator.QueryHandler[CursorGroupLinkStatsAccessRecord, *types.CursorResp[LinkStatsAccessRecord]]
+
+func NewCursorGroupLinkStatsAccessRecordHandler(
+	readModel CursorGroupLinkStatsAccessRecordReadModel,
+	codec types.CursorCodec,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) CursorGroupLinkStatsAccessRecordHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[CursorGroupLinkStatsAccessRecord, *types.CursorResp[LinkStatsAccessRecord]](
+		cursorGroupLinkStatsAccessRecordHandler{readModel: readModel, codec: codec},
+		logger,
+		metricsClient,
+	)
+}
+
+// CursorGroupLinkStatsAccessRecord 按游标获取分组指定时间内访问记录
+type CursorGroupLinkStatsAccessRecord struct {
+	types.CursorReq
+	// 分组ID
+	Gid string
+	// 开始日期
+	StartDate time.Time
+	// 结束日期
+	EndDate time.Time
+}
+
+type CursorGroupLinkStatsAccessRecordReadModel interface {
+	// CursorGroupLinkStatsAccessRecord 查询排在 after 之后的 limit 条访问记录，after 为 nil 时从头开始
+	CursorGroupLinkStatsAccessRecord(ctx context.Context, param CursorGroupLinkStatsAccessRecord, after *types.Cursor, limit int) ([]LinkStatsAccessRecord, error)
+}
+
+func (h cursorGroupLinkStatsAccessRecordHandler) Handle(ctx context.Context, q CursorGroupLinkStatsAccessRecord) (*types.CursorResp[LinkStatsAccessRecord], error) {
+	after, err := h.codec.Decode(groupAccessRecordCursorKind, q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := h.readModel.CursorGroupLinkStatsAccessRecord(ctx, q, after, q.Limit()+1)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewCursorResp(h.codec, groupAccessRecordCursorKind, records, q.Limit(), accessRecordKey)
+}
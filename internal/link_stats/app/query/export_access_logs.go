@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"shortlink/internal/base/decorator"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/metrics"
+	"time"
+)
+
+// maxExportAccessLogRange 单次导出访问日志的最大时间跨度
+const maxExportAccessLogRange = 366 * 24 * time.Hour
+
+type exportAccessLogsHandler struct {
+	readModel ExportAccessLogsReadModel
+}
+
+// ExportAccessLogs 导出分组或单个短链接指定时间内的原始访问日志
+type ExportAccessLogs struct {
+	// 分组ID
+	Gid string
+	// 完整短链接 为空时导出整个分组
+	FullShortUrl string
+	// 开始日期
+	StartDate time.Time
+	// 结束日期
+	EndDate time.Time
+}
+
+// AccessLogStream 分批输出访问日志，由调用方在写入响应时执行，避免把全部日志加载到内存
+type AccessLogStream func(ctx context.Context, fn func([]AccessLog) error) error
+
+type ExportAccessLogsHandler decorator.QueryHandler[ExportAccessLogs, AccessLogStream]
+
+func NewExportAccessLogsHandler(
+	readModel ExportAccessLogsReadModel,
+	logger *slog.Logger,
+	metricsClient metrics.Client,
+) ExportAccessLogsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[ExportAccessLogs, AccessLogStream](
+		exportAccessLogsHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+type ExportAccessLogsReadModel interface {
+	// GroupOwnedBy 分组是否属于指定用户
+	GroupOwnedBy(ctx context.Context, gid, username string) (bool, error)
+	// ScanAccessLogs 按访问时间正序分批遍历访问日志
+	ScanAccessLogs(ctx context.Context, param ExportAccessLogs, fn func([]AccessLog) error) error
+}
+
+// Handle 只校验参数和分组归属，日志在执行返回的 AccessLogStream 时才查询
+//
+// 访问日志包含访客 IP 等信息，只有分组所有者可以导出
+func (h exportAccessLogsHandler) Handle(ctx context.Context, q ExportAccessLogs) (AccessLogStream, error) {
+	if q.Gid == "" {
+		return nil, errno.NewRequestError("gid is required")
+	}
+	if q.EndDate.Before(q.StartDate) {
+		return nil, errno.LinkInvalidSearchTime
+	}
+	if q.EndDate.Sub(q.StartDate) > maxExportAccessLogRange {
+		return nil, errno.NewRequestError("导出访问日志的时间跨度不能超过一年")
+	}
+	// 不属于当前用户的分组视为不存在
+	username, _ := ctx.Value("username").(string)
+	if username == "" {
+		return nil, errno.ErrUnauthorized
+	}
+	owned, err := h.readModel.GroupOwnedBy(ctx, q.Gid, username)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, errno.LinkGroupNotExists
+	}
+
+	return func(ctx context.Context, fn func([]AccessLog) error) error {
+		return h.readModel.ScanAccessLogs(ctx, q, fn)
+	}, nil
+}
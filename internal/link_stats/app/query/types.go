@@ -22,6 +22,8 @@ type LinkStatsAccessRecord struct {
 	User string
 	// 访问时间
 	AccessTime time.Time
+	// 访问日志ID 游标分页使用
+	Id int
 }
 
 // LinkStats 短链接监控统计
@@ -52,6 +54,8 @@ type LinkStats struct {
 	DeviceStat []LinkStatsDevice `json:"deviceStat"`
 	// 网络统计
 	NetworkStat []LinkStatsNetwork `json:"networkStat"`
+	// 分流统计 未配置分流时为空
+	VariantStat []LinkStatsVariant `json:"variantStat,omitempty"`
 }
 
 // LinkStatsAccessDaily 短链接监控访问统计基础响应
@@ -133,3 +137,33 @@ type LinkStatsUv struct {
 	// 占比
 	Ratio float64 `json:"ratio"`
 }
+
+// LinkStatsVariant 分流统计响应
+type LinkStatsVariant struct {
+	// 分流名称
+	Variant string `json:"variant"`
+	// PV
+	Pv int `json:"pv"`
+	// UV
+	Uv int `json:"uv"`
+	// PV 占比
+	Ratio float64 `json:"ratio"`
+}
+
+// AccessLog 导出的原始访问日志
+type AccessLog struct {
+	// 访问日志ID
+	Id int
+	// 短链接
+	ShortUri string
+	Ip       string
+	Browser  string
+	Os       string
+	Network  string
+	Device   string
+	Locale   string
+	// 用户信息
+	User string
+	// 访问时间
+	AccessTime time.Time
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"gorm.io/gorm"
 	"log/slog"
+	"shortlink/internal/base"
 	"shortlink/internal/base/metrics"
+	"shortlink/internal/base/types"
 	"shortlink/internal/link_stats/adapter/readrepo"
 	"shortlink/internal/link_stats/app"
 	"shortlink/internal/link_stats/app/query"
@@ -14,6 +16,7 @@ func NewLinkStatsApplication(db *gorm.DB) app.Application {
 	logger := slog.Default()
 	metricsClient := metrics.NoOp{}
 	readModel := readrepo.NewLinkStatsQuery(db)
+	cursorCodec := types.NewCursorCodec(base.GetConfig().Pagination.CursorSecret)
 
 	return app.Application{
 		Queries: app.Queries{
@@ -21,6 +24,11 @@ func NewLinkStatsApplication(db *gorm.DB) app.Application {
 			GroupLinkStats:             query.NewGroupLinkStatsHandler(readModel, logger, metricsClient),
 			GetLinkStatsAccessRecord:   query.NewGetLinkStatsAccessRecordHandler(readModel, logger, metricsClient),
 			GroupLinkStatsAccessRecord: query.NewGroupLinkStatsAccessRecordHandler(readModel, logger, metricsClient),
+
+			CursorLinkStatsAccessRecord:      query.NewCursorLinkStatsAccessRecordHandler(readModel, cursorCodec, logger, metricsClient),
+			CursorGroupLinkStatsAccessRecord: query.NewCursorGroupLinkStatsAccessRecordHandler(readModel, cursorCodec, logger, metricsClient),
+
+			ExportAccessLogs: query.NewExportAccessLogsHandler(readModel, logger, metricsClient),
 		},
 	}
 }
@@ -53,6 +53,46 @@ type LinkStatsAccessRecordReq struct {
 	Status int `json:"enable_status" validate:"required"`
 }
 
+// LinkStatsAccessRecordCursorReq 游标分页获取短链接监控访问记录请求
+type LinkStatsAccessRecordCursorReq struct {
+	// 游标分页参数
+	types.CursorReq `json:",inline"`
+	// 完整短链接
+	FullShortUrl string `json:"full_short_url" validate:"required"`
+	// 分组标识
+	Gid string `json:"gid" validate:"required"`
+	// 开始时间
+	StartTime time.Time `json:"start_time" validate:"required" format:"2006-01-02 15:04:05"`
+	// 结束时间
+	EndTime time.Time `json:"end_time" validate:"required" format:"2006-01-02 15:04:05"`
+}
+
+// LinkGroupStatsAccessRecordCursorReq 游标分页获取分组短链接监控访问记录请求
+type LinkGroupStatsAccessRecordCursorReq struct {
+	// 游标分页参数
+	types.CursorReq `json:",inline"`
+	// 分组ID
+	Gid string `json:"gid" validate:"required"`
+	// 开始时间
+	StartTime time.Time `json:"start_time" validate:"required" format:"2006-01-02 15:04:05"`
+	// 结束时间
+	EndTime time.Time `json:"end_time" validate:"required" format:"2006-01-02 15:04:05"`
+}
+
+// LinkStatsAccessLogExportReq 导出原始访问日志请求
+type LinkStatsAccessLogExportReq struct {
+	// 分组ID
+	Gid string `json:"gid" validate:"required"`
+	// 完整短链接 为空时导出整个分组
+	FullShortUrl string `json:"full_short_url"`
+	// 开始时间
+	StartTime time.Time `json:"start_time" validate:"required" format:"2006-01-02 15:04:05"`
+	// 结束时间
+	EndTime time.Time `json:"end_time" validate:"required" format:"2006-01-02 15:04:05"`
+	// 文件格式 csv | ndjson 默认 csv
+	Format string `json:"format"`
+}
+
 // LinkStatsReq 短链接监控请求
 type LinkStatsReq struct {
 	// 完整短链接
@@ -118,6 +118,9 @@ type LinkStatsUvDTO struct {
 // LinkStatsAccessRecordResp 短链接监控访问统计记录响应
 type LinkStatsAccessRecordResp types.PageResp[LinkStatsAccessRecordDTO]
 
+// LinkStatsAccessRecordCursorResp 游标分页的短链接监控访问统计记录响应
+type LinkStatsAccessRecordCursorResp types.CursorResp[LinkStatsAccessRecordDTO]
+
 type LinkStatsAccessRecordDTO struct {
 	// 访客类型
 	UvType string `json:"UvType"`
@@ -138,3 +141,25 @@ type LinkStatsAccessRecordDTO struct {
 	// 访问时间
 	AccessTime time.Time `json:"accessTime" format:"2006-01-02 15:04:05"`
 }
+
+// AccessLogExportDTO 导出的原始访问日志 NDJSON 格式的每一行
+type AccessLogExportDTO struct {
+	// 短链接
+	ShortUri string `json:"short_uri"`
+	// IP
+	Ip string `json:"ip"`
+	// 浏览器
+	Browser string `json:"browser"`
+	// 操作系统
+	Os string `json:"os"`
+	// 访问网络
+	Network string `json:"network"`
+	// 访问设备
+	Device string `json:"device"`
+	// 地区
+	Locale string `json:"locale"`
+	// 用户信息
+	User string `json:"user"`
+	// 访问时间
+	AccessTime string `json:"access_time"`
+}
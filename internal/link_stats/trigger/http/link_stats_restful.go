@@ -1,12 +1,20 @@
 package http
 
 import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jinzhu/copier"
+	"log/slog"
+	"shortlink/internal/base/errno"
 	"shortlink/internal/link_stats/app"
 	"shortlink/internal/link_stats/app/query"
 	"shortlink/internal/link_stats/trigger/http/dto/req"
 	"shortlink/internal/link_stats/trigger/http/dto/resp"
+	"strings"
+	"time"
 )
 
 type LinkStatsApi struct {
@@ -26,6 +34,12 @@ func NewLinkStatsApi(app app.Application, router fiber.Router) {
 	router.Get("/stats/access-record", api.GetLinkStatsAccessRecord)
 	// 访问分组短链接指定时间内访问记录监控数据
 	router.Get("/stats/access-record/group", api.GroupLinkStatsAccessRecord)
+	// 游标分页访问单个短链接指定时间内访问记录
+	router.Get("/stats/access-record/cursor", api.CursorLinkStatsAccessRecord)
+	// 游标分页访问分组短链接指定时间内访问记录
+	router.Get("/stats/access-record/group/cursor", api.CursorGroupLinkStatsAccessRecord)
+	// 导出分组或单个短链接指定时间内的原始访问日志
+	router.Get("/stats/access-record/export", api.ExportAccessLogs)
 }
 
 // GetLinkStats 获取短链接统计信息
@@ -134,3 +148,136 @@ func (h LinkStatsApi) GroupLinkStatsAccessRecord(c *fiber.Ctx) (err error) {
 
 	return c.JSON(response)
 }
+
+// CursorLinkStatsAccessRecord 游标分页获取短链接访问记录
+func (h LinkStatsApi) CursorLinkStatsAccessRecord(c *fiber.Ctx) error {
+	reqParam := req.LinkStatsAccessRecordCursorReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+
+	res, err := h.app.Queries.CursorLinkStatsAccessRecord.Handle(c.Context(), query.CursorLinkStatsAccessRecord{
+		CursorReq:    reqParam.CursorReq,
+		FullShortUrl: reqParam.FullShortUrl,
+		Gid:          reqParam.Gid,
+		StartDate:    reqParam.StartTime,
+		EndDate:      reqParam.EndTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	var response resp.LinkStatsAccessRecordCursorResp
+	if err = copier.Copy(&response, res); err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// CursorGroupLinkStatsAccessRecord 游标分页获取分组短链接访问记录
+func (h LinkStatsApi) CursorGroupLinkStatsAccessRecord(c *fiber.Ctx) error {
+	reqParam := req.LinkGroupStatsAccessRecordCursorReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+
+	res, err := h.app.Queries.CursorGroupLinkStatsAccessRecord.Handle(c.Context(), query.CursorGroupLinkStatsAccessRecord{
+		CursorReq: reqParam.CursorReq,
+		Gid:       reqParam.Gid,
+		StartDate: reqParam.StartTime,
+		EndDate:   reqParam.EndTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	var response resp.LinkStatsAccessRecordCursorResp
+	if err = copier.Copy(&response, res); err != nil {
+		return err
+	}
+
+	return c.JSON(response)
+}
+
+// ExportAccessLogs 导出原始访问日志
+//
+// 响应体边查询边写入，导出大量日志时不会全部加载到内存
+func (h LinkStatsApi) ExportAccessLogs(c *fiber.Ctx) error {
+	reqParam := req.LinkStatsAccessLogExportReq{}
+	if err := c.QueryParser(&reqParam); err != nil {
+		return err
+	}
+	format := strings.ToLower(reqParam.Format)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		return errno.NewRequestError("导出文件仅支持 csv 和 ndjson 格式")
+	}
+
+	stream, err := h.app.Queries.ExportAccessLogs.Handle(c.Context(), query.ExportAccessLogs{
+		FullShortUrl: reqParam.FullShortUrl,
+		Gid:          reqParam.Gid,
+		StartDate:    reqParam.StartTime,
+		EndDate:      reqParam.EndTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Attachment("access-logs-" + reqParam.Gid + "." + format)
+	// 流式写入在处理函数返回后执行，此时请求上下文已被回收
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := writeAccessLogs(w, format, stream); err != nil {
+			slog.Error("导出访问日志失败", "gid", reqParam.Gid, "error", err)
+		}
+	})
+	return nil
+}
+
+func writeAccessLogs(w *bufio.Writer, format string, stream query.AccessLogStream) error {
+	var cw *csv.Writer
+	if format == "csv" {
+		cw = csv.NewWriter(w)
+		if err := cw.Write([]string{"short_uri", "ip", "browser", "os", "network", "device", "locale", "user", "access_time"}); err != nil {
+			return err
+		}
+	}
+	enc := json.NewEncoder(w)
+	return stream(context.Background(), func(logs []query.AccessLog) error {
+		for _, l := range logs {
+			record := resp.AccessLogExportDTO{
+				ShortUri:   l.ShortUri,
+				Ip:         l.Ip,
+				Browser:    l.Browser,
+				Os:         l.Os,
+				Network:    l.Network,
+				Device:     l.Device,
+				Locale:     l.Locale,
+				User:       l.User,
+				AccessTime: l.AccessTime.Format(time.DateTime),
+			}
+			if cw == nil {
+				if err := enc.Encode(record); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := cw.Write([]string{
+				record.ShortUri, record.Ip, record.Browser, record.Os, record.Network,
+				record.Device, record.Locale, record.User, record.AccessTime,
+			}); err != nil {
+				return err
+			}
+		}
+		if cw != nil {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+		}
+		// 每批写完刷新到连接，客户端断开时返回错误并停止查询
+		return w.Flush()
+	})
+}
@@ -59,8 +59,18 @@ func (r UserRepositoryImpl) AddUserToBloomFilter(ctx context.Context, name strin
 }
 
 func (r UserRepositoryImpl) UpdateUser(ctx context.Context, u *user.User) error {
-	//TODO implement me
-	panic("implement me")
+	values := map[string]any{
+		"real_name":   u.RealName(),
+		"phone":       u.Phone(),
+		"mail":        u.Email(),
+		"update_time": time.Now(),
+		// 修改邮箱后需要重新验证，SET 中的 mail 为修改前的值
+		"mail_verified": gorm.Expr("mail_verified AND mail = ?", u.Email()),
+	}
+	// 密码只能通过 ChangePassword 修改
+	return r.db.WithContext(ctx).Model(&po.User{}).
+		Where("username = ?", u.Name()).
+		Updates(values).Error
 }
 
 func (r UserRepositoryImpl) CheckLogin(ctx context.Context, username string, token string) (flag bool, err error) {
@@ -84,9 +94,68 @@ func (r UserRepositoryImpl) InvalidateToken(ctx context.Context, username string
 	return nil
 }
 
-func (r UserRepositoryImpl) Login(ctx context.Context, username string, password string) (token string, err error) {
+func (r UserRepositoryImpl) GetPassword(ctx context.Context, username string) (string, error) {
+	userPo := po.User{}
+	if err := r.db.WithContext(ctx).Select("password").
+		Where("username = ?", username).
+		First(&userPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return userPo.Password, nil
+}
+
+func (r UserRepositoryImpl) UpdatePassword(ctx context.Context, username string, hash string) error {
+	return r.db.WithContext(ctx).Model(&po.User{}).
+		Where("username = ?", username).
+		Updates(map[string]any{"password": hash, "update_time": time.Now()}).Error
+}
+
+func (r UserRepositoryImpl) GetMail(ctx context.Context, username string) (string, bool, error) {
+	userPo := po.User{}
+	if err := r.db.WithContext(ctx).Select("mail", "mail_verified").
+		Where("username = ?", username).
+		First(&userPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return userPo.Mail, userPo.MailVerified, nil
+}
+
+func (r UserRepositoryImpl) GetUsernameByMail(ctx context.Context, mail string) (string, error) {
+	userPo := po.User{}
+	if err := r.db.WithContext(ctx).Select("username").
+		Where("mail = ?", mail).
+		First(&userPo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return userPo.Username, nil
+}
+
+func (r UserRepositoryImpl) MarkMailVerified(ctx context.Context, username string, mail string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&po.User{}).
+		Where("username = ? AND mail = ?", username, mail).
+		Updates(map[string]any{"mail_verified": true, "update_time": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r UserRepositoryImpl) InvalidateAllTokens(ctx context.Context, username string) error {
+	return r.rdb.Del(ctx, constant.UserLoginKey+username).Err()
+}
+
+func (r UserRepositoryImpl) Login(ctx context.Context, username string) (token string, err error) {
 	userPo := po.User{}
-	if err = r.db.Where("username = ? AND password = ?", username, password).
+	if err = r.db.Where("username = ?", username).
 		First(&userPo).Error; err != nil {
 		return "", err
 	}
@@ -110,6 +179,8 @@ func (r UserRepositoryImpl) Login(ctx context.Context, username string, password
 	// hash key: login_username
 	// 	field: token
 	// 	value: user info
+	// 会话中不保存密码哈希
+	userPo.Password = ""
 	var userInfo string
 	if userInfo, err = sonic.MarshalString(&userPo); err != nil {
 		return "", err
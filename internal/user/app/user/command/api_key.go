@@ -0,0 +1,86 @@
+package command
+
+import (
+	"context"
+	"shortlink/internal/base/apikey"
+	"time"
+)
+
+// ApiKeyStore API Key 存储，由 apikey.Store 实现
+type ApiKeyStore interface {
+	Create(ctx context.Context, params apikey.CreateParams) (string, apikey.Key, error)
+	Revoke(ctx context.Context, username, id string) error
+}
+
+type CreateApiKeyCommand struct {
+	Username string
+	Name     string
+	Scopes   []apikey.Scope
+	// 允许访问的分组，为空时不限制
+	Gids []string
+	// 每分钟允许的请求次数，为 0 时使用默认值
+	RateLimit int
+	// 为空时永不过期
+	ExpireAt *time.Time
+	result   *CreateApiKeyResult
+}
+
+type CreateApiKeyResult struct {
+	// 明文只在创建时返回一次
+	Plain string
+	Key   apikey.Key
+}
+
+func (c *CreateApiKeyCommand) ExecutionResult() *CreateApiKeyResult {
+	return c.result
+}
+
+type CreateApiKeyHandler struct {
+	store ApiKeyStore
+}
+
+func NewCreateApiKeyHandler(store ApiKeyStore) CreateApiKeyHandler {
+	if store == nil {
+		panic("nil store")
+	}
+	return CreateApiKeyHandler{store: store}
+}
+
+func (h CreateApiKeyHandler) Handle(ctx context.Context, cmd *CreateApiKeyCommand) error {
+	params := apikey.CreateParams{
+		Username:  cmd.Username,
+		Name:      cmd.Name,
+		Scopes:    cmd.Scopes,
+		Gids:      cmd.Gids,
+		RateLimit: cmd.RateLimit,
+	}
+	if cmd.ExpireAt != nil {
+		params.ExpireAt = *cmd.ExpireAt
+	}
+	plain, key, err := h.store.Create(ctx, params)
+	if err != nil {
+		return err
+	}
+	cmd.result = &CreateApiKeyResult{Plain: plain, Key: key}
+	return nil
+}
+
+type RevokeApiKeyCommand struct {
+	Username string
+	Id       string
+}
+
+type RevokeApiKeyHandler struct {
+	store ApiKeyStore
+}
+
+func NewRevokeApiKeyHandler(store ApiKeyStore) RevokeApiKeyHandler {
+	if store == nil {
+		panic("nil store")
+	}
+	return RevokeApiKeyHandler{store: store}
+}
+
+func (h RevokeApiKeyHandler) Handle(ctx context.Context, cmd RevokeApiKeyCommand) error {
+	return h.store.Revoke(ctx, cmd.Username, cmd.Id)
+}
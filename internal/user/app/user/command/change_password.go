@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/user/domain/user"
+)
+
+// ChangePasswordCommand 修改密码，成功后用户的所有登录会话和刷新令牌都会失效
+type ChangePasswordCommand struct {
+	Username    string
+	OldPassword string
+	NewPassword string
+}
+
+type ChangePasswordHandler struct {
+	repo   user.Repository
+	hasher user.PasswordHasher
+	policy user.PasswordPolicy
+	// 为空时使用 Redis 登录会话
+	issuer TokenIssuer
+}
+
+func NewChangePasswordHandler(
+	repo user.Repository,
+	hasher user.PasswordHasher,
+	policy user.PasswordPolicy,
+	issuer TokenIssuer,
+) ChangePasswordHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if hasher == nil {
+		panic("nil hasher")
+	}
+	if policy == nil {
+		panic("nil policy")
+	}
+	return ChangePasswordHandler{repo: repo, hasher: hasher, policy: policy, issuer: issuer}
+}
+
+func (h ChangePasswordHandler) Handle(ctx context.Context, cmd ChangePasswordCommand) error {
+	if err := verifyPassword(ctx, h.repo, h.hasher, cmd.Username, cmd.OldPassword); err != nil {
+		return err
+	}
+	if cmd.NewPassword == cmd.OldPassword {
+		return errno.UserPasswordUnchanged
+	}
+	if err := h.policy.Validate(cmd.NewPassword, cmd.Username); err != nil {
+		return err
+	}
+	hash, err := h.hasher.Hash(cmd.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err = h.repo.UpdatePassword(ctx, cmd.Username, hash); err != nil {
+		return err
+	}
+	return invalidateAllTokens(ctx, h.repo, h.issuer, cmd.Username)
+}
+
+// verifyPassword 校验密码，历史明文密码和过时的哈希在校验通过后按当前算法重新保存
+func verifyPassword(
+	ctx context.Context,
+	repo user.Repository,
+	hasher user.PasswordHasher,
+	username, plain string,
+) error {
+	encoded, err := repo.GetPassword(ctx, username)
+	if err != nil {
+		return err
+	}
+	ok, rehash, err := hasher.Verify(encoded, plain)
+	if err != nil {
+		return err
+	}
+	// 用户不存在和密码错误返回相同的错误
+	if !ok {
+		return errno.UserPasswordIncorrect
+	}
+	if rehash {
+		hash, err := hasher.Hash(plain)
+		if err != nil {
+			return err
+		}
+		if err = repo.UpdatePassword(ctx, username, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invalidateAllTokens 删除登录会话，JWT 模式下同时吊销所有刷新令牌
+func invalidateAllTokens(ctx context.Context, repo user.Repository, issuer TokenIssuer, username string) error {
+	if err := repo.InvalidateAllTokens(ctx, username); err != nil {
+		return err
+	}
+	if issuer != nil {
+		return issuer.RevokeUser(ctx, username)
+	}
+	return nil
+}
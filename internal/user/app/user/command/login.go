@@ -2,36 +2,71 @@ package command
 
 import (
 	"context"
+	"shortlink/internal/base/jwt"
 	"shortlink/internal/user/domain/user"
 )
 
+// TokenIssuer JWT 模式下签发和吊销令牌，由 jwt.Manager 实现
+type TokenIssuer interface {
+	Issue(ctx context.Context, username string) (jwt.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (jwt.TokenPair, error)
+	Revoke(ctx context.Context, family string) error
+	RevokeUser(ctx context.Context, username string) error
+}
+
 type UserLoginCommand struct {
 	Username string
 	Password string
-	result   string
+	result   *UserLoginResult
 }
 
-func (c *UserLoginCommand) ExecutionResult() string {
+type UserLoginResult struct {
+	// session 模式下的登录 token，JWT 模式下与访问令牌相同
+	Token string
+	// JWT 模式下返回
+	jwt.TokenPair
+}
+
+func (c *UserLoginCommand) ExecutionResult() *UserLoginResult {
 	return c.result
 }
 
 type UserLoginHandler struct {
-	repo user.Repository
+	repo   user.Repository
+	hasher user.PasswordHasher
+	// 为空时使用 Redis 登录会话
+	issuer TokenIssuer
 }
 
-func NewUserLoginHandler(repo user.Repository) UserLoginHandler {
+func NewUserLoginHandler(repo user.Repository, hasher user.PasswordHasher, issuer TokenIssuer) UserLoginHandler {
 	if repo == nil {
 		panic("nil repo")
 	}
+	if hasher == nil {
+		panic("nil hasher")
+	}
 
-	return UserLoginHandler{repo: repo}
+	return UserLoginHandler{repo: repo, hasher: hasher, issuer: issuer}
 }
 
 func (h UserLoginHandler) Handle(ctx context.Context, cmd *UserLoginCommand) (err error) {
+	if err = verifyPassword(ctx, h.repo, h.hasher, cmd.Username, cmd.Password); err != nil {
+		return
+	}
+
+	if h.issuer != nil {
+		var pair jwt.TokenPair
+		if pair, err = h.issuer.Issue(ctx, cmd.Username); err != nil {
+			return
+		}
+		cmd.result = &UserLoginResult{Token: pair.AccessToken, TokenPair: pair}
+		return nil
+	}
+
 	var token string
-	if token, err = h.repo.Login(ctx, cmd.Username, cmd.Password); err != nil {
+	if token, err = h.repo.Login(ctx, cmd.Username); err != nil {
 		return
 	}
-	cmd.result = token
+	cmd.result = &UserLoginResult{Token: token}
 	return nil
 }
@@ -9,20 +9,32 @@ import (
 type UserLogoutCommand struct {
 	Username string
 	Token    string
+	// JWT 模式下访问令牌所属的刷新令牌族
+	Family string
 }
 
 type UserLogoutHandler struct {
 	repo user.Repository
+	// 为空时使用 Redis 登录会话
+	issuer TokenIssuer
 }
 
-func NewUserLogoutHandler(repo user.Repository) UserLogoutHandler {
+func NewUserLogoutHandler(repo user.Repository, issuer TokenIssuer) UserLogoutHandler {
 	if repo == nil {
 		panic("nil repo")
 	}
-	return UserLogoutHandler{repo: repo}
+	return UserLogoutHandler{repo: repo, issuer: issuer}
 }
 
 func (h UserLogoutHandler) Handle(ctx context.Context, cmd UserLogoutCommand) (err error) {
+	if h.issuer != nil {
+		// 访问令牌无状态，吊销刷新令牌族后无法再续期，已签发的访问令牌在过期后失效
+		if cmd.Family == "" {
+			return error_no.InvalidTokenOrUnloggedLoginUser
+		}
+		return h.issuer.Revoke(ctx, cmd.Family)
+	}
+
 	var login bool
 	if login, err = h.repo.CheckLogin(ctx, cmd.Username, cmd.Token); err != nil {
 		return err
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/jwt"
+)
+
+// RefreshTokenCommand 使用刷新令牌换取新的访问令牌和刷新令牌
+type RefreshTokenCommand struct {
+	RefreshToken string
+	result       jwt.TokenPair
+}
+
+func (c *RefreshTokenCommand) ExecutionResult() jwt.TokenPair {
+	return c.result
+}
+
+type RefreshTokenHandler struct {
+	// 为空时表示使用 session 模式，不支持刷新
+	issuer TokenIssuer
+}
+
+func NewRefreshTokenHandler(issuer TokenIssuer) RefreshTokenHandler {
+	return RefreshTokenHandler{issuer: issuer}
+}
+
+func (h RefreshTokenHandler) Handle(ctx context.Context, cmd *RefreshTokenCommand) error {
+	if h.issuer == nil {
+		return errno.UserInvalidToken
+	}
+	pair, err := h.issuer.Refresh(ctx, cmd.RefreshToken)
+	if err != nil {
+		// 重复使用时整个令牌族已被吊销，需要重新登录
+		if errors.Is(err, jwt.ErrRefreshInvalid) || errors.Is(err, jwt.ErrRefreshReused) {
+			return errno.UserInvalidToken
+		}
+		return err
+	}
+	cmd.result = pair
+	return nil
+}
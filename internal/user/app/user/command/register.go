@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"log/slog"
 	"shortlink/internal/base/error_no"
 	"shortlink/internal/base/lock"
 	"shortlink/internal/link/constant"
@@ -13,6 +14,9 @@ type UserRegisterHandler struct {
 	repo         user.Repository
 	locker       lock.DistributedLock
 	groupService GroupService
+	hasher       user.PasswordHasher
+	policy       user.PasswordPolicy
+	mailer       AccountMailer
 }
 
 type UserRegisterCommand struct {
@@ -27,6 +31,9 @@ func NewUserRegisterHandler(
 	repo user.Repository,
 	locker lock.DistributedLock,
 	groupService GroupService,
+	hasher user.PasswordHasher,
+	policy user.PasswordPolicy,
+	mailer AccountMailer,
 ) UserRegisterHandler {
 	if repo == nil {
 		panic("nil repo service")
@@ -37,10 +44,29 @@ func NewUserRegisterHandler(
 	if groupService == nil {
 		panic("nil group service")
 	}
-	return UserRegisterHandler{repo: repo, locker: locker, groupService: groupService}
+	if hasher == nil {
+		panic("nil hasher")
+	}
+	if policy == nil {
+		panic("nil policy")
+	}
+	if mailer == nil {
+		panic("nil mailer")
+	}
+	return UserRegisterHandler{
+		repo:         repo,
+		locker:       locker,
+		groupService: groupService,
+		hasher:       hasher,
+		policy:       policy,
+		mailer:       mailer,
+	}
 }
 
 func (h UserRegisterHandler) Handle(ctx context.Context, cmd UserRegisterCommand) error {
+	if err := h.policy.Validate(cmd.Password, cmd.Username); err != nil {
+		return err
+	}
 	if exist, err := h.repo.CheckUserExist(ctx, cmd.Username); err != nil {
 		return err
 	} else if exist {
@@ -60,8 +86,12 @@ func (h UserRegisterHandler) Handle(ctx context.Context, cmd UserRegisterCommand
 	} else if exist {
 		return error_no.UserExist
 	}
-	// 创建用户
-	u := user.NewUser(cmd.Username, cmd.Password, cmd.RealName, cmd.Email, cmd.Phone)
+	// 创建用户 只保存密码哈希
+	hash, err := h.hasher.Hash(cmd.Password)
+	if err != nil {
+		return err
+	}
+	u := user.NewUser(cmd.Username, hash, cmd.RealName, cmd.Email, cmd.Phone)
 	if err := h.repo.CreateUser(ctx, &u); err != nil {
 		return err
 	}
@@ -69,6 +99,12 @@ func (h UserRegisterHandler) Handle(ctx context.Context, cmd UserRegisterCommand
 	if err := h.groupService.CreateGroup(ctx, u.Name(), "默认分组"); err != nil {
 		return err
 	}
+	// 发送验证邮件 发送失败不影响注册，用户可以重新发送
+	if u.Email() != "" {
+		if err := h.mailer.SendVerifyEmail(u.Name(), u.Email()); err != nil {
+			slog.Warn("send verify email failed", "username", u.Name(), "error", err)
+		}
+	}
 	// 加入布隆过滤器
 	//if err := h.repo.AddUserToBloomFilter(ctx, u.Tag()); err != nil {
 	//	return err
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"context"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/user/domain/user"
+)
+
+// ForgotPasswordCommand 向注册邮箱发送重置密码邮件
+type ForgotPasswordCommand struct {
+	Mail string
+}
+
+type ForgotPasswordHandler struct {
+	repo   user.Repository
+	mailer AccountMailer
+}
+
+func NewForgotPasswordHandler(repo user.Repository, mailer AccountMailer) ForgotPasswordHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if mailer == nil {
+		panic("nil mailer")
+	}
+	return ForgotPasswordHandler{repo: repo, mailer: mailer}
+}
+
+func (h ForgotPasswordHandler) Handle(ctx context.Context, cmd ForgotPasswordCommand) error {
+	if cmd.Mail == "" {
+		return errno.UserMailMissing
+	}
+	username, err := h.repo.GetUsernameByMail(ctx, cmd.Mail)
+	if err != nil {
+		return err
+	}
+	// 邮箱未注册时同样返回成功，避免通过该接口探测邮箱
+	if username == "" {
+		return nil
+	}
+	hash, err := h.repo.GetPassword(ctx, username)
+	if err != nil {
+		return err
+	}
+	return h.mailer.SendResetPassword(username, cmd.Mail, hash)
+}
+
+// ResetPasswordCommand 使用邮件中的令牌设置新密码，成功后用户的所有登录会话和刷新令牌都会失效
+type ResetPasswordCommand struct {
+	Token       string
+	NewPassword string
+}
+
+type ResetPasswordHandler struct {
+	repo   user.Repository
+	mailer AccountMailer
+	hasher user.PasswordHasher
+	policy user.PasswordPolicy
+	// 为空时使用 Redis 登录会话
+	issuer TokenIssuer
+}
+
+func NewResetPasswordHandler(
+	repo user.Repository,
+	mailer AccountMailer,
+	hasher user.PasswordHasher,
+	policy user.PasswordPolicy,
+	issuer TokenIssuer,
+) ResetPasswordHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if mailer == nil {
+		panic("nil mailer")
+	}
+	if hasher == nil {
+		panic("nil hasher")
+	}
+	if policy == nil {
+		panic("nil policy")
+	}
+	return ResetPasswordHandler{repo: repo, mailer: mailer, hasher: hasher, policy: policy, issuer: issuer}
+}
+
+func (h ResetPasswordHandler) Handle(ctx context.Context, cmd ResetPasswordCommand) error {
+	t, err := h.mailer.ParseResetToken(cmd.Token)
+	if err != nil {
+		return err
+	}
+	current, err := h.repo.GetPassword(ctx, t.Username)
+	if err != nil {
+		return err
+	}
+	// 密码已经被重置或修改过，令牌随之失效
+	if current == "" || !h.mailer.ResetMatches(t, current) {
+		return errno.UserInvalidMailToken
+	}
+	if err = h.policy.Validate(cmd.NewPassword, t.Username); err != nil {
+		return err
+	}
+	if ok, _, err := h.hasher.Verify(current, cmd.NewPassword); err != nil {
+		return err
+	} else if ok {
+		return errno.UserPasswordUnchanged
+	}
+	hash, err := h.hasher.Hash(cmd.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err = h.repo.UpdatePassword(ctx, t.Username, hash); err != nil {
+		return err
+	}
+	return invalidateAllTokens(ctx, h.repo, h.issuer, t.Username)
+}
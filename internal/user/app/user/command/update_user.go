@@ -2,12 +2,14 @@ package command
 
 import (
 	"context"
+	"shortlink/internal/base/errno"
 	"shortlink/internal/base/error_no"
 	"shortlink/internal/user/domain/user"
 )
 
 type UpdateUserCommand struct {
 	Username string
+	// 不能通过修改用户信息修改密码，需要使用 ChangePasswordCommand 校验旧密码
 	Password string
 	RealName string
 	Email    string
@@ -30,7 +32,11 @@ func (h UpdateUserHandler) Handle(ctx context.Context, cmd UpdateUserCommand) er
 	if currentUsername != cmd.Username {
 		return error_no.UserForbidden
 	}
+	// 登录会话被盗用时不能直接改掉密码
+	if cmd.Password != "" {
+		return errno.UserPasswordChangeRequired
+	}
 
-	u := user.NewUser(cmd.Username, cmd.Password, cmd.RealName, cmd.Email, cmd.Phone)
+	u := user.NewUser(cmd.Username, "", cmd.RealName, cmd.Email, cmd.Phone)
 	return h.repo.UpdateUser(ctx, &u)
 }
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"context"
+	"shortlink/internal/base/errno"
+	"shortlink/internal/base/mail"
+	"shortlink/internal/user/domain/user"
+)
+
+// AccountMailer 发送邮箱验证和重置密码邮件，由 mail.AccountMailer 实现
+type AccountMailer interface {
+	SendVerifyEmail(username, mail string) error
+	SendResetPassword(username, mail, passwordHash string) error
+	ParseVerifyToken(token string) (mail.AccountToken, error)
+	ParseResetToken(token string) (mail.AccountToken, error)
+	// ResetMatches 令牌签发后密码是否没有被修改
+	ResetMatches(t mail.AccountToken, passwordHash string) bool
+}
+
+// SendVerifyEmailCommand 重新发送邮箱验证邮件
+type SendVerifyEmailCommand struct {
+	Username string
+}
+
+type SendVerifyEmailHandler struct {
+	repo   user.Repository
+	mailer AccountMailer
+}
+
+func NewSendVerifyEmailHandler(repo user.Repository, mailer AccountMailer) SendVerifyEmailHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if mailer == nil {
+		panic("nil mailer")
+	}
+	return SendVerifyEmailHandler{repo: repo, mailer: mailer}
+}
+
+func (h SendVerifyEmailHandler) Handle(ctx context.Context, cmd SendVerifyEmailCommand) error {
+	address, verified, err := h.repo.GetMail(ctx, cmd.Username)
+	if err != nil {
+		return err
+	}
+	if address == "" {
+		return errno.UserMailMissing
+	}
+	if verified {
+		return errno.UserMailAlreadyVerified
+	}
+	return h.mailer.SendVerifyEmail(cmd.Username, address)
+}
+
+// VerifyEmailCommand 使用邮件中的令牌验证邮箱
+type VerifyEmailCommand struct {
+	Token string
+}
+
+type VerifyEmailHandler struct {
+	repo   user.Repository
+	mailer AccountMailer
+}
+
+func NewVerifyEmailHandler(repo user.Repository, mailer AccountMailer) VerifyEmailHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if mailer == nil {
+		panic("nil mailer")
+	}
+	return VerifyEmailHandler{repo: repo, mailer: mailer}
+}
+
+func (h VerifyEmailHandler) Handle(ctx context.Context, cmd VerifyEmailCommand) error {
+	t, err := h.mailer.ParseVerifyToken(cmd.Token)
+	if err != nil {
+		return err
+	}
+	// 令牌签发后修改过邮箱时不再有效
+	ok, err := h.repo.MarkMailVerified(ctx, t.Username, t.Stamp)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errno.UserInvalidMailToken
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package query
+
+import (
+	"context"
+	"shortlink/internal/base/apikey"
+)
+
+// ApiKeyReader 查询用户的 API Key，由 apikey.Store 实现
+type ApiKeyReader interface {
+	List(ctx context.Context, username string) ([]apikey.Key, error)
+}
+
+type ListApiKeysHandler struct {
+	reader ApiKeyReader
+}
+
+func NewListApiKeysHandler(reader ApiKeyReader) ListApiKeysHandler {
+	if reader == nil {
+		panic("nil reader")
+	}
+	return ListApiKeysHandler{reader: reader}
+}
+
+func (h ListApiKeysHandler) Handle(ctx context.Context, username string) ([]apikey.Key, error) {
+	return h.reader.List(ctx, username)
+}
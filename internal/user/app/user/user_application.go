@@ -11,15 +11,24 @@ type Application struct {
 }
 
 type Commands struct {
-	UserRegister command.UserRegisterHandler
-	UserLogin    command.UserLoginHandler
-	UserLogout   command.UserLogoutHandler
-	UpdateUser   command.UpdateUserHandler
-	DeleteUser   command.DeleteUserHandler
+	UserRegister    command.UserRegisterHandler
+	UserLogin       command.UserLoginHandler
+	UserLogout      command.UserLogoutHandler
+	RefreshToken    command.RefreshTokenHandler
+	CreateApiKey    command.CreateApiKeyHandler
+	RevokeApiKey    command.RevokeApiKeyHandler
+	UpdateUser      command.UpdateUserHandler
+	ChangePassword  command.ChangePasswordHandler
+	SendVerifyEmail command.SendVerifyEmailHandler
+	VerifyEmail     command.VerifyEmailHandler
+	ForgotPassword  command.ForgotPasswordHandler
+	ResetPassword   command.ResetPasswordHandler
+	DeleteUser      command.DeleteUserHandler
 }
 
 type Queries struct {
 	GetUser        query.GetUserHandler
 	CheckLogin     query.CheckLoginHandler
 	CheckUserExist query.CheckUserExistHandler
+	ListApiKeys    query.ListApiKeysHandler
 }
@@ -0,0 +1,13 @@
+package user
+
+// PasswordHasher 密码哈希，由 password.Hasher 实现
+type PasswordHasher interface {
+	Hash(plain string) (string, error)
+	// Verify rehash 表示密码正确但存储格式已过时，需要重新哈希后保存
+	Verify(encoded, plain string) (ok bool, rehash bool, err error)
+}
+
+// PasswordPolicy 密码策略，由 password.Policy 实现
+type PasswordPolicy interface {
+	Validate(plain, username string) error
+}
@@ -10,6 +10,18 @@ type Repository interface {
 	UpdateUser(ctx context.Context, u *User) error
 	CheckLogin(ctx context.Context, username string, token string) (bool, error)
 	InvalidateToken(ctx context.Context, username string, token string) error
-	Login(ctx context.Context, username string, password string) (string, error)
+	// InvalidateAllTokens 删除用户的所有登录会话
+	InvalidateAllTokens(ctx context.Context, username string) error
+	// Login 创建或复用登录会话，密码由调用方校验
+	Login(ctx context.Context, username string) (string, error)
+	// GetPassword 查询存储的密码哈希，用户不存在时返回空字符串
+	GetPassword(ctx context.Context, username string) (string, error)
+	UpdatePassword(ctx context.Context, username string, hash string) error
+	// GetMail 查询邮箱和验证状态，用户不存在时返回空字符串
+	GetMail(ctx context.Context, username string) (mail string, verified bool, err error)
+	// GetUsernameByMail 根据邮箱查询用户名，不存在时返回空字符串
+	GetUsernameByMail(ctx context.Context, mail string) (string, error)
+	// MarkMailVerified 邮箱仍为 mail 时标记为已验证，返回是否更新成功
+	MarkMailVerified(ctx context.Context, username string, mail string) (bool, error)
 	DeleteUser(id string) error
 }
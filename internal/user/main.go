@@ -5,8 +5,10 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"shortlink/internal/base"
 	"shortlink/internal/base/cache"
 	"shortlink/internal/base/database"
+	"shortlink/internal/base/jwt"
 	"shortlink/internal/base/logging"
 	"shortlink/internal/base/server"
 	"shortlink/internal/base/server/middleware/auth"
@@ -28,10 +30,16 @@ func main() {
 	userApp := service.NewUserApplication(db, rdb, groupApp.Commands.CreateGroup)
 
 	// 不需要鉴权的接口
-	excludes := []string{"/users/login", "/users/register", "/users/check-login", "/users/exist"}
+	excludes := []string{"/users/login", "/users/register", "/users/check-login", "/users/exist",
+		"/users/refresh-token", "/users/verify-email", "/users/forgot-password", "/users/reset-password",
+		"/.well-known/jwks.json"}
 
 	cleanup := server.RunHttpServerOnPort("8080", func(router fiber.Router) {
 		router.Use(auth.New(rdb, excludes)) // 鉴权中间件
+		if base.GetConfig().Auth.Mode == base.AuthModeJwt {
+			// 网关通过 JWKS 获取公钥校验访问令牌
+			router.Get("/.well-known/jwks.json", auth.Jwks(jwt.NewManagerFromConfig(rdb)))
+		}
 		server.NewUriTitleApi(router)
 		rest.NewUserApi(userApp, router)
 		rest.NewGroupApi(groupApp, router)
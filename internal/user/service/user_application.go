@@ -2,7 +2,12 @@ package service
 
 import (
 	"gorm.io/gorm"
+	"shortlink/internal/base"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/jwt"
 	"shortlink/internal/base/lock"
+	"shortlink/internal/base/mail"
+	"shortlink/internal/base/password"
 	"shortlink/internal/user/adapter"
 	"shortlink/internal/user/app/user"
 	"shortlink/internal/user/app/user/command"
@@ -17,6 +22,22 @@ func NewUserApplication(
 
 	repository := adapter.NewUserRepositoryImpl(db, rdb)
 	locker := lock.NewRedisLock(rdb)
+	apiKeyStore := apikey.NewStore(rdb)
+	hasher := password.NewHasherFromConfig()
+	policy := password.PolicyFromConfig()
+
+	// 未配置 SMTP 账号时邮件只保存在内存中，便于本地开发
+	var sender mail.EmailSender = mail.NewSMTPMailer()
+	if base.GetConfig().Email.Username == "" {
+		sender = mail.NewMemorySender()
+	}
+	mailer := mail.NewAccountMailerFromConfig(sender)
+
+	// JWT 模式下登录签发访问令牌和刷新令牌，否则使用 Redis 登录会话
+	var issuer command.TokenIssuer
+	if base.GetConfig().Auth.Mode == base.AuthModeJwt {
+		issuer = jwt.NewManagerFromConfig(rdb)
+	}
 
 	// 对 group 领域的依赖项
 	//groupRepository := adapter.NewGroupRepositoryImpl(db)
@@ -24,16 +45,25 @@ func NewUserApplication(
 
 	a := user.Application{
 		Commands: user.Commands{
-			UserRegister: command.NewUserRegisterHandler(repository, locker, groupService),
-			UserLogin:    command.NewUserLoginHandler(repository),
-			UserLogout:   command.NewUserLogoutHandler(repository),
-			UpdateUser:   command.NewUpdateUserHandler(repository),
-			DeleteUser:   command.NewDeleteUserHandler(repository),
+			UserRegister:    command.NewUserRegisterHandler(repository, locker, groupService, hasher, policy, mailer),
+			UserLogin:       command.NewUserLoginHandler(repository, hasher, issuer),
+			UserLogout:      command.NewUserLogoutHandler(repository, issuer),
+			RefreshToken:    command.NewRefreshTokenHandler(issuer),
+			CreateApiKey:    command.NewCreateApiKeyHandler(apiKeyStore),
+			RevokeApiKey:    command.NewRevokeApiKeyHandler(apiKeyStore),
+			UpdateUser:      command.NewUpdateUserHandler(repository),
+			ChangePassword:  command.NewChangePasswordHandler(repository, hasher, policy, issuer),
+			SendVerifyEmail: command.NewSendVerifyEmailHandler(repository, mailer),
+			VerifyEmail:     command.NewVerifyEmailHandler(repository, mailer),
+			ForgotPassword:  command.NewForgotPasswordHandler(repository, mailer),
+			ResetPassword:   command.NewResetPasswordHandler(repository, mailer, hasher, policy, issuer),
+			DeleteUser:      command.NewDeleteUserHandler(repository),
 		},
 		Queries: user.Queries{
 			GetUser:        query.NewGetUserHandler(repository),
 			CheckLogin:     query.NewCheckLoginHandler(repository),
 			CheckUserExist: query.NewCheckUserExistHandler(repository),
+			ListApiKeys:    query.NewListApiKeysHandler(apiKeyStore),
 		},
 	}
 
@@ -1,5 +1,10 @@
 package req
 
+import (
+	"shortlink/internal/base/apikey"
+	"time"
+)
+
 type RecycleBinRecoverReq struct {
 	Gid          string `json:"gid"`
 	FullShortUrl string `json:"full_short_url"`
@@ -36,6 +41,40 @@ type UserLoginReq struct {
 	Password string `json:"password"`
 }
 
+type ChangePasswordReq struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordReq struct {
+	Mail string `json:"mail"`
+}
+
+type ResetPasswordReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ApiKeyCreateReq struct {
+	Name string `json:"name"`
+	// 权限范围 link:create link:read link:update
+	Scopes []apikey.Scope `json:"scopes"`
+	// 允许访问的分组 为空时不限制
+	Gids []string `json:"gids,omitempty"`
+	// 每分钟允许的请求次数 为空时使用默认值，不能超过 apikey.MaxRateLimit
+	RateLimit int `json:"rate_limit,omitempty"`
+	// 过期时间 为空时永不过期
+	ExpireAt *time.Time `json:"expire_at,omitempty"`
+}
+
 type UserRegisterReq struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
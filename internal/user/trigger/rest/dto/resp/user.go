@@ -6,6 +6,35 @@ import (
 
 type UserLoginResp struct {
 	Token string `json:"token"`
+	// JWT 模式下返回
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+}
+
+type RefreshTokenResp struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type ApiKeyCreateResp struct {
+	// 明文只在创建时返回一次
+	Key string `json:"key"`
+	ApiKeyDTO
+}
+
+type ApiKeyDTO struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Hint       string   `json:"hint"`
+	Scopes     []string `json:"scopes"`
+	Gids       []string `json:"gids"`
+	RateLimit  int      `json:"rate_limit"`
+	ExpireAt   int64    `json:"expire_at"`
+	CreatedAt  int64    `json:"created_at"`
+	UsageCount int64    `json:"usage_count"`
+	LastUsedAt int64    `json:"last_used_at"`
 }
 
 type UserResp struct {
@@ -3,6 +3,8 @@ package rest
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/jinzhu/copier"
+	"shortlink/internal/base/apikey"
+	"shortlink/internal/base/server/middleware/auth"
 	"shortlink/internal/user/app/user"
 	"shortlink/internal/user/app/user/command"
 	"shortlink/internal/user/app/user/query"
@@ -25,7 +27,16 @@ func NewUserApi(app user.Application, router fiber.Router) {
 	userRouter.Post("/register", api.Register)
 	userRouter.Post("/login", api.Login)
 	userRouter.Post("/logout", api.Logout)
+	userRouter.Post("/refresh-token", api.RefreshToken)
+	userRouter.Get("/api-keys", api.ListApiKeys)
+	userRouter.Post("/api-keys", api.CreateApiKey)
+	userRouter.Delete("/api-keys/:id", api.RevokeApiKey)
 	userRouter.Put("", api.Update)
+	userRouter.Put("/password", api.ChangePassword)
+	userRouter.Post("/verify-email/send", api.SendVerifyEmail)
+	userRouter.Post("/verify-email", api.VerifyEmail)
+	userRouter.Post("/forgot-password", api.ForgotPassword)
+	userRouter.Post("/reset-password", api.ResetPassword)
 	userRouter.Delete("/username/:username", api.Delete)
 }
 
@@ -113,7 +124,11 @@ func (h UserApi) Login(c *fiber.Ctx) error {
 	if err := h.app.Commands.UserLogin.Handle(c.Context(), cmd); err != nil {
 		return err
 	}
-	response.Token = cmd.ExecutionResult()
+	res := cmd.ExecutionResult()
+	response.Token = res.Token
+	response.AccessToken = res.AccessToken
+	response.RefreshToken = res.RefreshToken
+	response.ExpiresIn = res.ExpiresIn
 	c.Cookie(&fiber.Cookie{
 		Name:     "token",
 		Value:    response.Token,
@@ -142,9 +157,11 @@ func (h UserApi) CheckLogin(c *fiber.Ctx) error {
 func (h UserApi) Logout(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	token := c.Query("token")
+	family, _ := c.Locals(auth.LocalTokenFamily).(string)
 	cmd := command.UserLogoutCommand{
 		Username: username,
 		Token:    token,
+		Family:   family,
 	}
 	if err := h.app.Commands.UserLogout.Handle(c.Context(), cmd); err != nil {
 		return err
@@ -152,6 +169,77 @@ func (h UserApi) Logout(c *fiber.Ctx) error {
 	return nil
 }
 
+// RefreshToken 使用刷新令牌换取新的令牌，仅 JWT 模式可用
+func (h UserApi) RefreshToken(c *fiber.Ctx) error {
+	reqParam := req.RefreshTokenReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+	cmd := &command.RefreshTokenCommand{RefreshToken: reqParam.RefreshToken}
+	if err := h.app.Commands.RefreshToken.Handle(c.Context(), cmd); err != nil {
+		return err
+	}
+	pair := cmd.ExecutionResult()
+	return c.JSON(resp.RefreshTokenResp{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+	})
+}
+
+// ChangePassword 修改密码，所有已登录的会话都需要重新登录
+func (h UserApi) ChangePassword(c *fiber.Ctx) error {
+	reqParam := req.ChangePasswordReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+	cmd := command.ChangePasswordCommand{
+		Username:    c.Locals("username").(string),
+		OldPassword: reqParam.OldPassword,
+		NewPassword: reqParam.NewPassword,
+	}
+	return h.app.Commands.ChangePassword.Handle(c.Context(), cmd)
+}
+
+// SendVerifyEmail 重新发送邮箱验证邮件
+func (h UserApi) SendVerifyEmail(c *fiber.Ctx) error {
+	cmd := command.SendVerifyEmailCommand{Username: c.Locals("username").(string)}
+	return h.app.Commands.SendVerifyEmail.Handle(c.Context(), cmd)
+}
+
+// VerifyEmail 验证邮箱
+func (h UserApi) VerifyEmail(c *fiber.Ctx) error {
+	reqParam := req.VerifyEmailReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+	cmd := command.VerifyEmailCommand{Token: reqParam.Token}
+	return h.app.Commands.VerifyEmail.Handle(c.Context(), cmd)
+}
+
+// ForgotPassword 发送重置密码邮件，邮箱未注册时同样返回成功
+func (h UserApi) ForgotPassword(c *fiber.Ctx) error {
+	reqParam := req.ForgotPasswordReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+	cmd := command.ForgotPasswordCommand{Mail: reqParam.Mail}
+	return h.app.Commands.ForgotPassword.Handle(c.Context(), cmd)
+}
+
+// ResetPassword 使用邮件中的令牌重置密码，所有已登录的会话都需要重新登录
+func (h UserApi) ResetPassword(c *fiber.Ctx) error {
+	reqParam := req.ResetPasswordReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+	cmd := command.ResetPasswordCommand{
+		Token:       reqParam.Token,
+		NewPassword: reqParam.NewPassword,
+	}
+	return h.app.Commands.ResetPassword.Handle(c.Context(), cmd)
+}
+
 // Delete 删除用户
 func (h UserApi) Delete(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
@@ -160,3 +248,66 @@ func (h UserApi) Delete(c *fiber.Ctx) error {
 	}
 	return nil
 }
+
+// ListApiKeys 查询当前用户的 API Key
+func (h UserApi) ListApiKeys(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	keys, err := h.app.Queries.ListApiKeys.Handle(c.Context(), username)
+	if err != nil {
+		return err
+	}
+	response := make([]resp.ApiKeyDTO, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toApiKeyDTO(key))
+	}
+	return c.JSON(response)
+}
+
+// CreateApiKey 创建 API Key，明文只在响应中返回一次
+func (h UserApi) CreateApiKey(c *fiber.Ctx) error {
+	reqParam := req.ApiKeyCreateReq{}
+	if err := c.BodyParser(&reqParam); err != nil {
+		return err
+	}
+	cmd := &command.CreateApiKeyCommand{
+		Username:  c.Locals("username").(string),
+		Name:      reqParam.Name,
+		Scopes:    reqParam.Scopes,
+		Gids:      reqParam.Gids,
+		RateLimit: reqParam.RateLimit,
+		ExpireAt:  reqParam.ExpireAt,
+	}
+	if err := h.app.Commands.CreateApiKey.Handle(c.Context(), cmd); err != nil {
+		return err
+	}
+	res := cmd.ExecutionResult()
+	return c.JSON(resp.ApiKeyCreateResp{Key: res.Plain, ApiKeyDTO: toApiKeyDTO(res.Key)})
+}
+
+// RevokeApiKey 删除 API Key
+func (h UserApi) RevokeApiKey(c *fiber.Ctx) error {
+	cmd := command.RevokeApiKeyCommand{
+		Username: c.Locals("username").(string),
+		Id:       c.Params("id"),
+	}
+	return h.app.Commands.RevokeApiKey.Handle(c.Context(), cmd)
+}
+
+func toApiKeyDTO(key apikey.Key) resp.ApiKeyDTO {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return resp.ApiKeyDTO{
+		Id:         key.Id,
+		Name:       key.Name,
+		Hint:       key.Hint,
+		Scopes:     scopes,
+		Gids:       key.Gids,
+		RateLimit:  key.RateLimit,
+		ExpireAt:   key.ExpireAt,
+		CreatedAt:  key.CreatedAt,
+		UsageCount: key.UsageCount,
+		LastUsedAt: key.LastUsedAt,
+	}
+}
@@ -0,0 +1,69 @@
+-- 自定义短链接域名 不同域名下可以存在相同的短链接，以 domain + short_uri 区分短链接
+-- 空字符串表示默认域名，已有数据都属于默认域名
+-- 单表部署只需要修改 t_link、t_link_goto，分表部署需要修改 t_link_0 ~ t_link_15、t_link_goto_0 ~ t_link_goto_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+    idx TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND (table_name ~ '^t_link(_[0-9]+)?$' OR table_name ~ '^t_link_goto(_[0-9]+)?$')
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "domain" varchar(128) NOT NULL DEFAULT ''''', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."domain" IS ''自定义域名 空字符串表示默认域名''', tbl);
+            -- 原唯一索引只包含 short_uri，会阻止不同域名下创建同名短链接
+            FOR idx IN SELECT indexname
+                       FROM pg_indexes
+                       WHERE schemaname = current_schema()
+                         AND tablename = tbl
+                         AND indexdef LIKE 'CREATE UNIQUE INDEX%'
+                         AND indexdef LIKE '%short_uri%'
+                         AND indexdef NOT LIKE '%domain%'
+                LOOP
+                    EXECUTE format('DROP INDEX IF EXISTS %I', idx);
+                END LOOP;
+            -- 已删除的短链接不占用短链接
+            EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS %I ON %I ("domain", "short_uri") WHERE "delete_time" IS NULL',
+                           'idx_' || tbl || '_domain_short_uri', tbl);
+        END LOOP;
+
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "full_short_url" text', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."full_short_url" IS ''完整短链接''', tbl);
+        END LOOP;
+END
+$$;
+
+-- 域名数量有限且按域名查询，不参与分片
+CREATE TABLE IF NOT EXISTS "t_link_domain"
+(
+    "id"          BIGSERIAL PRIMARY KEY,
+    "host"        text    NOT NULL,
+    "owner"       text    NOT NULL,
+    "status"      text    NOT NULL DEFAULT 'unverified',
+    "use_tls"     boolean NOT NULL DEFAULT FALSE,
+    "create_time" timestamptz      DEFAULT CURRENT_TIMESTAMP,
+    "update_time" timestamptz      DEFAULT CURRENT_TIMESTAMP,
+    "delete_time" timestamptz,
+    "tenant_id"   text
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_t_link_domain_host" ON "t_link_domain" ("host");
+CREATE INDEX IF NOT EXISTS "idx_t_link_domain_owner" ON "t_link_domain" ("owner");
+COMMENT ON COLUMN "t_link_domain"."id" IS 'ID';
+COMMENT ON COLUMN "t_link_domain"."host" IS '域名';
+COMMENT ON COLUMN "t_link_domain"."owner" IS '注册域名的用户名';
+COMMENT ON COLUMN "t_link_domain"."status" IS '可选值:unverified,verified,failed';
+COMMENT ON COLUMN "t_link_domain"."use_tls" IS '是否通过 https 访问';
+COMMENT ON COLUMN "t_link_domain"."create_time" IS '创建时间';
+COMMENT ON COLUMN "t_link_domain"."update_time" IS '修改时间';
+COMMENT ON COLUMN "t_link_domain"."delete_time" IS '删除时间戳';
+COMMENT ON COLUMN "t_link_domain"."tenant_id" IS '租户ID';
@@ -0,0 +1,9 @@
+-- 自定义域名所有权验证 t_link_domain 由 link_domain.sql 创建
+
+ALTER TABLE "t_link_domain" ADD COLUMN IF NOT EXISTS "verify_token" text NOT NULL DEFAULT '';
+ALTER TABLE "t_link_domain" ADD COLUMN IF NOT EXISTS "checked_time" timestamptz;
+-- 通过验证后连续验证失败达到阈值才吊销域名
+ALTER TABLE "t_link_domain" ADD COLUMN IF NOT EXISTS "verify_failures" integer NOT NULL DEFAULT 0;
+COMMENT ON COLUMN "t_link_domain"."verify_token" IS '所有权验证令牌';
+COMMENT ON COLUMN "t_link_domain"."checked_time" IS '最近一次验证时间';
+COMMENT ON COLUMN "t_link_domain"."verify_failures" IS '连续验证失败次数';
@@ -0,0 +1,19 @@
+-- 短链接不可用时跳转的备用链接，短链接上为空时使用分组的默认备用链接
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+-- t_link_group_setting 由 link_url_params.sql 创建
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND (table_name ~ '^t_link(_[0-9]+)?$' OR table_name = 't_link_group_setting')
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "fallback_url" text', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."fallback_url" IS ''备用链接''', tbl);
+        END LOOP;
+END
+$$;
@@ -0,0 +1,22 @@
+-- 跳转目标最近一次健康检查的结果
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "health_status" integer NOT NULL DEFAULT 0', tbl);
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "health_latency" integer NOT NULL DEFAULT 0', tbl);
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "health_check_time" timestamptz', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."health_status" IS ''响应状态码 0：无法访问''', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."health_latency" IS ''响应耗时 单位：毫秒''', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."health_check_time" IS ''检查时间''', tbl);
+        END LOOP;
+END
+$$;
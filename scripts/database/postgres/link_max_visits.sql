@@ -0,0 +1,18 @@
+-- 短链接最大访问次数 达到次数后短链接失效，0 表示不限制
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "max_visits" integer NOT NULL DEFAULT 0', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."max_visits" IS ''最大访问次数 0：不限制''', tbl);
+        END LOOP;
+END
+$$;
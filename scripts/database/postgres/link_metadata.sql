@@ -0,0 +1,20 @@
+-- 创建短链接后异步获取的跳转目标页面分享信息
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "og_image" text', tbl);
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "og_description" text', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."og_image" IS ''分享图片''', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."og_description" IS ''页面描述''', tbl);
+        END LOOP;
+END
+$$;
@@ -0,0 +1,18 @@
+-- 短链接访问密码 保存哈希值，为空表示不需要密码
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "password" text', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."password" IS ''访问密码（哈希）''', tbl);
+        END LOOP;
+END
+$$;
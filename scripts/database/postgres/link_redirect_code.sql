@@ -0,0 +1,18 @@
+-- 短链接跳转状态码 0 表示使用 302
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "redirect_code" integer NOT NULL DEFAULT 0', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."redirect_code" IS ''跳转状态码 301/302/307/308''', tbl);
+        END LOOP;
+END
+$$;
@@ -0,0 +1,18 @@
+-- 短链接条件路由规则 JSON 数组，为空表示不按条件路由
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "routing_rules" jsonb', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."routing_rules" IS ''条件路由规则''', tbl);
+        END LOOP;
+END
+$$;
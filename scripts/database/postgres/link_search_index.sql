@@ -0,0 +1,27 @@
+-- 短链接标签和关键词搜索使用的索引
+-- 单表部署只需要为 t_link 创建，分表部署需要为 t_link_0 ~ t_link_15 分别创建
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "tags" jsonb NOT NULL DEFAULT ''[]''', tbl);
+            -- 标签筛选 tags @> ?
+            EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I USING gin ("tags")', 'idx_' || tbl || '_tags', tbl);
+            -- 全文检索 与 read.linkSearchCondition 中的表达式保持一致
+            EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I USING gin (to_tsvector(''simple'', COALESCE("desc", '''') || '' '' || "original_url" || '' '' || "short_uri"))',
+                           'idx_' || tbl || '_fts', tbl);
+            -- 模糊匹配 ILIKE
+            EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I USING gin ("desc" gin_trgm_ops, "original_url" gin_trgm_ops, "short_uri" gin_trgm_ops)',
+                           'idx_' || tbl || '_trgm', tbl);
+        END LOOP;
+END
+$$;
@@ -0,0 +1,47 @@
+-- 不同域名下可以存在相同的短链接，统计表以 domain + short_uri 区分短链接
+-- 空字符串表示默认域名，已有数据都属于默认域名
+
+DO
+$$
+DECLARE
+    tbl  TEXT;
+    cols TEXT;
+    idx  TEXT;
+BEGIN
+    -- 表名 => 唯一键中 domain 之后的列，与 LinkStatsRepository.SaveLinkStats 的 ON CONFLICT 保持一致
+    FOR tbl, cols IN SELECT t.name, t.cols
+                     FROM (VALUES ('t_link_access_stats', '"short_uri", "date", "hour"'),
+                                  ('t_link_stats_today', '"short_uri", "date"'),
+                                  ('t_link_locale_stats', '"short_uri", "date", "province"'),
+                                  ('t_link_os_stats', '"os", "short_uri", "date"'),
+                                  ('t_link_browser_stats', '"browser", "short_uri", "date"'),
+                                  ('t_link_device_stats', '"device", "short_uri", "date"'),
+                                  ('t_link_network_stats', '"network", "short_uri", "date"'),
+                                  ('t_link_variant_stats', '"short_uri", "date", "variant"')) AS t(name, cols)
+                              INNER JOIN information_schema.tables it
+                                         ON it.table_name = t.name AND it.table_schema = current_schema()
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "domain" varchar(128) NOT NULL DEFAULT ''''', tbl);
+            -- 原唯一索引不含 domain，会让不同域名下的同名短链接写入同一行
+            FOR idx IN SELECT indexname
+                       FROM pg_indexes
+                       WHERE schemaname = current_schema()
+                         AND tablename = tbl
+                         AND indexdef LIKE 'CREATE UNIQUE INDEX%'
+                         AND indexdef NOT LIKE '%(id)'
+                         AND indexdef NOT LIKE '%domain%'
+                LOOP
+                    EXECUTE format('DROP INDEX IF EXISTS %I', idx);
+                END LOOP;
+            EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS %I ON %I ("domain", %s)', 'idx_' || tbl || '_unique', tbl, cols);
+        END LOOP;
+
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name IN ('t_link_access_logs', 't_link_stats')
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "domain" varchar(128) NOT NULL DEFAULT ''''', tbl);
+        END LOOP;
+END
+$$;
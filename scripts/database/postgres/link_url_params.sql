@@ -0,0 +1,39 @@
+-- 跳转时对查询参数的处理 JSON 对象，短链接上为空时使用分组的默认配置
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "url_params" jsonb', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."url_params" IS ''查询参数处理''', tbl);
+        END LOOP;
+END
+$$;
+
+-- 分组归属于用户服务，这里只保存和短链接相关的分组默认配置，不参与分片
+CREATE TABLE IF NOT EXISTS "t_link_group_setting"
+(
+    "id"          BIGSERIAL PRIMARY KEY,
+    "gid"         text NOT NULL,
+    "url_params"  jsonb,
+    "create_time" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    "update_time" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    "delete_time" timestamptz,
+    "tenant_id"   text
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_t_link_group_setting_gid" ON "t_link_group_setting" ("gid");
+COMMENT ON COLUMN "t_link_group_setting"."id" IS 'ID';
+COMMENT ON COLUMN "t_link_group_setting"."gid" IS '分组标识';
+COMMENT ON COLUMN "t_link_group_setting"."url_params" IS '查询参数处理';
+COMMENT ON COLUMN "t_link_group_setting"."create_time" IS '创建时间';
+COMMENT ON COLUMN "t_link_group_setting"."update_time" IS '修改时间';
+COMMENT ON COLUMN "t_link_group_setting"."delete_time" IS '删除时间戳';
+COMMENT ON COLUMN "t_link_group_setting"."tenant_id" IS '租户ID';
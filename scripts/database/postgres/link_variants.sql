@@ -0,0 +1,45 @@
+-- 按权重分流的跳转目标和分流统计
+-- 单表部署只需要修改 t_link，分表部署需要修改 t_link_0 ~ t_link_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_link(_[0-9]+)?$'
+        LOOP
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "variants" jsonb', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."variants" IS ''分流目标''', tbl);
+        END LOOP;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS "t_link_variant_stats"
+(
+    "id"          SERIAL PRIMARY KEY,
+    "domain"      varchar(128) NOT NULL DEFAULT '',
+    "short_uri"   text         NOT NULL,
+    "date"        date         NOT NULL DEFAULT CURRENT_DATE,
+    "variant"     text         NOT NULL,
+    "pv"          integer,
+    "uv"          integer,
+    "create_time" timestamptz           DEFAULT CURRENT_TIMESTAMP,
+    "update_time" timestamptz           DEFAULT CURRENT_TIMESTAMP,
+    "delete_time" timestamptz
+);
+
+-- 与 LinkStatsRepository.SaveLinkStats 的 ON CONFLICT 保持一致
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_t_link_variant_stats_unique" ON "t_link_variant_stats" ("domain", "short_uri", "date", "variant");
+COMMENT ON COLUMN "t_link_variant_stats"."id" IS 'ID';
+COMMENT ON COLUMN "t_link_variant_stats"."domain" IS '自定义域名 空字符串表示默认域名';
+COMMENT ON COLUMN "t_link_variant_stats"."short_uri" IS '短链接';
+COMMENT ON COLUMN "t_link_variant_stats"."date" IS '日期';
+COMMENT ON COLUMN "t_link_variant_stats"."variant" IS '分流名称';
+COMMENT ON COLUMN "t_link_variant_stats"."pv" IS '访问量';
+COMMENT ON COLUMN "t_link_variant_stats"."uv" IS '独立访客数';
+COMMENT ON COLUMN "t_link_variant_stats"."create_time" IS '创建时间';
+COMMENT ON COLUMN "t_link_variant_stats"."update_time" IS '修改时间';
+COMMENT ON COLUMN "t_link_variant_stats"."delete_time" IS '删除时间';
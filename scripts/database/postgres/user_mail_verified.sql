@@ -0,0 +1,22 @@
+-- 邮箱验证状态 邮箱未验证的用户不能创建短链接
+-- 单表部署只需要修改 t_user，分表部署需要修改 t_user_0 ~ t_user_15
+
+DO
+$$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN SELECT table_name
+               FROM information_schema.tables
+               WHERE table_schema = current_schema()
+                 AND table_name ~ '^t_user(_[0-9]+)?$'
+        LOOP
+            -- 已有用户视为已验证，避免升级后无法创建短链接，新注册的用户默认未验证
+            EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "mail_verified" boolean NOT NULL DEFAULT TRUE', tbl);
+            EXECUTE format('ALTER TABLE %I ALTER COLUMN "mail_verified" SET DEFAULT FALSE', tbl);
+            EXECUTE format('COMMENT ON COLUMN %I."mail_verified" IS ''邮箱是否已验证''', tbl);
+            -- 忘记密码时根据邮箱查询用户
+            EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I ("mail")', 'idx_' || tbl || '_mail', tbl);
+        END LOOP;
+END
+$$;